@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/stackus/todos/internal/backup"
+	"github.com/stackus/todos/internal/scheduler"
+)
+
+// adminClient calls the admin endpoints of a running server
+type adminClient struct {
+	server string
+	token  string
+	client *http.Client
+}
+
+func adminFlags(fs *flag.FlagSet) *adminClient {
+	c := &adminClient{client: &http.Client{Timeout: 5 * time.Minute}}
+	fs.StringVar(&c.server, "server", "http://localhost:3000", "URL of the running server")
+	fs.StringVar(&c.token, "token", os.Getenv("TODOS_ADMIN_TOKEN"), "admin token of the server")
+	return c
+}
+
+func (c *adminClient) do(ctx context.Context, method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.server, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// runBackup takes backups of a running server
+//
+//	todos backup [-server URL] [-token TOKEN] [-out FILE | -dir DIR -keep N] [-every DURATION]
+//
+// A backup is written to the file given by -out, or otherwise to a new file in -dir, keeping the latest -keep
+// backups there. With -every, a backup is taken at that interval until the command is stopped.
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	client := adminFlags(fs)
+	out := fs.String("out", "", "file to write the backup to, instead of a new file in the backup directory")
+	dir := fs.String("dir", "data/backups", "directory keeping the backups")
+	keep := fs.Int("keep", 0, "how many backups to keep in the backup directory; 0 keeps them all")
+	every := fs.Duration("every", 0, "take a backup at this interval until stopped")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out != "" && *every > 0 {
+		return errors.New("-out cannot be used with -every, as each backup would replace the last")
+	}
+
+	backups := backup.NewDir(*dir, *keep)
+	take := func(ctx context.Context) error {
+		data, err := client.do(ctx, http.MethodGet, "/admin/backup", nil)
+		if err != nil {
+			return err
+		}
+		// the backup is checked as it arrives, so that a broken one is never kept in place of a good one
+		b, manifest, err := backup.Read(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		path := *out
+		if path == "" {
+			path, err = backups.Save(b)
+		} else {
+			err = backup.WriteFile(path, b)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", path, describe(manifest))
+		return nil
+	}
+
+	if *every <= 0 {
+		return take(context.Background())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	jobs := scheduler.New(log.New(os.Stderr, "", log.LstdFlags))
+	jobs.Every(*every, "taking a backup", take)
+	jobs.Run(ctx)
+	return nil
+}
+
+// runRestore checks a backup and restores it into a running server
+//
+//	todos restore [-server URL] [-token TOKEN] [-check] FILE
+//
+// The todos, users and saved views of the backup replace those with the same IDs; anything else the server
+// holds is kept. With -check, the backup is only checked.
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	client := adminFlags(fs)
+	check := fs.Bool("check", false, "only check the backup")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("restore needs the backup file to restore")
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	_, manifest, err := backup.Read(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+	fmt.Printf("%s: %s\n", fs.Arg(0), describe(manifest))
+	if *check {
+		return nil
+	}
+
+	if _, err = client.do(context.Background(), http.MethodPost, "/admin/restore", bytes.NewReader(data)); err != nil {
+		return err
+	}
+	fmt.Printf("restored into %s\n", client.server)
+	return nil
+}
+
+func describe(m backup.Manifest) string {
+	return fmt.Sprintf("%d todos, %d users and %d views taken %s", m.Todos, m.Users, m.Views, m.TakenAt.Format(time.RFC3339))
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -15,9 +17,28 @@ import (
 	"github.com/go-chi/cors"
 
 	"github.com/stackus/todos/internal/assets"
+	"github.com/stackus/todos/internal/blob"
+	"github.com/stackus/todos/internal/csp"
 	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/eventstore"
+	"github.com/stackus/todos/internal/features/attachments"
+	"github.com/stackus/todos/internal/features/backups"
+	"github.com/stackus/todos/internal/features/caldav"
+	"github.com/stackus/todos/internal/features/focus"
+	"github.com/stackus/todos/internal/features/habits"
 	"github.com/stackus/todos/internal/features/home"
+	"github.com/stackus/todos/internal/features/inbox"
+	"github.com/stackus/todos/internal/features/offline"
+	"github.com/stackus/todos/internal/features/timetracking"
 	"github.com/stackus/todos/internal/features/todos"
+	"github.com/stackus/todos/internal/features/transfer"
+	"github.com/stackus/todos/internal/features/users"
+	"github.com/stackus/todos/internal/features/views"
+	"github.com/stackus/todos/internal/fulltext"
+	"github.com/stackus/todos/internal/health"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/metrics"
+	"github.com/stackus/todos/internal/scheduler"
 )
 
 type Config struct {
@@ -26,9 +47,63 @@ type Config struct {
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
 	Environment     string
+	Attachments     AttachmentsConfig
+	Store           StoreConfig
+	// ShutdownDelay is how long /readyz fails before the server stops taking requests, so load balancers can
+	// take it out of rotation first
+	ShutdownDelay time.Duration
+	// AdminToken is the bearer token of the admin endpoints, such as backup and restore; they are off without one
+	AdminToken string
+	// SessionKey signs the cookies telling users apart; without one a key is made up that lasts until the server
+	// restarts, when everyone is given a new identity
+	SessionKey string
+}
+
+// StoreConfig is how the todos are kept
+type StoreConfig struct {
+	// Kind is "memory" to keep the todos only while the server runs, or "events" to keep them in an event log
+	Kind string
+	Dir  string
+	// SnapshotEvery is how often the event log is snapshotted so that less of it is played back on startup
+	SnapshotEvery time.Duration
+}
+
+// AttachmentsConfig is where files attached to todos are kept and how large they may be
+type AttachmentsConfig struct {
+	Dir     string
+	MaxSize int64
+	// S3Endpoint, when set, keeps the files in a bucket of an S3-compatible object store instead of Dir
+	S3Endpoint string
+	S3Bucket   string
+	S3Region   string
 }
 
 func main() {
+	// Run a subcommand; serving is what the server does without one, as it did before there were any
+	var command string
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	var err error
+	switch command {
+	case "backup":
+		err = runBackup(os.Args[2:])
+	case "restore":
+		err = runRestore(os.Args[2:])
+	case "serve":
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		serve()
+	default:
+		serve()
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func serve() {
 	// Load configuration
 	cfg := loadConfig()
 
@@ -37,10 +112,13 @@ func main() {
 
 	// Create router with middleware
 	router := chi.NewRouter()
+	stats := metrics.New()
+	status := health.New(health.ReadVersion())
 
 	// Add middleware
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(stats.Middleware)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Timeout(cfg.ReadTimeout))
@@ -49,28 +127,79 @@ func main() {
 	corsMiddleware := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 	router.Use(corsMiddleware.Handler)
 
-	// Initialize domain
-	list := domain.NewTodos()
+	// Tell the people using the app apart
+	sessionKey := []byte(cfg.SessionKey)
+	if len(sessionKey) == 0 {
+		var err error
+		if sessionKey, err = identity.NewKey(); err != nil {
+			logger.Fatal(err)
+		}
+		logger.Print("no session key given; users are given new identities when the server restarts")
+	}
+	router.Use(identity.New(sessionKey).Middleware)
 
-	// Add some sample todos if in development
-	if cfg.Environment == "development" {
+	// Only let the pages run the scripts they were served with
+	router.Use(csp.Middleware)
+
+	// Initialize domain
+	list, store, err := openTodos(cfg.Store, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	stats.CountTodos(list)
+	list = stats.TodoRepository(list)
+	// Index the todos for searching as every feature changes them
+	list = fulltext.NewIndex().TodoRepository(list)
+	people := domain.NewUsers()
+	notifications := domain.NewNotifications()
+	focusSessions := domain.NewFocusSessions()
+	savedViews := domain.NewViews()
+
+	// Add some sample todos if in development, unless there are todos kept from before
+	if cfg.Environment == "development" && len(list.All()) == 0 {
 		addSampleTodos(list)
 	}
 
 	// Initialize services
-	todoService := todos.NewService(list)
-	homeService := home.NewService(list)
+	attachmentLimits := attachments.DefaultLimits
+	attachmentLimits.MaxSize = cfg.Attachments.MaxSize
+	attachmentService := attachments.NewService(list, newBlobStore(cfg.Attachments), attachmentLimits)
+	todoService := todos.NewService(list, people, notifications, stats.NotificationService(todos.NewNoopNotificationService()), attachmentService)
+	homeService := home.NewService(list, savedViews)
+	caldavService := caldav.NewService(todoService)
+	transferService := transfer.NewService(list)
+	userService := users.NewService(people, list)
+	inboxService := inbox.NewService(notifications)
+	timeService := timetracking.NewService(list, people)
+	focusService := focus.NewService(list, focusSessions)
+	habitService := habits.NewService(list)
+	viewService := views.NewService(savedViews, list)
+	backupService := backups.NewService(list, people, savedViews)
+	syncService := offline.NewService(list, todoService)
 
 	// Mount routes
 	home.Mount(router, home.NewHandler(homeService))
 	todos.Mount(router, todos.NewHandler(todoService))
+	caldav.Mount(router, caldav.NewHandler(caldavService))
+	transfer.Mount(router, transfer.NewHandler(transferService))
+	users.Mount(router, users.NewHandler(userService))
+	inbox.Mount(router, inbox.NewHandler(inboxService))
+	attachments.Mount(router, attachments.NewHandler(attachmentService))
+	timetracking.Mount(router, timetracking.NewHandler(timeService))
+	focus.Mount(router, focus.NewHandler(focusService))
+	habits.Mount(router, habits.NewHandler(habitService))
+	views.Mount(router, views.NewHandler(viewService, homeService))
+	backups.Mount(router, backups.NewHandler(backupService, cfg.AdminToken))
+	offline.Mount(router, offline.NewHandler(syncService))
+	metrics.Mount(router, stats)
+	health.Mount(router, status)
 	assets.Mount(router)
 
 	// Create server
@@ -84,14 +213,64 @@ func main() {
 	// Server run context
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
 
+	// Run the background jobs until the server stops
+	jobs := scheduler.New(logger)
+	// Open recurring todos again as each of their periods begins
+	jobs.Every(time.Minute, "rolling over recurring todos", func(ctx context.Context) error {
+		reopened, err := habitService.Rollover(ctx)
+		for _, todo := range reopened {
+			logger.Printf("recurring todo %s is due again", todo.ID)
+		}
+		return err
+	})
+	// Bring back snoozed todos as their start dates arrive
+	jobs.Every(time.Minute, "resurfacing snoozed todos", func(ctx context.Context) error {
+		resurfaced, err := todoService.Resurface(ctx)
+		for _, todo := range resurfaced {
+			logger.Printf("snoozed todo %s is back", todo.ID)
+		}
+		return err
+	})
+	if store != nil {
+		// Write the events that could not be written as the changes were made, and snapshot the log now and then
+		jobs.Every(5*time.Second, "writing todo events", func(context.Context) error {
+			return store.Flush()
+		})
+		jobs.Every(cfg.Store.SnapshotEvery, "snapshotting todos", func(context.Context) error {
+			return store.Snapshot()
+		})
+	}
+	go jobs.Run(serverCtx)
+
+	// The server is ready while the todos can be kept and the background jobs run; the event log has been
+	// played back by the time the store is open. There is no migrations check, as there are no migrations:
+	// nothing the server keeps has a schema, and backups carry the format they were written in.
+	status.Check("repository", func(context.Context) error {
+		if store != nil {
+			return store.Check()
+		}
+		return nil
+	})
+	status.Check("scheduler", func(context.Context) error {
+		if !jobs.Running() {
+			return errors.New("not running")
+		}
+		return nil
+	})
+
 	// Listen for syscall signals for process to interrupt/quit
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
 		<-sig
 
+		// Fail readiness first, giving load balancers time to stop sending requests
+		status.Shutdown()
+		time.Sleep(cfg.ShutdownDelay)
+
 		// Shutdown signal with grace period of 30 seconds
-		shutdownCtx, _ := context.WithTimeout(serverCtx, cfg.ShutdownTimeout)
+		shutdownCtx, cancel := context.WithTimeout(serverCtx, cfg.ShutdownTimeout)
+		defer cancel()
 
 		go func() {
 			<-shutdownCtx.Done()
@@ -105,12 +284,17 @@ func main() {
 		if err != nil {
 			logger.Fatal(err)
 		}
+		if store != nil {
+			if err = store.Close(); err != nil {
+				logger.Printf("closing the todo store: %v", err)
+			}
+		}
 		serverStopCtx()
 	}()
 
 	// Run the server
 	logger.Printf("Server is running on http://localhost%s", cfg.Port)
-	err := server.ListenAndServe()
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		logger.Fatal(err)
 	}
@@ -127,49 +311,95 @@ func loadConfig() Config {
 	flag.DurationVar(&cfg.ReadTimeout, "read-timeout", 30*time.Second, "read timeout")
 	flag.DurationVar(&cfg.WriteTimeout, "write-timeout", 30*time.Second, "write timeout")
 	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "shutdown timeout")
+	flag.DurationVar(&cfg.ShutdownDelay, "shutdown-delay", 5*time.Second, "how long /readyz fails before the server stops taking requests")
+	flag.StringVar(&cfg.Attachments.Dir, "attachments-dir", "data/attachments", "directory keeping the files attached to todos")
+	flag.Int64Var(&cfg.Attachments.MaxSize, "attachments-max-size", attachments.DefaultLimits.MaxSize, "largest file in bytes that can be attached to a todo")
+	flag.StringVar(&cfg.Attachments.S3Endpoint, "s3-endpoint", "", "S3-compatible endpoint keeping attached files instead of the attachments directory")
+	flag.StringVar(&cfg.Attachments.S3Bucket, "s3-bucket", "todos", "bucket keeping attached files")
+	flag.StringVar(&cfg.Attachments.S3Region, "s3-region", "us-east-1", "region of the bucket")
+	flag.StringVar(&cfg.Store.Kind, "store", "memory", "how the todos are kept: memory or events")
+	flag.StringVar(&cfg.Store.Dir, "events-dir", "data/events", "directory keeping the event log of the todos")
+	flag.DurationVar(&cfg.Store.SnapshotEvery, "snapshot-every", time.Hour, "how often the event log is snapshotted")
+	flag.StringVar(&cfg.AdminToken, "admin-token", os.Getenv("TODOS_ADMIN_TOKEN"), "bearer token of the admin endpoints; they are off without one")
+	flag.StringVar(&cfg.SessionKey, "session-key", os.Getenv("TODOS_SESSION_KEY"), "key signing the cookies telling users apart; a random one is made up without it")
 	flag.Parse()
 
 	return cfg
 }
 
-func addSampleTodos(list *domain.Todos) {
+// openTodos keeps the todos in memory, or in an event log when one is configured, which is also returned
+func openTodos(cfg StoreConfig, logger *log.Logger) (domain.TodoRepository, *eventstore.Store, error) {
+	switch cfg.Kind {
+	case "memory":
+		return domain.NewTodos(), nil, nil
+	case "events":
+		store, err := eventstore.Open(cfg.Dir, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening the event log: %w", err)
+		}
+		return store, store, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown store %q", cfg.Kind)
+	}
+}
+
+// newBlobStore keeps blobs in an S3-compatible object store when one is configured, and on disk otherwise
+//
+// The credentials for the object store are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func newBlobStore(cfg AttachmentsConfig) blob.Store {
+	if cfg.S3Endpoint == "" {
+		return blob.NewFileStore(cfg.Dir)
+	}
+	return blob.NewS3Store(blob.S3Config{
+		Endpoint:        cfg.S3Endpoint,
+		Bucket:          cfg.S3Bucket,
+		Region:          cfg.S3Region,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+	})
+}
+
+func addSampleTodos(list domain.TodoRepository) {
 	// Add some sample todos with the new features
-	todo1 := list.Add("Bake a cake")
+	todo1 := domain.NewTodo("Bake a cake")
 	todo1.DueDate = ptr(time.Now().Add(24 * time.Hour))
 	todo1.Priority = domain.PriorityHigh
 	todo1.Category = "Cooking"
 	todo1.Tags = []string{"baking", "dessert"}
 
-	todo2 := list.Add("Feed the cat")
+	todo2 := domain.NewTodo("Feed the cat")
 	todo2.DueDate = ptr(time.Now().Add(12 * time.Hour))
 	todo2.Priority = domain.PriorityMedium
 	todo2.Category = "Pets"
 	todo2.Tags = []string{"pet care", "daily"}
+	todo2.SetRecurring("daily", nil)
 
-	todo3 := list.Add("Take out the trash")
+	todo3 := domain.NewTodo("Take out the trash")
 	todo3.DueDate = ptr(time.Now().Add(6 * time.Hour))
 	todo3.Priority = domain.PriorityLow
 	todo3.Category = "Household"
 	todo3.Tags = []string{"chores", "daily"}
 
 	// Add a recurring todo
-	todo4 := list.Add("Weekly team meeting")
+	todo4 := domain.NewTodo("Weekly team meeting")
 	todo4.SetRecurring("weekly", ptr(time.Now().AddDate(0, 1, 0)))
 	todo4.Category = "Work"
 	todo4.Tags = []string{"meeting", "team"}
 
 	// Add a todo with subtasks
-	todo5 := list.Add("Plan vacation")
+	todo5 := domain.NewTodo("Plan vacation")
 	todo5.Category = "Personal"
 	todo5.Tags = []string{"travel", "planning"}
 
-	subtask1 := list.Add("Book flights")
-	subtask2 := list.Add("Reserve hotel")
-	subtask3 := list.Add("Create itinerary")
+	subtask1 := domain.NewTodo("Book flights")
+	subtask2 := domain.NewTodo("Reserve hotel")
+	subtask3 := domain.NewTodo("Create itinerary")
 
 	todo5.AddSubtask(subtask1)
 	todo5.AddSubtask(subtask2)
 	todo5.AddSubtask(subtask3)
+
+	list.Save(todo1, todo2, todo3, todo4, todo5, subtask1, subtask2, subtask3)
 }
 
 // Helper function to create a pointer to a time.Time
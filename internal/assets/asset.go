@@ -0,0 +1,106 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// asset is an embedded asset along with what is worked out from it once, as the app starts
+type asset struct {
+	// name is where the asset is under dist, and hashed the same with the fingerprint of its content in it
+	name   string
+	hashed string
+	// fingerprint is the start of the SHA-256 hash of the content, also used for its ETag
+	fingerprint string
+	contentType string
+	data        []byte
+	// encoded are the compressed copies of the asset by content coding, kept only when they are smaller
+	encoded map[string][]byte
+	// integrity is the Subresource Integrity hash of the asset
+	integrity string
+}
+
+// assets are the embedded assets by name and by hashed name; a hashed name never changes what it serves,
+// so those are cached for good
+var assets, hashed = index()
+
+func index() (map[string]*asset, map[string]*asset) {
+	byName, byHashed := make(map[string]*asset), make(map[string]*asset)
+	err := fs.WalkDir(Assets, "dist", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(Assets, p)
+		if err != nil {
+			return err
+		}
+		a, err := newAsset(strings.TrimPrefix(p, "dist/"), data)
+		if err != nil {
+			return err
+		}
+		byName[a.name], byHashed[a.hashed] = a, a
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return byName, byHashed
+}
+
+func newAsset(name string, data []byte) (*asset, error) {
+	sum := sha256.Sum256(data)
+	sri := sha512.Sum384(data)
+	a := &asset{
+		name:        name,
+		fingerprint: hex.EncodeToString(sum[:])[:12],
+		contentType: contentType(name, data),
+		data:        data,
+		integrity:   "sha384-" + base64.StdEncoding.EncodeToString(sri[:]),
+	}
+	a.hashed = hashedName(name, a.fingerprint)
+
+	var err error
+	if compressible(a.contentType) {
+		a.encoded, err = compress(data)
+	}
+	return a, err
+}
+
+// hashedName puts the fingerprint before the extension: vendor/htmx.min.js becomes vendor/htmx.min.<hash>.js
+func hashedName(name, fingerprint string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + fingerprint + ext
+}
+
+// etag returns the ETag of the asset as sent with the content coding; each coding is a different
+// representation, so each has a tag of its own
+func (a *asset) etag(coding string) string {
+	if coding == "" {
+		return `"` + a.fingerprint + `"`
+	}
+	return `"` + a.fingerprint + "-" + coding + `"`
+}
+
+// Path returns the URL path of the embedded asset, named as it is under dist, with its fingerprint in it
+//
+// An asset that is not embedded keeps its name, so the page still asks for it.
+func Path(name string) string {
+	if a, ok := assets[name]; ok {
+		return "/dist/" + a.hashed
+	}
+	return "/dist/" + name
+}
+
+// Integrity returns the Subresource Integrity hash of the embedded asset, or an empty string when it is not
+// embedded
+func Integrity(name string) string {
+	if a, ok := assets[name]; ok {
+		return a.integrity
+	}
+	return ""
+}
@@ -9,3 +9,90 @@ htmx.onLoad(function (content) {
     });
   }
 });
+
+// count down the time left of a focus phase, letting the page know when it is up
+setInterval(function () {
+  var countdowns = document.querySelectorAll("[data-countdown]");
+  for (var i = 0; i < countdowns.length; i++) {
+    var countdown = countdowns[i];
+    var left = Math.max(0, Math.round((Date.parse(countdown.dataset.countdown) - Date.now()) / 1000));
+    countdown.textContent = Math.floor(left / 60) + ":" + String(left % 60).padStart(2, "0");
+    if (left === 0 && !countdown.dataset.ended) {
+      countdown.dataset.ended = "true";
+      htmx.trigger(countdown, "phaseEnded");
+    }
+  }
+}, 1000);
+
+// a todo changed while it was being edited comes back as 409 Conflict with a screen to merge the two
+document.addEventListener("htmx:beforeSwap", function (event) {
+  if (event.detail.xhr.status === 409) {
+    event.detail.shouldSwap = true;
+    event.detail.isError = false;
+  }
+});
+
+// clicking a link in a todo's description follows the link rather than completing the todo; this is done
+// here as the Content-Security-Policy keeps htmx from evaluating trigger filters
+document.addEventListener("click", function (event) {
+  if (event.target.closest("a") && event.target.closest("[data-toggle-todo]")) {
+    event.stopPropagation();
+  }
+}, true);
+
+// the service worker keeps the pages working offline, queueing the changes made until the network is back
+if ("serviceWorker" in navigator) {
+  navigator.serviceWorker.register("/sw.js");
+
+  navigator.serviceWorker.addEventListener("message", function (event) {
+    switch (event.data.type) {
+      case "queued":
+        offlineBanner(event.data.count);
+        break;
+      case "replayed":
+        // the pages show what was saved before the queued changes were sent
+        offlineBanner(event.data.count);
+        if (navigator.onLine && event.data.count === 0) {
+          location.reload();
+        }
+        break;
+    }
+  });
+
+  // the scripts and styles have fingerprints in their names, so the service worker is told which to keep
+  navigator.serviceWorker.ready.then(function (registration) {
+    var urls = [];
+    document.querySelectorAll("script[src], link[rel=stylesheet], link[rel=icon]").forEach(function (el) {
+      urls.push(el.src || el.href);
+    });
+    registration.active.postMessage({ type: "cache", urls: urls });
+  });
+
+  window.addEventListener("online", function () {
+    navigator.serviceWorker.ready.then(function (registration) {
+      registration.active.postMessage("replay");
+    });
+  });
+}
+
+var offlineQueued = 0;
+
+// offlineBanner shows the banner while offline or while changes wait to be sent
+function offlineBanner(queued) {
+  if (queued !== undefined) {
+    offlineQueued = queued;
+  }
+  var banner = document.getElementById("offline-banner");
+  if (!banner) {
+    return;
+  }
+  banner.hidden = navigator.onLine && offlineQueued === 0;
+  document.getElementById("offline-queued").textContent = offlineQueued === 0 ? ""
+    : offlineQueued === 1 ? "1 change will be saved once you are back online."
+    : offlineQueued + " changes will be saved once you are back online.";
+  banner.firstChild.textContent = navigator.onLine ? "Saving… " : "You are offline. ";
+}
+
+window.addEventListener("online", function () { offlineBanner(); });
+window.addEventListener("offline", function () { offlineBanner(); });
+document.addEventListener("DOMContentLoaded", function () { offlineBanner(); });
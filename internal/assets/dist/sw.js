@@ -0,0 +1,281 @@
+// The service worker keeps the app working offline: it serves the shell and the pages last seen from its
+// caches when the network is down, and queues form posts to send once the network is back.
+var VERSION = "v1";
+var SHELL_CACHE = "todos-shell-" + VERSION;
+var PAGE_CACHE = "todos-pages-" + VERSION;
+var QUEUE_DB = "todos-offline";
+var QUEUE_STORE = "requests";
+
+// the scripts and styles have fingerprints in their names, so the pages tell the service worker which they use
+var SHELL = [
+  "/",
+  "/dist/manifest.webmanifest",
+];
+
+self.addEventListener("install", function (event) {
+  event.waitUntil(
+    caches.open(SHELL_CACHE).then(function (cache) {
+      return cache.addAll(SHELL);
+    }).then(function () {
+      return self.skipWaiting();
+    })
+  );
+});
+
+self.addEventListener("activate", function (event) {
+  event.waitUntil(
+    caches.keys().then(function (keys) {
+      return Promise.all(keys.filter(function (key) {
+        return key !== SHELL_CACHE && key !== PAGE_CACHE;
+      }).map(function (key) {
+        return caches.delete(key);
+      }));
+    }).then(function () {
+      return self.clients.claim();
+    }).then(replay)
+  );
+});
+
+self.addEventListener("fetch", function (event) {
+  var request = event.request;
+  var url = new URL(request.url);
+
+  if (request.method !== "GET") {
+    if (queueable(request, url)) {
+      event.respondWith(sendOrQueue(request));
+    }
+    return;
+  }
+  if (url.origin !== self.location.origin) {
+    return;
+  }
+  // the API and the live feeds are never served from a cache
+  if (url.pathname.indexOf("/api/") === 0 || request.headers.get("Accept") === "text/event-stream") {
+    return;
+  }
+  if (url.pathname.indexOf("/dist/") === 0) {
+    event.respondWith(cacheFirst(request));
+    return;
+  }
+  event.respondWith(networkFirst(request));
+});
+
+// the page asks for the queue to be sent as soon as it sees the network come back, and for the assets it
+// uses to be kept
+self.addEventListener("message", function (event) {
+  if (event.data === "replay") {
+    event.waitUntil(replay());
+  } else if (event.data.type === "cache") {
+    event.waitUntil(keep(event.data.urls));
+  }
+});
+
+// keep caches the assets a page uses, dropping those of earlier versions of the app
+function keep(urls) {
+  return caches.open(SHELL_CACHE).then(function (cache) {
+    return cache.keys().then(function (requests) {
+      return Promise.all(requests.filter(function (request) {
+        var path = new URL(request.url).pathname;
+        return path.indexOf("/dist/") === 0 && SHELL.indexOf(path) === -1 && urls.indexOf(request.url) === -1;
+      }).map(function (request) {
+        return cache.delete(request);
+      }));
+    }).then(function () {
+      return Promise.all(urls.map(function (url) {
+        return cache.match(url).then(function (cached) {
+          return cached || cache.add(url);
+        });
+      }));
+    });
+  }).catch(function () {});
+}
+
+self.addEventListener("sync", function (event) {
+  if (event.tag === "replay") {
+    event.waitUntil(replay());
+  }
+});
+
+function cacheFirst(request) {
+  return caches.match(request).then(function (cached) {
+    var fetched = fetch(request).then(function (response) {
+      if (response.ok) {
+        var copy = response.clone();
+        caches.open(SHELL_CACHE).then(function (cache) {
+          cache.put(request, copy);
+        });
+      }
+      return response;
+    });
+    return cached || fetched;
+  });
+}
+
+// pageKey keeps the partials HTMX asks for apart from the whole pages at the same URL
+function pageKey(request) {
+  if (request.headers.get("HX-Request") !== "true") {
+    return request.url;
+  }
+  var url = new URL(request.url);
+  url.searchParams.set("_hx", "1");
+  return url.toString();
+}
+
+// networkFirst fetches pages from the server, keeping the last copy of each to show while offline
+function networkFirst(request) {
+  var key = pageKey(request);
+  return fetch(request).then(function (response) {
+    if (response.ok) {
+      var copy = response.clone();
+      caches.open(PAGE_CACHE).then(function (cache) {
+        cache.put(key, copy);
+      });
+    }
+    return response;
+  }).catch(function () {
+    return caches.match(key, { cacheName: PAGE_CACHE }).then(function (cached) {
+      if (cached) {
+        return cached;
+      }
+      if (request.mode === "navigate") {
+        return caches.match("/");
+      }
+      return new Response("", { status: 503, statusText: "Offline" });
+    });
+  });
+}
+
+// queueable tells the form posts of the pages apart from the rest, which are left to fail offline: the API,
+// CalDAV clients and uploads neither expect a queued answer nor could be replayed from a text body
+function queueable(request, url) {
+  if (url.origin !== self.location.origin) {
+    return false;
+  }
+  var path = url.pathname;
+  if (path.indexOf("/api/") === 0 || path.indexOf("/caldav/") === 0 || /\/attachments(\/|$)/.test(path)) {
+    return false;
+  }
+  var type = request.headers.get("Content-Type") || "";
+  return type.split(";")[0].trim().toLowerCase() === "application/x-www-form-urlencoded";
+}
+
+// sendOrQueue sends a form post, queueing it when the network is down
+function sendOrQueue(request) {
+  var queued = request.clone();
+  return fetch(request).catch(function () {
+    return save(queued).then(function (count) {
+      notify({ type: "queued", count: count });
+      if (self.registration.sync) {
+        self.registration.sync.register("replay").catch(function () {});
+      }
+      return queuedResponse(queued);
+    });
+  });
+}
+
+// queuedResponse answers a queued post: HTMX is told to leave the page as it is, and forms go back to the
+// page they were sent from
+function queuedResponse(request) {
+  if (request.headers.get("HX-Request") === "true") {
+    return new Response("", {
+      status: 202,
+      headers: { "HX-Reswap": "none", "HX-Trigger": "queuedOffline" },
+    });
+  }
+  return Response.redirect(request.referrer || "/", 303);
+}
+
+function save(request) {
+  return request.text().then(function (body) {
+    var headers = {};
+    request.headers.forEach(function (value, name) {
+      headers[name] = value;
+    });
+    return withStore("readwrite", function (store) {
+      store.add({ url: request.url, method: request.method, headers: headers, body: body, at: Date.now() });
+      return store.count();
+    });
+  });
+}
+
+var replaying = null;
+
+// replay sends the queued posts in the order they were made, stopping at the first that cannot be sent
+function replay() {
+  if (replaying) {
+    return replaying;
+  }
+  replaying = all().then(function (entries) {
+    var sent = 0;
+    var next = function (i) {
+      if (i === entries.length) {
+        return Promise.resolve();
+      }
+      var entry = entries[i];
+      return fetch(entry.url, { method: entry.method, headers: entry.headers, body: entry.body })
+        .then(function () {
+          // the server has had its say; a post it turns down would only be turned down again
+          sent++;
+          return remove(entry.id).then(function () {
+            return next(i + 1);
+          });
+        });
+    };
+    return next(0).catch(function () {}).then(function () {
+      return all();
+    }).then(function (left) {
+      if (sent > 0) {
+        notify({ type: "replayed", sent: sent, count: left.length });
+      }
+    });
+  }).finally(function () {
+    replaying = null;
+  });
+  return replaying;
+}
+
+function notify(message) {
+  return self.clients.matchAll({ type: "window" }).then(function (clients) {
+    clients.forEach(function (client) {
+      client.postMessage(message);
+    });
+  });
+}
+
+function all() {
+  return withStore("readonly", function (store) {
+    return store.getAll();
+  });
+}
+
+function remove(id) {
+  return withStore("readwrite", function (store) {
+    return store.delete(id);
+  });
+}
+
+// withStore runs f in a transaction on the queue, resolving to the result of the request it returns
+function withStore(mode, f) {
+  return new Promise(function (resolve, reject) {
+    var open = indexedDB.open(QUEUE_DB, 1);
+    open.onupgradeneeded = function () {
+      open.result.createObjectStore(QUEUE_STORE, { keyPath: "id", autoIncrement: true });
+    };
+    open.onerror = function () {
+      reject(open.error);
+    };
+    open.onsuccess = function () {
+      var db = open.result;
+      var tx = db.transaction(QUEUE_STORE, mode);
+      var result = f(tx.objectStore(QUEUE_STORE));
+      tx.oncomplete = function () {
+        db.close();
+        resolve(result.result);
+      };
+      tx.onerror = function () {
+        db.close();
+        reject(tx.error);
+      };
+    };
+  });
+}
@@ -1,8 +1,17 @@
+// Package assets serves the scripts, styles and images of the app from the files embedded in it
+//
+// Each asset is fingerprinted, compressed with brotli and gzip, and given its ETag and MIME type once, as
+// the app starts. Pages link to the assets with Path, which puts the fingerprint in the name; those URLs
+// change with the content, so they are cached for good, while the plain names are checked on each use.
 package assets
 
 import (
+	"bytes"
 	"embed"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -10,14 +19,68 @@ import (
 //go:embed all:dist
 var Assets embed.FS
 
-// ServeAssets mount the embedded assets to an HTTP server
+const (
+	// immutable is the caching of assets asked for by their hashed names
+	immutable = "public, max-age=31536000, immutable"
+	// revalidate is the caching of assets asked for by their plain names, which change as the app does
+	revalidate = "no-cache"
+)
+
+// Mount mounts the embedded assets under /dist, and the service worker at /sw.js
 func Mount(r chi.Router) {
 	r.Route("/dist", func(r chi.Router) {
-		r.Use(func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				next.ServeHTTP(w, r)
-			})
-		})
-		r.Handle("/*", http.FileServer(http.FS(Assets)))
+		r.Get("/*", serveDist)
+		r.Head("/*", serveDist)
 	})
+	// the service worker controls only the pages at or below where it is served from, so it is served from
+	// the root; it is never cached without checking so that browsers pick up a new one straight away
+	r.Get("/sw.js", serveServiceWorker)
+	r.Head("/sw.js", serveServiceWorker)
+}
+
+func serveDist(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(chi.URLParam(r, "*"), "/")
+	if a, ok := hashed[name]; ok {
+		serve(w, r, a, immutable)
+		return
+	}
+	if a, ok := assets[name]; ok {
+		serve(w, r, a, revalidate)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func serveServiceWorker(w http.ResponseWriter, r *http.Request) {
+	a, ok := assets["sw.js"]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	serve(w, r, a, revalidate)
+}
+
+// serve sends the asset compressed with the best coding the request accepts, answering conditional and
+// range requests along the way
+func serve(w http.ResponseWriter, r *http.Request, a *asset, cacheControl string) {
+	h := w.Header()
+	coding := negotiate(a, r.Header.Get("Accept-Encoding"))
+	data := a.data
+	if coding != "" {
+		// ServeContent leaves the length of encoded content unset, as a range of it would not be the whole;
+		// compressed copies are always sent whole, so the length is known
+		data = a.encoded[coding]
+		h.Set("Content-Encoding", coding)
+		h.Set("Content-Length", strconv.Itoa(len(data)))
+		r.Header.Del("Range")
+	}
+	if len(a.encoded) > 0 {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	h.Set("Content-Type", a.contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Cache-Control", cacheControl)
+	h.Set("ETag", a.etag(coding))
+
+	http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(data))
 }
@@ -0,0 +1,267 @@
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMount_pwa(t *testing.T) {
+	router := chi.NewRouter()
+	Mount(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/sw.js")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/javascript; charset=utf-8" || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("GET /sw.js = %d %v", rec.Code, rec.Header())
+	}
+
+	rec = get("/dist/manifest.webmanifest")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/manifest+json" {
+		t.Fatalf("GET /dist/manifest.webmanifest = %d %v", rec.Code, rec.Header())
+	}
+	var manifest struct {
+		StartURL string `json:"start_url"`
+		Icons    []struct {
+			Src string `json:"src"`
+		} `json:"icons"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("the manifest is not JSON: %v", err)
+	}
+	for _, icon := range manifest.Icons {
+		if rec := get(icon.Src); rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d", icon.Src, rec.Code)
+		}
+	}
+}
+
+func TestMount_fingerprints(t *testing.T) {
+	router := chi.NewRouter()
+	Mount(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	path := Path("app.js")
+	if path == "/dist/app.js" || !strings.HasPrefix(path, "/dist/app.") || !strings.HasSuffix(path, ".js") {
+		t.Fatalf("Path(app.js) = %q, want the name with a fingerprint in it", path)
+	}
+	rec := get(path)
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "public, max-age=31536000, immutable" {
+		t.Errorf("GET %s = %d %v", path, rec.Code, rec.Header())
+	}
+	sum := sha512.Sum384(rec.Body.Bytes())
+	if want := "sha384-" + base64.StdEncoding.EncodeToString(sum[:]); Integrity("app.js") != want {
+		t.Errorf("Integrity(app.js) = %q, want %q", Integrity("app.js"), want)
+	}
+
+	if rec = get("/dist/app.js"); rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("GET /dist/app.js = %d %v", rec.Code, rec.Header())
+	}
+	if rec = get("/dist/app.000000000000.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("GET with a stale fingerprint = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if Path("missing.js") != "/dist/missing.js" || Integrity("missing.js") != "" {
+		t.Errorf("Path(missing.js) = %q with integrity %q", Path("missing.js"), Integrity("missing.js"))
+	}
+}
+
+// TestVendor checks the vendored libraries against the hashes pinned when they were downloaded
+func TestVendor(t *testing.T) {
+	data, err := os.ReadFile("vendor.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var libraries []struct {
+		File      string `json:"file"`
+		Integrity string `json:"integrity"`
+	}
+	if err = json.Unmarshal(data, &libraries); err != nil {
+		t.Fatal(err)
+	}
+	for _, lib := range libraries {
+		if lib.Integrity == "" {
+			t.Errorf("%s has no integrity pinned in vendor.json; run task vendor", lib.File)
+		}
+		got := Integrity(lib.File)
+		if got == "" {
+			t.Errorf("%s is not vendored; run task vendor and commit it", lib.File)
+			continue
+		}
+		if got != lib.Integrity {
+			t.Errorf("%s has the integrity %s, want %s as pinned in vendor.json", lib.File, got, lib.Integrity)
+		}
+	}
+}
+
+// TestScripts fails when a template loads a script that is not embedded, which would be left to 404 and be
+// blocked by the Content-Security-Policy besides
+func TestScripts(t *testing.T) {
+	script := regexp.MustCompile(`@script\("([^"]+)"\)`)
+	found := 0
+	err := filepath.WalkDir("../templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".templ" {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		for _, match := range script.FindAllSubmatch(data, -1) {
+			found++
+			if name := string(match[1]); assets[name] == nil {
+				t.Errorf("%s loads the script %s, which is not embedded", p, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == 0 {
+		t.Error("no templates load scripts with @script")
+	}
+}
+
+func TestMount_encoding(t *testing.T) {
+	router := chi.NewRouter()
+	Mount(router)
+	original, err := fs.ReadFile(Assets, "dist/app.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		acceptEncoding string
+		wantEncoding   string
+		decode         func(r io.Reader) (io.Reader, error)
+	}{
+		"Brotli": {
+			acceptEncoding: "gzip, deflate, br",
+			wantEncoding:   "br",
+			decode:         func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		},
+		"Gzip": {
+			acceptEncoding: "gzip",
+			wantEncoding:   "gzip",
+			decode:         func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+		"BrotliRefused": {
+			acceptEncoding: "br;q=0, *",
+			wantEncoding:   "gzip",
+			decode:         func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+		"Identity": {
+			acceptEncoding: "",
+			decode:         func(r io.Reader) (io.Reader, error) { return r, nil },
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, Path("app.js"), nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET = %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tc.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tc.wantEncoding)
+			}
+			if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+				t.Errorf("Content-Length = %q, want %d", got, rec.Body.Len())
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "text/javascript; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			body, err := tc.decode(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded, err := io.ReadAll(body); err != nil || !bytes.Equal(decoded, original) {
+				t.Errorf("the body does not decode to app.js: %v", err)
+			}
+		})
+	}
+}
+
+func TestMount_conditional(t *testing.T) {
+	router := chi.NewRouter()
+	Mount(router)
+
+	do := func(method, path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/dist/styles.css", http.Header{"Accept-Encoding": {"gzip"}})
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("GET /dist/styles.css = %d %v", rec.Code, rec.Header())
+	}
+
+	rec = do(http.MethodGet, "/dist/styles.css", http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {etag}})
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("GET with If-None-Match = %d with %d bytes, want %d", rec.Code, rec.Body.Len(), http.StatusNotModified)
+	}
+	// the uncompressed copy is another representation, with a tag of its own
+	rec = do(http.MethodGet, "/dist/styles.css", http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("GET the uncompressed copy with the gzip ETag = %d %s", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = do(http.MethodHead, Path("styles.css"), nil)
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 || rec.Header().Get("Content-Length") == "" {
+		t.Errorf("HEAD = %d with %d bytes, Content-Length %q", rec.Code, rec.Body.Len(), rec.Header().Get("Content-Length"))
+	}
+
+	for path, want := range map[string]string{
+		Path("styles.css"):           "text/css; charset=utf-8",
+		Path("favicon.svg"):          "image/svg+xml",
+		"/dist/manifest.webmanifest": "application/manifest+json",
+		"/sw.js":                     "text/javascript; charset=utf-8",
+	} {
+		rec = do(http.MethodGet, path, nil)
+		if got := rec.Header().Get("Content-Type"); rec.Code != http.StatusOK || got != want {
+			t.Errorf("GET %s = %d %q, want %q", path, rec.Code, got, want)
+		}
+		if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("GET %s X-Content-Type-Options = %q", path, got)
+		}
+	}
+}
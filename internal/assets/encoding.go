@@ -0,0 +1,131 @@
+package assets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// codings are the content codings the assets are compressed with, in the order they are preferred
+var codings = []string{"br", "gzip"}
+
+// contentTypes are the types of the assets served, so that they do not hang on the MIME tables of the system
+var contentTypes = map[string]string{
+	".css":         "text/css; charset=utf-8",
+	".html":        "text/html; charset=utf-8",
+	".ico":         "image/x-icon",
+	".js":          "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".png":         "image/png",
+	".svg":         "image/svg+xml",
+	".txt":         "text/plain; charset=utf-8",
+	".webmanifest": "application/manifest+json",
+	".webp":        "image/webp",
+	".woff2":       "font/woff2",
+}
+
+func contentType(name string, data []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// compressible reports whether compressing content of the type is worth it; images other than SVG and fonts
+// are compressed already
+func compressible(contentType string) bool {
+	t, _, _ := strings.Cut(contentType, ";")
+	switch {
+	case strings.HasPrefix(t, "text/"), t == "image/svg+xml", t == "image/x-icon":
+		return true
+	case strings.HasPrefix(t, "application/"):
+		return strings.HasSuffix(t, "json") || strings.HasSuffix(t, "javascript") || strings.HasSuffix(t, "xml")
+	}
+	return false
+}
+
+// compress compresses the data with each coding as hard as it goes, as it is only done once
+func compress(data []byte) (map[string][]byte, error) {
+	encoded := make(map[string][]byte, len(codings))
+
+	var gz bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&gz, gzip.BestCompression)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	encoded["gzip"] = gz.Bytes()
+
+	var br bytes.Buffer
+	bw := brotli.NewWriterLevel(&br, brotli.BestCompression)
+	if _, err := bw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := bw.Close(); err != nil {
+		return nil, err
+	}
+	encoded["br"] = br.Bytes()
+
+	for coding, e := range encoded {
+		if len(e) >= len(data) {
+			delete(encoded, coding)
+		}
+	}
+	return encoded, nil
+}
+
+// negotiate picks the coding to send the asset with from those the Accept-Encoding header allows, or an
+// empty string to send it as it is
+func negotiate(a *asset, header string) string {
+	if len(a.encoded) == 0 || header == "" {
+		return ""
+	}
+	accepted := parseAcceptEncoding(header)
+	for _, coding := range codings {
+		if _, ok := a.encoded[coding]; !ok {
+			continue
+		}
+		q, ok := accepted[coding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > 0 {
+			return coding
+		}
+	}
+	return ""
+}
+
+// parseAcceptEncoding returns the quality of each coding named in the header
+func parseAcceptEncoding(header string) map[string]float64 {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		accepted[coding] = q
+	}
+	return accepted
+}
@@ -0,0 +1,65 @@
+package assets
+
+import "testing"
+
+func Test_negotiate(t *testing.T) {
+	a, err := newAsset("app.js", []byte("htmx.onLoad(function () {}); htmx.onLoad(function () {}); htmx.onLoad(function () {});"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := newAsset("tiny.js", []byte("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		asset  *asset
+		header string
+		want   string
+	}{
+		"None":              {asset: a, header: "", want: ""},
+		"Brotli":            {asset: a, header: "br", want: "br"},
+		"PrefersBrotli":     {asset: a, header: "gzip, br", want: "br"},
+		"Gzip":              {asset: a, header: "gzip, deflate", want: "gzip"},
+		"Refused":           {asset: a, header: "br;q=0, gzip;q=0", want: ""},
+		"Wildcard":          {asset: a, header: "*", want: "br"},
+		"WildcardRefused":   {asset: a, header: "gzip, *;q=0", want: "gzip"},
+		"CaseAndSpaces":     {asset: a, header: " GZIP ; q=0.5 ", want: "gzip"},
+		"BadQuality":        {asset: a, header: "br;q=x, gzip", want: "gzip"},
+		"NotWorthIt":        {asset: small, header: "br, gzip", want: ""},
+		"OnlyIdentityKnown": {asset: a, header: "deflate, identity", want: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := negotiate(tc.asset, tc.header); got != tc.want {
+				t.Errorf("negotiate(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_contentType(t *testing.T) {
+	tests := map[string]struct {
+		name         string
+		want         string
+		compressible bool
+	}{
+		"JavaScript": {name: "vendor/htmx.min.js", want: "text/javascript; charset=utf-8", compressible: true},
+		"CSS":        {name: "styles.css", want: "text/css; charset=utf-8", compressible: true},
+		"SVG":        {name: "favicon.svg", want: "image/svg+xml", compressible: true},
+		"Manifest":   {name: "manifest.webmanifest", want: "application/manifest+json", compressible: true},
+		"PNG":        {name: "icon.PNG", want: "image/png", compressible: false},
+		"Font":       {name: "mono.woff2", want: "font/woff2", compressible: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := contentType(tc.name, nil)
+			if got != tc.want {
+				t.Errorf("contentType(%q) = %q, want %q", tc.name, got, tc.want)
+			}
+			if compressible(got) != tc.compressible {
+				t.Errorf("compressible(%q) = %v, want %v", got, !tc.compressible, tc.compressible)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+//go:build ignore
+
+// gen_vendor downloads the JavaScript libraries listed in vendor.json into dist, so that they are embedded
+// and served by the app instead of loaded from a CDN
+//
+// Each download is checked against the integrity hash pinned in vendor.json. A library without a pinned hash
+// has the hash of what was downloaded written back to vendor.json, to be checked from then on. Libraries
+// already in dist that match their hash are left alone, so this only needs the network when one changes.
+package main
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const manifest = "vendor.json"
+
+type library struct {
+	// File is where the library is kept under dist
+	File      string `json:"file"`
+	URL       string `json:"url"`
+	Integrity string `json:"integrity"`
+}
+
+func main() {
+	log.SetFlags(0)
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var libraries []library
+	if err = json.Unmarshal(data, &libraries); err != nil {
+		log.Fatalf("reading %s: %v", manifest, err)
+	}
+
+	pinned := false
+	client := &http.Client{Timeout: time.Minute}
+	for i, lib := range libraries {
+		path := filepath.Join("dist", filepath.FromSlash(lib.File))
+		if existing, err := os.ReadFile(path); err == nil && lib.Integrity != "" && integrity(existing) == lib.Integrity {
+			continue
+		}
+
+		body, err := download(client, lib.URL)
+		if err != nil {
+			log.Fatalf("downloading %s: %v", lib.URL, err)
+		}
+		sum := integrity(body)
+		switch lib.Integrity {
+		case "":
+			libraries[i].Integrity, pinned = sum, true
+		case sum:
+		default:
+			log.Fatalf("%s has the integrity %s, want %s", lib.URL, sum, lib.Integrity)
+		}
+
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err = os.WriteFile(path, body, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %s\n", path, sum)
+	}
+
+	if !pinned {
+		return
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err = enc.Encode(libraries); err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(manifest, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func integrity(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -32,60 +32,141 @@ class TodoApp {
         });
     }
 
+    // loadTodos shows the todos kept from the last sync, then syncs; the changes made offline are queued in
+    // localStorage and sent with the next sync that gets through
     async loadTodos() {
-        try {
-            const response = await fetch(this.config.apiEndpoint);
-            this.todos = await response.json();
+        const saved = JSON.parse(localStorage.getItem(this.storageKey('state')) || 'null');
+        if (saved) {
+            this.todos = saved.todos;
+            this.token = saved.token;
             this.renderTodos();
-        } catch (error) {
-            console.error('Error loading todos:', error);
         }
+        this.queue = JSON.parse(localStorage.getItem(this.storageKey('queue')) || '[]');
+        this.clientId = localStorage.getItem(this.storageKey('client'));
+        if (!this.clientId) {
+            this.clientId = crypto.randomUUID();
+            localStorage.setItem(this.storageKey('client'), this.clientId);
+        }
+
+        window.addEventListener('online', () => this.sync());
+        setInterval(() => this.sync(), this.config.syncInterval ?? 30000);
+        await this.sync();
     }
 
-    async addTodo(formData) {
+    storageKey(name) {
+        return `todos.sync.${name}`;
+    }
+
+    save() {
+        localStorage.setItem(this.storageKey('state'), JSON.stringify({ token: this.token, todos: this.todos }));
+        localStorage.setItem(this.storageKey('queue'), JSON.stringify(this.queue));
+    }
+
+    // sync sends the queued changes and applies what changed on the server since the last sync
+    async sync() {
+        if (this.syncing || !navigator.onLine) {
+            return;
+        }
+        this.syncing = true;
+        const sent = this.queue.slice();
         try {
-            const response = await fetch(this.config.apiEndpoint, {
+            const response = await fetch(this.config.syncEndpoint ?? '/api/sync', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
                 },
-                body: JSON.stringify(Object.fromEntries(formData)),
+                body: JSON.stringify({
+                    token: this.token ?? '',
+                    clientId: this.clientId,
+                    now: new Date().toISOString(),
+                    mutations: sent,
+                }),
             });
-
-            if (response.ok) {
-                const todo = await response.json();
-                this.todos.push(todo);
-                this.renderTodos();
-                this.config.onTodoUpdate?.(todo);
+            if (!response.ok) {
+                return;
             }
+            const changes = await response.json();
+            this.queue = this.queue.slice(sent.length);
+            this.applyChanges(changes);
+            changes.results
+                .filter(result => result.status === 'rejected')
+                .forEach(result => console.error('Change rejected:', result.id, result.error));
         } catch (error) {
-            console.error('Error adding todo:', error);
+            console.error('Error syncing todos:', error);
+        } finally {
+            this.syncing = false;
         }
     }
 
-    async updateTodo(id, updates) {
-        try {
-            const response = await fetch(`${this.config.apiEndpoint}/${id}`, {
-                method: 'PUT',
-                headers: {
-                    'Content-Type': 'application/json',
-                },
-                body: JSON.stringify(updates),
-            });
+    applyChanges(changes) {
+        const deleted = new Set(changes.deleted);
+        let todos = changes.reset ? [] : this.todos.filter(todo => !deleted.has(todo.id));
+        const changed = new Map(changes.todos.map(todo => [todo.id, todo]));
+        todos = todos.map(todo => changed.get(todo.id) ?? todo);
+        const known = new Set(todos.map(todo => todo.id));
+        todos.push(...changes.todos.filter(todo => !known.has(todo.id)));
+
+        // the changes still queued are laid over the server's copy until they are sent
+        this.todos = todos;
+        this.queue.forEach(mutation => this.applyMutation(mutation));
+        this.token = changes.token;
+        this.save();
+        this.filterTodos();
+        changes.todos.forEach(todo => this.config.onTodoUpdate?.(todo));
+    }
 
-            if (response.ok) {
-                const updatedTodo = await response.json();
-                this.todos = this.todos.map(todo => 
-                    todo.id === id ? updatedTodo : todo
+    applyMutation(mutation) {
+        switch (mutation.op) {
+            case 'create':
+                if (!this.todos.some(todo => todo.id === mutation.todoId)) {
+                    this.todos.push({ id: mutation.todoId, completed: false, priority: 'medium', tags: [], ...mutation.fields });
+                }
+                break;
+            case 'update':
+                this.todos = this.todos.map(todo =>
+                    todo.id === mutation.todoId ? { ...todo, ...mutation.fields } : todo
                 );
-                this.renderTodos();
-                this.config.onTodoUpdate?.(updatedTodo);
-            }
-        } catch (error) {
-            console.error('Error updating todo:', error);
+                break;
+            case 'delete':
+                this.todos = this.todos.filter(todo => todo.id !== mutation.todoId);
+                break;
         }
     }
 
+    // queueMutation records a change to send with the next sync and shows it straight away
+    queueMutation(op, todoId, fields) {
+        const mutation = { id: crypto.randomUUID(), op, todoId, at: new Date().toISOString(), fields };
+        this.queue.push(mutation);
+        this.applyMutation(mutation);
+        this.save();
+        this.filterTodos();
+        this.sync();
+    }
+
+    // addTodo queues a todo made from the form with every field the sync keeps; the form names priorities by
+    // number and gives due dates in local time, while the sync takes names and ISO times
+    addTodo(formData) {
+        const form = Object.fromEntries(formData);
+        const priorities = ['low', 'medium', 'high'];
+        this.queueMutation('create', crypto.randomUUID(), {
+            description: form.description,
+            completed: formData.has('completed'),
+            priority: priorities[parseInt(form.priority, 10)] ?? form.priority ?? 'medium',
+            category: form.category ?? '',
+            tags: (form.tags ?? '').split(',').map(tag => tag.trim()).filter(tag => tag !== ''),
+            dueDate: form.dueDate ? new Date(form.dueDate).toISOString() : null,
+            archived: formData.has('archived'),
+        });
+    }
+
+    updateTodo(id, updates) {
+        this.queueMutation('update', id, updates);
+    }
+
+    removeTodo(id) {
+        this.queueMutation('delete', id);
+    }
+
     async addComment(todoId, content) {
         try {
             const response = await fetch(`${this.config.apiEndpoint}/${todoId}/comments`, {
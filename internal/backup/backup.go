@@ -0,0 +1,219 @@
+// Package backup takes point-in-time copies of the todos, users and saved views and restores them
+//
+// A backup is written as gzip-compressed JSON: a manifest line naming the format, when the backup was taken,
+// how many of each thing it holds and the SHA-256 checksum of the data, followed by the data itself. A backup
+// is checked against its manifest before anything is restored from it.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// Format is the version of the backup format written; backups in this format or an earlier one can be read
+const Format = 1
+
+var (
+	ErrCorrupt           = errors.New("backup is corrupt")
+	ErrUnsupportedFormat = errors.New("backup format is not supported")
+)
+
+type (
+	// Manifest describes a backup
+	Manifest struct {
+		Format  int       `json:"format"`
+		TakenAt time.Time `json:"takenAt"`
+		Todos   int       `json:"todos"`
+		Users   int       `json:"users"`
+		Views   int       `json:"views"`
+		// SHA256 is the hex-encoded checksum of the data following the manifest
+		SHA256 string `json:"sha256"`
+	}
+
+	// Backup is a copy of the todos, users and saved views as they were when it was taken
+	Backup struct {
+		TakenAt time.Time
+		// Todos are in the order they were dragged into
+		Todos []*domain.Todo
+		Users []*domain.User
+		Views []*domain.View
+	}
+
+	// data is a backup as it is written after the manifest
+	data struct {
+		Todos []todoRecord   `json:"todos"`
+		Users []*domain.User `json:"users"`
+		Views []*domain.View `json:"views"`
+	}
+
+	// todoRecord is a todo as it is written to a backup, naming its subtasks by ID so that each todo is
+	// written once
+	todoRecord struct {
+		domain.Todo
+		Subtasks []uuid.UUID
+	}
+)
+
+// Take copies the todos, users and saved views as they are now
+//
+// The repositories hand out copies made under their locks, so nothing changed while the backup is written ends
+// up in it. A todo repository that keeps a history gives the todos as they were at the time the backup is
+// taken, however long the users and views take to copy.
+func Take(todos domain.TodoRepository, users domain.UserRepository, views domain.ViewRepository, now time.Time) (*Backup, error) {
+	b := &Backup{TakenAt: now, Users: users.All(), Views: views.All()}
+	history, ok := todos.(domain.TodoHistory)
+	if !ok {
+		b.Todos = todos.All()
+		return b, nil
+	}
+	var err error
+	if b.Todos, err = history.TodosAt(now); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Write writes the backup, compressed, to w
+func Write(w io.Writer, b *Backup) error {
+	raw, err := json.Marshal(newData(b.Todos, b.Users, b.Views))
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(raw)
+	manifest, err := json.Marshal(Manifest{
+		Format:  Format,
+		TakenAt: b.TakenAt,
+		Todos:   len(b.Todos),
+		Users:   len(b.Users),
+		Views:   len(b.Views),
+		SHA256:  hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	zw.ModTime = b.TakenAt
+	for _, part := range [][]byte{manifest, {'\n'}, raw} {
+		if _, err = zw.Write(part); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Read reads a backup written by Write, checking it against its manifest
+//
+// ErrUnsupportedFormat is returned for backups written by a later version of the format, and ErrCorrupt for
+// backups that cannot be read or do not hold what their manifest says.
+func Read(r io.Reader) (*Backup, Manifest, error) {
+	var manifest Manifest
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, manifest, fmt.Errorf("%w: reading the manifest: %w", ErrCorrupt, err)
+	}
+	if err = json.Unmarshal(line, &manifest); err != nil {
+		return nil, manifest, fmt.Errorf("%w: reading the manifest: %w", ErrCorrupt, err)
+	}
+	if manifest.Format < 1 || manifest.Format > Format {
+		return nil, manifest, fmt.Errorf("%w: format %d", ErrUnsupportedFormat, manifest.Format)
+	}
+
+	raw, err := io.ReadAll(br)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	if sum := sha256.Sum256(raw); hex.EncodeToString(sum[:]) != manifest.SHA256 {
+		return nil, manifest, fmt.Errorf("%w: the checksum does not match", ErrCorrupt)
+	}
+	d, err := decodeData(raw)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	if len(d.Todos) != manifest.Todos || len(d.Users) != manifest.Users || len(d.Views) != manifest.Views {
+		return nil, manifest, fmt.Errorf("%w: it does not hold what the manifest lists", ErrCorrupt)
+	}
+
+	b := &Backup{TakenAt: manifest.TakenAt}
+	b.Todos, b.Users, b.Views = d.unpack()
+	return b, manifest, nil
+}
+
+// Restore saves the todos, users and views of the backup, replacing those with the same IDs
+//
+// Anything not in the backup is left as it is, so a backup can be restored into an empty store to get back
+// what was there or into one in use to bring back what was lost.
+func (b *Backup) Restore(todos domain.TodoRepository, users domain.UserRepository, views domain.ViewRepository) {
+	for _, user := range b.Users {
+		users.Save(user)
+	}
+	for _, view := range b.Views {
+		views.Save(view)
+	}
+	todos.Save(b.Todos...)
+}
+
+func newData(todos []*domain.Todo, users []*domain.User, views []*domain.View) data {
+	d := data{Todos: make([]todoRecord, len(todos)), Users: users, Views: views}
+	for i, todo := range todos {
+		r := todoRecord{Todo: *todo, Subtasks: make([]uuid.UUID, len(todo.Subtasks))}
+		r.Todo.Subtasks = nil
+		for j, subtask := range todo.Subtasks {
+			r.Subtasks[j] = subtask.ID
+		}
+		d.Todos[i] = r
+	}
+	return d
+}
+
+func decodeData(raw []byte) (data, error) {
+	var d data
+	err := json.Unmarshal(raw, &d)
+	return d, err
+}
+
+// unpack returns the todos of the data with their subtasks linked up, along with the users and views
+func (d data) unpack() ([]*domain.Todo, []*domain.User, []*domain.View) {
+	todos := make([]*domain.Todo, len(d.Todos))
+	byID := make(map[uuid.UUID]*domain.Todo, len(d.Todos))
+	for i := range d.Todos {
+		todo := d.Todos[i].Todo
+		todos[i] = &todo
+		byID[todo.ID] = &todo
+	}
+	for i, r := range d.Todos {
+		todos[i].Subtasks = make([]*domain.Todo, 0, len(r.Subtasks))
+		for _, id := range r.Subtasks {
+			if subtask, ok := byID[id]; ok {
+				todos[i].Subtasks = append(todos[i].Subtasks, subtask)
+			}
+		}
+	}
+
+	users, views := d.Users, d.Views
+	if users == nil {
+		users = make([]*domain.User, 0)
+	}
+	if views == nil {
+		views = make([]*domain.View, 0)
+	}
+	return todos, users, views
+}
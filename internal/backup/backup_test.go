@@ -0,0 +1,173 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func fixture() (*domain.Todos, *domain.Users, *domain.Views) {
+	todos, users, views := domain.NewTodos(), domain.NewUsers(), domain.NewViews()
+	user := &domain.User{ID: uuid.New(), Username: "sam", CreatedAt: time.Now()}
+	users.Save(user)
+	trip := domain.NewTodo("Plan vacation")
+	trip.AddComment("Somewhere warm", user.ID)
+	todos.Save(trip)
+	_, _ = todos.AddSubtask(trip.ID, domain.NewTodo("Book flights"))
+	views.Save(domain.NewView(user.ID, "Travel", domain.Query{Tags: []string{"travel"}}, domain.SortOrder("due"), true))
+	return todos, users, views
+}
+
+func TestWriteRead(t *testing.T) {
+	todos, users, views := fixture()
+	takenAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	b, err := Take(todos, users, views, takenAt)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	// the backup is a copy, so later changes are not in it
+	_, _ = todos.Change(todos.All()[0].ID, domain.Rename{Description: "Plan a holiday"})
+
+	var buf bytes.Buffer
+	if err = Write(&buf, b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, manifest, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if manifest.Format != Format || !manifest.TakenAt.Equal(takenAt) || manifest.Todos != 2 || manifest.Users != 1 || manifest.Views != 1 {
+		t.Errorf("Read() manifest = %+v", manifest)
+	}
+
+	trip, flights := got.Todos[0], got.Todos[1]
+	if trip.Description != "Plan vacation" || len(trip.Comments) != 1 || len(trip.Subtasks) != 1 || trip.Subtasks[0] != flights {
+		t.Errorf("Read() trip = %+v, want the todo as it was with its subtask linked", trip)
+	}
+	if got.Users[0].Username != "sam" || got.Views[0].Name != "Travel" || got.Views[0].Query.Tags[0] != "travel" {
+		t.Errorf("Read() users = %+v, views = %+v", got.Users[0], got.Views[0])
+	}
+
+	// restoring into an empty store brings everything back
+	restored, restoredUsers, restoredViews := domain.NewTodos(), domain.NewUsers(), domain.NewViews()
+	got.Restore(restored, restoredUsers, restoredViews)
+	if len(restored.All()) != 2 || restored.Get(trip.ID).Description != trip.Description || len(restored.Get(trip.ID).Subtasks) != 1 || len(restoredUsers.All()) != 1 || len(restoredViews.All()) != 1 {
+		t.Errorf("Restore() into an empty store = %d todos, %d users, %d views", len(restored.All()), len(restoredUsers.All()), len(restoredViews.All()))
+	}
+
+	// restoring into a store in use replaces what is in the backup and keeps the rest
+	cat := todos.Add("Feed the cat")
+	got.Restore(todos, users, views)
+	if all := todos.All(); len(all) != 3 || all[0].Description != "Plan vacation" || all[2].ID != cat.ID {
+		t.Errorf("Restore() into a store in use = %v", all)
+	}
+}
+
+// history is a todo repository that remembers the todos as they were when it was made, and when they were
+// last asked for
+type history struct {
+	*domain.Todos
+	past []*domain.Todo
+	at   time.Time
+}
+
+func (h *history) TodosAt(at time.Time) ([]*domain.Todo, error) {
+	h.at = at
+	return h.past, nil
+}
+
+func TestTake_history(t *testing.T) {
+	todos, users, views := fixture()
+	repo := &history{Todos: todos, past: todos.All()}
+	todos.Add("Feed the cat")
+
+	// the todos are those of the moment the backup is taken, not those there once the users and views are read
+	takenAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	b, err := Take(repo, users, views, takenAt)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if len(b.Todos) != 2 || !repo.at.Equal(takenAt) {
+		t.Errorf("Take() = %d todos as of %v, want the 2 there at %v", len(b.Todos), repo.at, takenAt)
+	}
+}
+
+func TestRead_integrity(t *testing.T) {
+	todos, users, views := fixture()
+	b, _ := Take(todos, users, views, time.Now())
+	var buf bytes.Buffer
+	_ = Write(&buf, b)
+
+	// rewrite unpacks the backup, changes it and packs it again
+	rewrite := func(change func(s string) string) *bytes.Buffer {
+		zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var plain bytes.Buffer
+		_, _ = plain.ReadFrom(zr)
+		var out bytes.Buffer
+		zw := gzip.NewWriter(&out)
+		_, _ = zw.Write([]byte(change(plain.String())))
+		_ = zw.Close()
+		return &out
+	}
+
+	tests := map[string]struct {
+		backup *bytes.Buffer
+		want   error
+	}{
+		"not gzip":       {backup: bytes.NewBufferString("todos"), want: ErrCorrupt},
+		"cut short":      {backup: bytes.NewBuffer(buf.Bytes()[:buf.Len()/2]), want: ErrCorrupt},
+		"changed data":   {backup: rewrite(func(s string) string { return strings.Replace(s, "Plan vacation", "Plan vacations", 1) }), want: ErrCorrupt},
+		"later format":   {backup: rewrite(func(s string) string { return strings.Replace(s, `"format":1`, `"format":2`, 1) }), want: ErrUnsupportedFormat},
+		"missing a todo": {backup: rewrite(func(s string) string { return strings.Replace(s, `"todos":2`, `"todos":3`, 1) }), want: ErrCorrupt},
+		"no manifest":    {backup: rewrite(func(s string) string { return s[strings.IndexByte(s, '\n')+1:] }), want: ErrCorrupt},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := Read(tc.backup); !errors.Is(err, tc.want) {
+				t.Errorf("Read() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestDir_Save(t *testing.T) {
+	path := t.TempDir()
+	dir := NewDir(path, 2)
+	todos, users, views := fixture()
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 3; day++ {
+		b, _ := Take(todos, users, views, start.AddDate(0, 0, day))
+		if _, err := dir.Save(b); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	// files that are not backups are left alone
+	_ = os.WriteFile(filepath.Join(path, "notes.txt"), nil, 0o644)
+
+	files, err := dir.Files()
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	want := []string{FileName(start.AddDate(0, 0, 1)), FileName(start.AddDate(0, 0, 2))}
+	if len(files) != 2 || filepath.Base(files[0]) != want[0] || filepath.Base(files[1]) != want[1] {
+		t.Fatalf("Files() = %v, want %v", files, want)
+	}
+
+	f, _ := os.Open(files[1])
+	defer f.Close()
+	if _, manifest, err := Read(f); err != nil || !manifest.TakenAt.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("Read() of the latest backup = %+v, %v", manifest, err)
+	}
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	filePrefix = "todos-"
+	fileSuffix = ".json.gz"
+	// fileTime names the backups so that they sort by when they were taken
+	fileTime = "20060102T150405.000000000Z"
+)
+
+// Dir keeps backups in a directory, removing the oldest as new ones are saved
+type Dir struct {
+	path string
+	// keep is how many backups are kept; zero keeps them all
+	keep int
+}
+
+func NewDir(path string, keep int) *Dir {
+	return &Dir{path: path, keep: keep}
+}
+
+// FileName names the file of a backup taken at the time
+func FileName(takenAt time.Time) string {
+	return filePrefix + takenAt.UTC().Format(fileTime) + fileSuffix
+}
+
+// Save writes the backup to a new file in the directory and removes the backups past the number kept,
+// returning the path of the file
+func (d *Dir) Save(b *Backup) (string, error) {
+	if err := os.MkdirAll(d.path, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(d.path, FileName(b.TakenAt))
+	if err := WriteFile(path, b); err != nil {
+		return "", err
+	}
+	return path, d.rotate()
+}
+
+// Files returns the paths of the backups in the directory, oldest first
+func (d *Dir) Files() ([]string, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			files = append(files, filepath.Join(d.path, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (d *Dir) rotate() error {
+	if d.keep <= 0 {
+		return nil
+	}
+	files, err := d.Files()
+	if err != nil {
+		return err
+	}
+	for len(files) > d.keep {
+		if err = os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// WriteFile writes the backup to the file, moving it into place once it is whole
+func WriteFile(path string, b *Backup) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err = Write(tmp, b); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,40 @@
+// Package blob keeps the content of files outside the todos that refer to them
+//
+// A Store is addressed by keys that look like relative paths, such as "todos/<id>/<id>". The
+// local filesystem is used unless the server is given an S3-compatible object store.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps blobs by key
+type Store interface {
+	// Put stores the content under the key, replacing any blob already there
+	Put(ctx context.Context, key string, contentType string, content io.Reader) error
+	// Get opens the blob with the key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob with the key; removing a blob that is not there is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey returns true for keys made of non-empty path segments that do not climb out of the store
+func validKey(key string) bool {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testStore puts, reads and deletes blobs the way every Store must allow
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "todos/1/a", "text/plain", strings.NewReader("first")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := store.Put(ctx, "todos/1/a", "text/plain", strings.NewReader("second")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	r, err := store.Get(ctx, "todos/1/a")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	content, _ := io.ReadAll(r)
+	_ = r.Close()
+	if string(content) != "second" {
+		t.Errorf("Get() = %q, want the content put last", content)
+	}
+
+	if err = store.Delete(ctx, "todos/1/a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err = store.Delete(ctx, "todos/1/a"); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+	if _, err = store.Get(ctx, "todos/1/a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+
+	for _, key := range []string{"", "../escape", "todos/../../escape", "/todos/1", "todos//1"} {
+		if err = store.Put(ctx, key, "", strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) error = %v, want %v", key, err, ErrInvalidKey)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	testStore(t, NewFileStore(dir))
+
+	// deleting the last blob below a directory removes the directory as well
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("entries = %v (%v), want an empty directory", entries, err)
+	}
+	if _, err = os.Stat(filepath.Join(filepath.Dir(dir), "escape")); err == nil {
+		t.Errorf("a blob was written outside the directory")
+	}
+}
@@ -0,0 +1,34 @@
+package csp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var seen []string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, Nonce(r.Context()))
+	}))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := seen[len(seen)-1]
+		if nonce == "" {
+			t.Fatalf("no nonce in the request context")
+		}
+		header := rec.Header().Get("Content-Security-Policy")
+		if !strings.Contains(header, "script-src 'nonce-"+nonce+"' 'strict-dynamic'") {
+			t.Errorf("Content-Security-Policy = %q, want it to allow the nonce %q", header, nonce)
+		}
+		if strings.Contains(header, "unsafe") {
+			t.Errorf("Content-Security-Policy = %q, want nothing unsafe", header)
+		}
+	}
+	if seen[0] == seen[1] {
+		t.Errorf("both requests got the nonce %q", seen[0])
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file attached to a todo; its content is kept in a blob store
+type Attachment struct {
+	ID          uuid.UUID
+	Filename    string
+	ContentType string
+	Size        int64
+	// BlobKey is where the content is kept in the blob store
+	BlobKey string
+	// ThumbnailKey is where a small copy of an image is kept, when one could be made
+	ThumbnailKey string
+	UserID       uuid.UUID
+	CreatedAt    time.Time
+}
+
+// IsImage returns true when the attachment can be shown as an image
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// HasThumbnail returns true when there is a small copy of the attachment to show
+func (a Attachment) HasThumbnail() bool {
+	return a.ThumbnailKey != ""
+}
+
+// AddAttachment adds an attachment to the todo
+func (t *Todo) AddAttachment(attachment Attachment) {
+	t.addAttachment(attachment, time.Now())
+}
+
+func (t *Todo) addAttachment(attachment Attachment, now time.Time) {
+	t.Attachments = append(t.Attachments, attachment)
+	t.touch(now)
+}
+
+// Attachment returns the attachment with the ID, or nil when the todo has no such attachment
+func (t *Todo) Attachment(id uuid.UUID) *Attachment {
+	for i := range t.Attachments {
+		if t.Attachments[i].ID == id {
+			return &t.Attachments[i]
+		}
+	}
+	return nil
+}
+
+// RemoveAttachment removes the attachment from the todo, returning false when there is none to remove
+func (t *Todo) RemoveAttachment(id uuid.UUID) bool {
+	return t.removeAttachment(id, time.Now())
+}
+
+func (t *Todo) removeAttachment(id uuid.UUID, now time.Time) bool {
+	for i := range t.Attachments {
+		if t.Attachments[i].ID == id {
+			t.Attachments = append(t.Attachments[:i], t.Attachments[i+1:]...)
+			t.touch(now)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTodo_Attachments(t *testing.T) {
+	todo := NewTodo("Fix the layout")
+	screenshot := Attachment{ID: uuid.New(), Filename: "screen.png", ContentType: "image/png", ThumbnailKey: "thumb"}
+	invoice := Attachment{ID: uuid.New(), Filename: "invoice.pdf", ContentType: "application/pdf"}
+	todo.AddAttachment(screenshot)
+	todo.AddAttachment(invoice)
+
+	if got := todo.Attachment(invoice.ID); got == nil || got.Filename != "invoice.pdf" || got.IsImage() || got.HasThumbnail() {
+		t.Errorf("Attachment() = %+v, want the invoice", got)
+	}
+	if got := todo.Attachment(screenshot.ID); got == nil || !got.IsImage() || !got.HasThumbnail() {
+		t.Errorf("Attachment() = %+v, want the screenshot with a thumbnail", got)
+	}
+
+	if !todo.RemoveAttachment(screenshot.ID) || todo.RemoveAttachment(screenshot.ID) {
+		t.Errorf("RemoveAttachment() removes an attachment once")
+	}
+	if len(todo.Attachments) != 1 || todo.Attachments[0].ID != invoice.ID {
+		t.Errorf("Attachments = %v, want only the invoice", todo.Attachments)
+	}
+}
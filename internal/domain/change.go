@@ -0,0 +1,336 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Change is a change made to a todo through the repository, which makes it while holding its lock so that no
+// change is lost to another made at the same time
+//
+// Apply returns why the change cannot be made; the repository then leaves the todo as it was. The IDs and
+// times a change records, other than when it was made, are chosen by whoever makes it rather than by Apply, so
+// that a change played back from a log leaves the todo as it did the first time.
+type Change interface {
+	Apply(todo *Todo, at time.Time) error
+}
+
+type (
+	// Expect makes no change, but fails with ErrVersionConflict unless the todo is still at the version, so that
+	// the changes made with it are not made to a todo someone else has changed since it was read
+	Expect struct {
+		Version int
+	}
+
+	// Update completes or opens the todo and sets its description, as the list does
+	Update struct {
+		Completed   bool
+		Description string
+	}
+
+	// Rename sets the description
+	Rename struct {
+		Description string
+	}
+
+	// Complete completes or opens the todo
+	Complete struct {
+		Completed bool
+	}
+
+	SetPriority struct {
+		Priority Priority
+	}
+
+	SetCategory struct {
+		Category string
+	}
+
+	// SetTags sets the tags, leaving out any that are blank
+	SetTags struct {
+		Tags []string
+	}
+
+	SetDueDate struct {
+		DueDate *time.Time
+	}
+
+	SetArchived struct {
+		Archived bool
+	}
+
+	SetRecurring struct {
+		Frequency string
+		EndDate   *time.Time
+	}
+
+	// SetEstimate sets how long the todo is expected to take, or clears it with zero
+	SetEstimate struct {
+		Estimate time.Duration
+	}
+
+	Assign struct {
+		UserID uuid.UUID
+	}
+
+	// SetSnooze hides the todo until the time of the snooze, replacing any snooze it already had
+	SetSnooze struct {
+		Snooze Snooze
+	}
+
+	// Unsnooze brings the todo back straight away
+	Unsnooze struct{}
+
+	// Wake ends a snooze that is over by now, returning ErrUnchanged when there is none
+	Wake struct {
+		Now time.Time
+	}
+
+	// Reopen opens a completed recurring todo again once the period it was completed in has passed by now,
+	// returning ErrUnchanged when it is not time to
+	Reopen struct {
+		Now time.Time
+	}
+
+	// AddComment adds the comment, returning ErrCommentNotFound when it replies to a comment the todo does not have
+	AddComment struct {
+		Comment Comment
+	}
+
+	// EditComment replaces the content of a comment; only the user who made it may do so
+	EditComment struct {
+		CommentID uuid.UUID
+		UserID    uuid.UUID
+		Content   string
+	}
+
+	// RemoveComment removes a comment and its replies; only the user who made it may do so
+	RemoveComment struct {
+		CommentID uuid.UUID
+		UserID    uuid.UUID
+	}
+
+	// StartTimer starts a timer for the user, unless they already have one running on the todo
+	StartTimer struct {
+		EntryID uuid.UUID
+		UserID  uuid.UUID
+	}
+
+	// StopTimer stops the timer the user has running, returning ErrUnchanged when there is none
+	StopTimer struct {
+		UserID uuid.UUID
+	}
+
+	AddTimeEntry struct {
+		Entry TimeEntry
+	}
+
+	// RemoveTimeEntry removes a time entry; only the user who recorded it may do so
+	RemoveTimeEntry struct {
+		EntryID uuid.UUID
+		UserID  uuid.UUID
+	}
+
+	AddAttachment struct {
+		Attachment Attachment
+	}
+
+	// RemoveAttachment removes an attachment, returning ErrUnchanged when the todo has no such attachment
+	RemoveAttachment struct {
+		AttachmentID uuid.UUID
+	}
+
+	// AddPomodoro records a pomodoro completed on the todo
+	AddPomodoro struct {
+		Pomodoro Pomodoro
+	}
+)
+
+func (c Expect) Apply(todo *Todo, _ time.Time) error {
+	if todo.Version != c.Version {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (c Update) Apply(todo *Todo, at time.Time) error {
+	todo.update(c.Completed, c.Description, at)
+	return nil
+}
+
+func (c Rename) Apply(todo *Todo, at time.Time) error {
+	todo.Description = c.Description
+	todo.Touch(at)
+	return nil
+}
+
+func (c Complete) Apply(todo *Todo, at time.Time) error {
+	todo.complete(c.Completed, at)
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetPriority) Apply(todo *Todo, at time.Time) error {
+	todo.Priority = c.Priority
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetCategory) Apply(todo *Todo, at time.Time) error {
+	todo.Category = c.Category
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetTags) Apply(todo *Todo, at time.Time) error {
+	todo.Tags = make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			todo.Tags = append(todo.Tags, tag)
+		}
+	}
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetDueDate) Apply(todo *Todo, at time.Time) error {
+	todo.DueDate = c.DueDate
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetArchived) Apply(todo *Todo, at time.Time) error {
+	todo.Archived = c.Archived
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetRecurring) Apply(todo *Todo, at time.Time) error {
+	todo.setRecurring(c.Frequency, c.EndDate, at)
+	return nil
+}
+
+func (c SetEstimate) Apply(todo *Todo, at time.Time) error {
+	todo.Estimate = c.Estimate
+	todo.Touch(at)
+	return nil
+}
+
+func (c Assign) Apply(todo *Todo, at time.Time) error {
+	userID := c.UserID
+	todo.AssignedTo = &userID
+	todo.Touch(at)
+	return nil
+}
+
+func (c SetSnooze) Apply(todo *Todo, at time.Time) error {
+	todo.snooze(c.Snooze, at)
+	return nil
+}
+
+func (c Unsnooze) Apply(todo *Todo, at time.Time) error {
+	todo.unsnooze(at)
+	return nil
+}
+
+func (c Wake) Apply(todo *Todo, at time.Time) error {
+	if todo.Snoozed == nil || todo.IsSnoozed(c.Now) {
+		return ErrUnchanged
+	}
+	todo.unsnooze(at)
+	return nil
+}
+
+func (c Reopen) Apply(todo *Todo, at time.Time) error {
+	if !todo.reopen(c.Now) {
+		return ErrUnchanged
+	}
+	todo.touch(at)
+	return nil
+}
+
+func (c AddComment) Apply(todo *Todo, at time.Time) error {
+	if c.Comment.ParentID != nil && todo.Comment(*c.Comment.ParentID) == nil {
+		return ErrCommentNotFound
+	}
+	todo.Comments = append(todo.Comments, c.Comment)
+	todo.touch(at)
+	return nil
+}
+
+func (c EditComment) Apply(todo *Todo, at time.Time) error {
+	if err := authored(todo, c.CommentID, c.UserID); err != nil {
+		return err
+	}
+	todo.editComment(c.CommentID, c.Content, at)
+	return nil
+}
+
+func (c RemoveComment) Apply(todo *Todo, at time.Time) error {
+	if err := authored(todo, c.CommentID, c.UserID); err != nil {
+		return err
+	}
+	todo.removeComment(c.CommentID, at)
+	return nil
+}
+
+// authored returns why the user may not change the comment: there is no such comment, or someone else made it
+func authored(todo *Todo, commentID, userID uuid.UUID) error {
+	comment := todo.Comment(commentID)
+	if comment == nil {
+		return ErrCommentNotFound
+	}
+	if comment.UserID != userID {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (c StartTimer) Apply(todo *Todo, at time.Time) error {
+	todo.startTimer(c.EntryID, c.UserID, at)
+	return nil
+}
+
+func (c StopTimer) Apply(todo *Todo, at time.Time) error {
+	if _, ok := todo.StopTimer(c.UserID, at); !ok {
+		return ErrUnchanged
+	}
+	return nil
+}
+
+func (c AddTimeEntry) Apply(todo *Todo, at time.Time) error {
+	todo.addTimeEntry(c.Entry, at)
+	return nil
+}
+
+func (c RemoveTimeEntry) Apply(todo *Todo, at time.Time) error {
+	entry := todo.TimeEntry(c.EntryID)
+	if entry == nil {
+		return ErrEntryNotFound
+	}
+	if entry.UserID != c.UserID {
+		return ErrPermissionDenied
+	}
+	todo.removeTimeEntry(c.EntryID, at)
+	return nil
+}
+
+func (c AddAttachment) Apply(todo *Todo, at time.Time) error {
+	todo.addAttachment(c.Attachment, at)
+	return nil
+}
+
+func (c RemoveAttachment) Apply(todo *Todo, at time.Time) error {
+	if !todo.removeAttachment(c.AttachmentID, at) {
+		return ErrUnchanged
+	}
+	return nil
+}
+
+func (c AddPomodoro) Apply(todo *Todo, at time.Time) error {
+	todo.AddPomodoro(c.Pomodoro)
+	todo.touch(at)
+	return nil
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentThread is a comment with the replies to it, and to those replies, in the order they were made
+type CommentThread struct {
+	Comment Comment
+	Replies []CommentThread
+}
+
+// Edited returns true when the content has been changed since the comment was made
+func (c Comment) Edited() bool {
+	return c.EditedAt != nil
+}
+
+// AddComment adds a comment to the todo
+func (t *Todo) AddComment(content string, userID uuid.UUID) Comment {
+	return t.addComment(content, userID, nil)
+}
+
+// AddReply adds a comment replying to another comment on the todo
+//
+// It returns false when the todo has no comment with the parent ID.
+func (t *Todo) AddReply(parentID uuid.UUID, content string, userID uuid.UUID) (Comment, bool) {
+	if t.Comment(parentID) == nil {
+		return Comment{}, false
+	}
+	return t.addComment(content, userID, &parentID), true
+}
+
+func (t *Todo) addComment(content string, userID uuid.UUID, parentID *uuid.UUID) Comment {
+	comment := Comment{
+		ID:        uuid.New(),
+		Content:   content,
+		CreatedAt: time.Now(),
+		UserID:    userID,
+		ParentID:  parentID,
+	}
+	t.Comments = append(t.Comments, comment)
+	t.touch(time.Now())
+	return comment
+}
+
+// Comment returns the comment with the ID, or nil when the todo has no such comment
+func (t *Todo) Comment(id uuid.UUID) *Comment {
+	for i := range t.Comments {
+		if t.Comments[i].ID == id {
+			return &t.Comments[i]
+		}
+	}
+	return nil
+}
+
+// EditComment replaces the content of a comment and marks it as edited
+func (t *Todo) EditComment(id uuid.UUID, content string) bool {
+	return t.editComment(id, content, time.Now())
+}
+
+func (t *Todo) editComment(id uuid.UUID, content string, now time.Time) bool {
+	comment := t.Comment(id)
+	if comment == nil {
+		return false
+	}
+	comment.Content = content
+	comment.EditedAt = &now
+	t.touch(now)
+	return true
+}
+
+// RemoveComment removes a comment together with all the replies below it
+func (t *Todo) RemoveComment(id uuid.UUID) bool {
+	return t.removeComment(id, time.Now())
+}
+
+func (t *Todo) removeComment(id uuid.UUID, now time.Time) bool {
+	if t.Comment(id) == nil {
+		return false
+	}
+
+	removed := map[uuid.UUID]bool{id: true}
+	// replies always follow the comment they reply to
+	comments := make([]Comment, 0, len(t.Comments))
+	for _, comment := range t.Comments {
+		if removed[comment.ID] || comment.ParentID != nil && removed[*comment.ParentID] {
+			removed[comment.ID] = true
+			continue
+		}
+		comments = append(comments, comment)
+	}
+	t.Comments = comments
+	t.touch(now)
+	return true
+}
+
+// CommentThreads returns the comments arranged as threads of replies
+//
+// Replies to a comment that no longer exists start a thread of their own.
+func (t *Todo) CommentThreads() []CommentThread {
+	replies := make(map[uuid.UUID][]Comment)
+	var roots []Comment
+	for _, comment := range t.Comments {
+		if comment.ParentID != nil && t.Comment(*comment.ParentID) != nil {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+			continue
+		}
+		roots = append(roots, comment)
+	}
+
+	var thread func(comment Comment) CommentThread
+	thread = func(comment Comment) CommentThread {
+		ct := CommentThread{Comment: comment}
+		for _, reply := range replies[comment.ID] {
+			ct.Replies = append(ct.Replies, thread(reply))
+		}
+		return ct
+	}
+
+	threads := make([]CommentThread, len(roots))
+	for i, root := range roots {
+		threads[i] = thread(root)
+	}
+	return threads
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTodo_AddReply(t *testing.T) {
+	var userID = uuid.New()
+	todo := NewTodo("test")
+	question := todo.AddComment("question", userID)
+
+	reply, ok := todo.AddReply(question.ID, "answer", userID)
+	if !ok {
+		t.Fatalf("AddReply() = false, want true")
+	}
+	if reply.ParentID == nil || *reply.ParentID != question.ID {
+		t.Errorf("AddReply().ParentID = %v, want %v", reply.ParentID, question.ID)
+	}
+	if _, ok = todo.AddReply(uuid.New(), "lost", userID); ok {
+		t.Errorf("AddReply() to an unknown comment = true, want false")
+	}
+	if len(todo.Comments) != 2 {
+		t.Errorf("Comments = %d, want 2", len(todo.Comments))
+	}
+}
+
+func TestTodo_EditComment(t *testing.T) {
+	todo := NewTodo("test")
+	comment := todo.AddComment("first", uuid.New())
+	if comment.Edited() {
+		t.Fatalf("Edited() = true before editing, want false")
+	}
+
+	if !todo.EditComment(comment.ID, "second") {
+		t.Fatalf("EditComment() = false, want true")
+	}
+	edited := todo.Comment(comment.ID)
+	if edited.Content != "second" || !edited.Edited() {
+		t.Errorf("EditComment() left %+v, want the content changed and marked as edited", edited)
+	}
+	if todo.EditComment(uuid.New(), "other") {
+		t.Errorf("EditComment() of an unknown comment = true, want false")
+	}
+}
+
+func TestTodo_RemoveComment(t *testing.T) {
+	var userID = uuid.New()
+	todo := NewTodo("test")
+	first := todo.AddComment("first", userID)
+	reply, _ := todo.AddReply(first.ID, "reply", userID)
+	todo.AddReply(reply.ID, "reply to the reply", userID)
+	second := todo.AddComment("second", userID)
+
+	if !todo.RemoveComment(first.ID) {
+		t.Fatalf("RemoveComment() = false, want true")
+	}
+	if len(todo.Comments) != 1 || todo.Comments[0].ID != second.ID {
+		t.Errorf("Comments = %+v, want only the second comment", todo.Comments)
+	}
+	if todo.RemoveComment(first.ID) {
+		t.Errorf("RemoveComment() of a removed comment = true, want false")
+	}
+}
+
+func TestTodo_CommentThreads(t *testing.T) {
+	var userID = uuid.New()
+	todo := NewTodo("test")
+	first := todo.AddComment("first", userID)
+	second := todo.AddComment("second", userID)
+	reply, _ := todo.AddReply(first.ID, "reply", userID)
+	nested, _ := todo.AddReply(reply.ID, "nested", userID)
+	// a reply whose parent has gone missing
+	orphan := Comment{ID: uuid.New(), Content: "orphan", ParentID: &nested.UserID}
+	todo.Comments = append(todo.Comments, orphan)
+
+	threads := todo.CommentThreads()
+	if len(threads) != 3 {
+		t.Fatalf("CommentThreads() = %d threads, want 3", len(threads))
+	}
+	if threads[0].Comment.ID != first.ID || threads[1].Comment.ID != second.ID || threads[2].Comment.ID != orphan.ID {
+		t.Errorf("CommentThreads() are not in the order the comments were made")
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].Comment.ID != reply.ID {
+		t.Fatalf("CommentThreads()[0].Replies = %+v, want the reply", threads[0].Replies)
+	}
+	if len(threads[0].Replies[0].Replies) != 1 || threads[0].Replies[0].Replies[0].Comment.ID != nested.ID {
+		t.Errorf("CommentThreads()[0].Replies[0].Replies = %+v, want the nested reply", threads[0].Replies[0].Replies)
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+var (
+	ErrTodoNotFound     = errors.New("todo not found")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrEntryNotFound    = errors.New("time entry not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrVersionConflict  = errors.New("todo was changed by someone else")
+	// ErrUnchanged is returned by changes with nothing to do, such as waking a todo that is not snoozed
+	ErrUnchanged = errors.New("nothing to change")
+)
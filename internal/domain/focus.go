@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FocusPhase is a part of a pomodoro cycle
+type FocusPhase string
+
+const (
+	FocusWork       FocusPhase = "work"
+	FocusShortBreak FocusPhase = "short_break"
+	FocusLongBreak  FocusPhase = "long_break"
+)
+
+// FocusSettings are the lengths of the phases of a pomodoro cycle
+type FocusSettings struct {
+	Work       time.Duration
+	ShortBreak time.Duration
+	LongBreak  time.Duration
+	// LongBreakEvery is how many pomodoros are worked before a long break instead of a short one
+	LongBreakEvery int
+}
+
+// DefaultFocusSettings are the classic 25 minute pomodoros with a long break after every fourth
+var DefaultFocusSettings = FocusSettings{
+	Work:           25 * time.Minute,
+	ShortBreak:     5 * time.Minute,
+	LongBreak:      15 * time.Minute,
+	LongBreakEvery: 4,
+}
+
+// Pomodoro is a work phase a user completed while focusing on a todo
+type Pomodoro struct {
+	UserID uuid.UUID
+	Start  time.Time
+	End    time.Time
+}
+
+// FocusSession is a user working through pomodoro cycles on a todo
+//
+// A phase runs until its time is up and then waits for the user to start the next one, so time
+// away from the app is never counted as focus.
+type FocusSession struct {
+	UserID   uuid.UUID
+	TodoID   uuid.UUID
+	Settings FocusSettings
+	Phase    FocusPhase
+	// Running is false while the phase waits to be started
+	Running bool
+	// PhaseStart and PhaseEnd are when the running phase started and when its time is up
+	PhaseStart time.Time
+	PhaseEnd   time.Time
+	// Completed is how many pomodoros the session has completed
+	Completed int
+	StartedAt time.Time
+	// EndedAt is set when the user stops focusing; the session is kept for its settings
+	EndedAt *time.Time
+}
+
+// NewFocusSession creates a session waiting for its first pomodoro to be started
+func NewFocusSession(userID, todoID uuid.UUID, settings FocusSettings, now time.Time) *FocusSession {
+	return &FocusSession{
+		UserID:    userID,
+		TodoID:    todoID,
+		Settings:  settings,
+		Phase:     FocusWork,
+		StartedAt: now,
+	}
+}
+
+// Active returns true until the user stops focusing
+func (s *FocusSession) Active() bool {
+	return s.EndedAt == nil
+}
+
+// Length returns how long the phase lasts
+func (s *FocusSession) Length(phase FocusPhase) time.Duration {
+	switch phase {
+	case FocusShortBreak:
+		return s.Settings.ShortBreak
+	case FocusLongBreak:
+		return s.Settings.LongBreak
+	default:
+		return s.Settings.Work
+	}
+}
+
+// Remaining returns how long the running phase has left, or the length of a waiting one
+func (s *FocusSession) Remaining(now time.Time) time.Duration {
+	if !s.Running {
+		return s.Length(s.Phase)
+	}
+	if now.After(s.PhaseEnd) {
+		return 0
+	}
+	return s.PhaseEnd.Sub(now)
+}
+
+// StartPhase starts the time of the waiting phase
+func (s *FocusSession) StartPhase(now time.Time) {
+	if s.Running {
+		return
+	}
+	s.Running = true
+	s.PhaseStart = now
+	s.PhaseEnd = now.Add(s.Length(s.Phase))
+}
+
+// Advance ends the running phase once its time is up, returning the pomodoro when it was a work phase
+func (s *FocusSession) Advance(now time.Time) (Pomodoro, bool) {
+	if !s.Running || now.Before(s.PhaseEnd) {
+		return Pomodoro{}, false
+	}
+
+	ended := s.Phase
+	s.next()
+	if ended != FocusWork {
+		return Pomodoro{}, false
+	}
+	s.Completed++
+	// the break after a pomodoro depends on how many there have been
+	if s.Settings.LongBreakEvery > 0 && s.Completed%s.Settings.LongBreakEvery == 0 {
+		s.Phase = FocusLongBreak
+	}
+	return Pomodoro{UserID: s.UserID, Start: s.PhaseStart, End: s.PhaseEnd}, true
+}
+
+// Skip gives up on the phase, running or waiting, and moves on to the next one without completing anything
+func (s *FocusSession) Skip() {
+	s.next()
+}
+
+// Stop ends the session
+func (s *FocusSession) Stop(now time.Time) {
+	s.Running = false
+	s.EndedAt = &now
+}
+
+// next leaves the phase waiting to be started after the current one
+func (s *FocusSession) next() {
+	s.Running = false
+	switch s.Phase {
+	case FocusWork:
+		s.Phase = FocusShortBreak
+	default:
+		s.Phase = FocusWork
+	}
+}
+
+// AddPomodoro records a pomodoro completed on the todo
+func (t *Todo) AddPomodoro(pomodoro Pomodoro) {
+	t.Pomodoros = append(t.Pomodoros, pomodoro)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+type FocusSessionRepository interface {
+	// Save keeps the session as the latest of its user
+	Save(session *FocusSession)
+	// Get returns the latest session of the user, or nil when they have never focused
+	Get(userID uuid.UUID) *FocusSession
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// FocusSessions is an in-memory FocusSessionRepository keeping the latest session of each user, safe for use by
+// concurrent requests
+//
+// The sessions are kept and handed out as copies, so a changed session is only seen by others once it is saved.
+type FocusSessions struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]*FocusSession
+}
+
+func NewFocusSessions() *FocusSessions {
+	return &FocusSessions{sessions: make(map[uuid.UUID]*FocusSession)}
+}
+
+func (f *FocusSessions) Save(session *FocusSession) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	saved := *session
+	f.sessions[session.UserID] = &saved
+}
+
+func (f *FocusSessions) Get(userID uuid.UUID) *FocusSession {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	session, ok := f.sessions[userID]
+	if !ok {
+		return nil
+	}
+	c := *session
+	return &c
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFocusSession(t *testing.T) {
+	settings := FocusSettings{Work: 25 * time.Minute, ShortBreak: 5 * time.Minute, LongBreak: 15 * time.Minute, LongBreakEvery: 2}
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	session := NewFocusSession(uuid.New(), uuid.New(), settings, now)
+
+	// nothing happens until a phase is started
+	if _, ok := session.Advance(now.Add(time.Hour)); ok || session.Remaining(now) != 25*time.Minute {
+		t.Fatalf("a waiting session advanced")
+	}
+
+	var phases []FocusPhase
+	var pomodoros []Pomodoro
+	for i := 0; i < 4; i++ {
+		session.StartPhase(now)
+		if _, ok := session.Advance(now.Add(session.Length(session.Phase) - time.Second)); ok {
+			t.Fatalf("the phase ended early")
+		}
+		now = now.Add(session.Length(session.Phase))
+		if pomodoro, ok := session.Advance(now); ok {
+			pomodoros = append(pomodoros, pomodoro)
+		}
+		if session.Running {
+			t.Fatalf("the next phase started without the user")
+		}
+		phases = append(phases, session.Phase)
+	}
+
+	want := []FocusPhase{FocusShortBreak, FocusWork, FocusLongBreak, FocusWork}
+	for i := range want {
+		if phases[i] != want[i] {
+			t.Errorf("phases = %v, want %v", phases, want)
+			break
+		}
+	}
+	if len(pomodoros) != 2 || session.Completed != 2 || pomodoros[1].End.Sub(pomodoros[1].Start) != 25*time.Minute {
+		t.Errorf("pomodoros = %v, want two of 25 minutes", pomodoros)
+	}
+
+	// skipping work completes nothing
+	session.StartPhase(now)
+	session.Skip()
+	if session.Phase != FocusShortBreak || session.Completed != 2 {
+		t.Errorf("Skip() left %s with %d completed", session.Phase, session.Completed)
+	}
+
+	session.Stop(now)
+	if session.Active() {
+		t.Errorf("the session is still active after Stop()")
+	}
+}
+
+func TestFocusSessions(t *testing.T) {
+	sessions := NewFocusSessions()
+	userID := uuid.New()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				session := NewFocusSession(userID, uuid.New(), DefaultFocusSettings, now)
+				sessions.Save(session)
+				sessions.Get(userID).StartPhase(now)
+				sessions.Save(NewFocusSession(uuid.New(), uuid.New(), DefaultFocusSettings, now))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the sessions handed out are copies, changed only by saving them
+	if session := sessions.Get(userID); session == nil || session.Running {
+		t.Errorf("Get() = %+v, want a session waiting to be started", session)
+	}
+	if sessions.Get(uuid.New()) != nil {
+		t.Error("Get() of a user who never focused is not nil")
+	}
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// HabitDay is a period of a recurring todo as it appears in its history
+type HabitDay struct {
+	// Start is when the period begins
+	Start time.Time
+	// Done is how many times the todo was completed in the period
+	Done int
+	// Missed is true when the period passed without the todo being completed
+	Missed bool
+	// Tracked is false for periods before the habit began and for those still to come
+	Tracked bool
+}
+
+// PeriodStart returns the start of the period holding the time: its day, its week from Monday, its month or its year
+func (r RecurringConfig) PeriodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch strings.ToLower(r.Frequency) {
+	case "weekly":
+		// Monday begins the week
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "monthly":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case "yearly":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// NextPeriod returns the start of the period after the one beginning at start
+func (r RecurringConfig) NextPeriod(start time.Time) time.Time {
+	switch strings.ToLower(r.Frequency) {
+	case "weekly":
+		return start.AddDate(0, 0, 7)
+	case "monthly":
+		return start.AddDate(0, 1, 0)
+	case "yearly":
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// PreviousPeriod returns the start of the period before the one beginning at start
+func (r RecurringConfig) PreviousPeriod(start time.Time) time.Time {
+	switch strings.ToLower(r.Frequency) {
+	case "weekly":
+		return start.AddDate(0, 0, -7)
+	case "monthly":
+		return start.AddDate(0, -1, 0)
+	case "yearly":
+		return start.AddDate(-1, 0, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+// periodKey names the period holding the time by the date it starts on, as the time falls in the location
+//
+// Periods are compared by name rather than kept as times, as equal times read back from the log or a backup
+// differ from those made by the clock in their location and monotonic reading, and so never match as map
+// keys. Every period of a todo is worked out in the location of now, so that an occurrence recorded in UTC
+// falls on the day it did for the person looking at it.
+func (r RecurringConfig) periodKey(t time.Time, loc *time.Location) string {
+	return r.PeriodStart(t.In(loc)).Format(time.DateOnly)
+}
+
+// habitStart returns the start of the first period the recurring todo is tracked for, in the location
+func (t *Todo) habitStart(loc *time.Location) time.Time {
+	start := t.CreatedAt
+	if len(t.Occurrences) > 0 && t.Occurrences[0].Before(start) {
+		start = t.Occurrences[0]
+	}
+	return t.Recurring.PeriodStart(start.In(loc))
+}
+
+// Streak returns how many periods in a row the recurring todo has been completed, up to now and at best
+//
+// The period holding now does not break the current streak while it is still open.
+func (t *Todo) Streak(now time.Time) (current, longest int) {
+	if t.Recurring == nil || len(t.Occurrences) == 0 {
+		return 0, 0
+	}
+	loc := now.Location()
+	done := t.donePeriods(loc)
+
+	run := 0
+	var previous time.Time
+	for _, occurrence := range t.Occurrences {
+		period := t.Recurring.PeriodStart(occurrence.In(loc))
+		switch {
+		case period.Equal(previous):
+			continue
+		case !previous.IsZero() && t.Recurring.NextPeriod(previous).Equal(period):
+			run++
+		default:
+			run = 1
+		}
+		previous = period
+		if run > longest {
+			longest = run
+		}
+	}
+
+	period := t.Recurring.PeriodStart(now)
+	if !done[t.Recurring.periodKey(period, loc)] {
+		period = t.Recurring.PreviousPeriod(period)
+	}
+	for done[t.Recurring.periodKey(period, loc)] {
+		current++
+		period = t.Recurring.PreviousPeriod(period)
+	}
+	return current, longest
+}
+
+// MissedOccurrences returns the starts of the periods that passed without the recurring todo being completed
+func (t *Todo) MissedOccurrences(now time.Time) []time.Time {
+	if t.Recurring == nil {
+		return nil
+	}
+	loc := now.Location()
+	done := t.donePeriods(loc)
+
+	var missed []time.Time
+	current := t.Recurring.PeriodStart(now)
+	for period := t.habitStart(loc); period.Before(current); period = t.Recurring.NextPeriod(period) {
+		if t.Recurring.EndDate != nil && period.After(*t.Recurring.EndDate) {
+			break
+		}
+		if !done[t.Recurring.periodKey(period, loc)] {
+			missed = append(missed, period)
+		}
+	}
+	return missed
+}
+
+// HabitHistory returns the last periods of the recurring todo, oldest first, ending with the one holding now
+func (t *Todo) HabitHistory(now time.Time, periods int) []HabitDay {
+	if t.Recurring == nil || periods <= 0 {
+		return nil
+	}
+	loc := now.Location()
+
+	counts := make(map[string]int)
+	for _, occurrence := range t.Occurrences {
+		counts[t.Recurring.periodKey(occurrence, loc)]++
+	}
+
+	current := t.Recurring.PeriodStart(now)
+	start := t.habitStart(loc)
+	history := make([]HabitDay, periods)
+	period := current
+	for i := periods - 1; i >= 0; i-- {
+		ended := t.Recurring.EndDate != nil && period.After(*t.Recurring.EndDate)
+		tracked := !period.Before(start) && !ended
+		done := counts[t.Recurring.periodKey(period, loc)]
+		history[i] = HabitDay{
+			Start:   period,
+			Done:    done,
+			Tracked: tracked,
+			Missed:  tracked && done == 0 && period.Before(current),
+		}
+		period = t.Recurring.PreviousPeriod(period)
+	}
+	return history
+}
+
+// Reopen opens a completed recurring todo again once the period it was completed in has passed
+func (t *Todo) Reopen(now time.Time) bool {
+	if !t.reopen(now) {
+		return false
+	}
+	t.touch(now)
+	return true
+}
+
+func (t *Todo) reopen(now time.Time) bool {
+	if t.Recurring == nil || !t.Completed || len(t.Occurrences) == 0 {
+		return false
+	}
+	if t.Recurring.EndDate != nil && now.After(*t.Recurring.EndDate) {
+		return false
+	}
+	last := t.Occurrences[len(t.Occurrences)-1]
+	if !t.Recurring.PeriodStart(last.In(now.Location())).Before(t.Recurring.PeriodStart(now)) {
+		return false
+	}
+	t.Completed = false
+	return true
+}
+
+func (t *Todo) recordOccurrence(at time.Time) {
+	t.Occurrences = append(t.Occurrences, at)
+	t.Recurring.LastOccurrence = at
+}
+
+// undoOccurrence takes back the last occurrence when it was recorded in the period holding now
+func (t *Todo) undoOccurrence(now time.Time) {
+	if len(t.Occurrences) == 0 {
+		return
+	}
+	last := t.Occurrences[len(t.Occurrences)-1]
+	if t.Recurring.periodKey(last, now.Location()) == t.Recurring.periodKey(now, now.Location()) {
+		t.Occurrences = t.Occurrences[:len(t.Occurrences)-1]
+	}
+}
+
+// donePeriods returns the keys of the periods the recurring todo was completed in
+func (t *Todo) donePeriods(loc *time.Location) map[string]bool {
+	done := make(map[string]bool)
+	for _, occurrence := range t.Occurrences {
+		done[t.Recurring.periodKey(occurrence, loc)] = true
+	}
+	return done
+}
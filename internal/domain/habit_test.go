@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurringConfig_PeriodStart(t *testing.T) {
+	// a Thursday afternoon
+	at := time.Date(2024, time.March, 7, 15, 30, 0, 0, time.UTC)
+	tests := map[string]struct {
+		frequency string
+		want      time.Time
+	}{
+		"Daily":   {frequency: "daily", want: time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)},
+		"Weekly":  {frequency: "weekly", want: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)},
+		"Monthly": {frequency: "monthly", want: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		"Yearly":  {frequency: "yearly", want: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := RecurringConfig{Frequency: tt.frequency}
+			if got := r.PeriodStart(at); !got.Equal(tt.want) {
+				t.Errorf("PeriodStart() = %v, want %v", got, tt.want)
+			}
+			if got := r.PreviousPeriod(r.NextPeriod(tt.want)); !got.Equal(tt.want) {
+				t.Errorf("PreviousPeriod(NextPeriod()) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodo_Streak(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 8, 0, 0, 0, time.UTC) }
+	tests := map[string]struct {
+		frequency   string
+		occurrences []time.Time
+		now         time.Time
+		current     int
+		longest     int
+		missed      int
+	}{
+		"Never": {
+			frequency: "daily",
+			now:       day(10),
+			missed:    9,
+		},
+		"TodayStillOpen": {
+			frequency:   "daily",
+			occurrences: []time.Time{day(1), day(2), day(4), day(5), day(6), day(7), day(8)},
+			now:         day(9),
+			current:     5,
+			longest:     5,
+			missed:      1,
+		},
+		"DoneToday": {
+			frequency:   "daily",
+			occurrences: []time.Time{day(7), day(8), day(8).Add(time.Hour), day(9)},
+			now:         day(9),
+			current:     3,
+			longest:     3,
+			missed:      6,
+		},
+		"Broken": {
+			frequency:   "daily",
+			occurrences: []time.Time{day(1), day(2), day(3), day(6)},
+			now:         day(8),
+			current:     0,
+			longest:     3,
+			missed:      3,
+		},
+		"Weekly": {
+			// the 1st is a Friday, so the three fall in three weeks in a row
+			frequency:   "weekly",
+			occurrences: []time.Time{day(1), day(5), day(12)},
+			now:         day(20),
+			current:     3,
+			longest:     3,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			todo := NewTodo("Feed the cat")
+			todo.CreatedAt = day(1)
+			todo.Recurring = &RecurringConfig{Frequency: tt.frequency}
+			todo.Occurrences = tt.occurrences
+			current, longest := todo.Streak(tt.now)
+			if current != tt.current || longest != tt.longest {
+				t.Errorf("Streak() = %d, %d, want %d, %d", current, longest, tt.current, tt.longest)
+			}
+			if got := len(todo.MissedOccurrences(tt.now)); got != tt.missed {
+				t.Errorf("MissedOccurrences() = %d periods, want %d", got, tt.missed)
+			}
+		})
+	}
+}
+
+func TestTodo_HabitHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 8, 0, 0, 0, time.UTC) }
+	todo := NewTodo("Feed the cat")
+	todo.CreatedAt = day(3)
+	todo.Recurring = &RecurringConfig{Frequency: "daily"}
+	todo.Occurrences = []time.Time{day(3), day(5), day(6)}
+
+	history := todo.HabitHistory(day(6), 5)
+	want := []HabitDay{
+		{Start: day(2).Truncate(24 * time.Hour)},
+		{Start: day(3).Truncate(24 * time.Hour), Done: 1, Tracked: true},
+		{Start: day(4).Truncate(24 * time.Hour), Missed: true, Tracked: true},
+		{Start: day(5).Truncate(24 * time.Hour), Done: 1, Tracked: true},
+		{Start: day(6).Truncate(24 * time.Hour), Done: 1, Tracked: true},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("HabitHistory() = %d periods, want %d", len(history), len(want))
+	}
+	for i := range want {
+		if !history[i].Start.Equal(want[i].Start) || history[i].Done != want[i].Done ||
+			history[i].Missed != want[i].Missed || history[i].Tracked != want[i].Tracked {
+			t.Errorf("HabitHistory()[%d] = %+v, want %+v", i, history[i], want[i])
+		}
+	}
+}
+
+func TestTodo_Occurrences(t *testing.T) {
+	todo := NewTodo("Feed the cat")
+	todo.SetRecurring("daily", nil)
+
+	// completing records an occurrence, taking it back the same day removes it
+	todo.Update(true, todo.Description)
+	if len(todo.Occurrences) != 1 || !todo.Recurring.LastOccurrence.Equal(todo.Occurrences[0]) {
+		t.Fatalf("Occurrences = %v after completing", todo.Occurrences)
+	}
+	todo.Update(false, todo.Description)
+	if len(todo.Occurrences) != 0 {
+		t.Fatalf("Occurrences = %v after opening again", todo.Occurrences)
+	}
+
+	// editing the description alone records nothing
+	todo.Update(false, "Feed the cats")
+	if len(todo.Occurrences) != 0 {
+		t.Fatalf("Occurrences = %v after editing", todo.Occurrences)
+	}
+
+	// a todo completed today stays completed until tomorrow
+	todo.Update(true, todo.Description)
+	if todo.Reopen(time.Now()) {
+		t.Errorf("Reopen() reopened the todo in the period it was completed")
+	}
+	if !todo.Reopen(time.Now().AddDate(0, 0, 1)) || todo.Completed {
+		t.Errorf("Reopen() left the todo completed the next day")
+	}
+
+	// todos that do not repeat keep no history
+	once := NewTodo("Bake a cake")
+	once.Update(true, once.Description)
+	if len(once.Occurrences) != 0 || once.Reopen(time.Now().AddDate(0, 0, 1)) {
+		t.Errorf("a todo that does not repeat recorded occurrences or reopened")
+	}
+}
+
+func TestTodo_Streak_location(t *testing.T) {
+	// occurrences read back from the log are in UTC without a monotonic reading, while now comes from the clock
+	newYork := time.FixedZone("EST", -5*60*60)
+	day := func(d, hour int) time.Time { return time.Date(2024, time.March, d, hour, 0, 0, 0, newYork) }
+	todo := NewTodo("Feed the cat")
+	todo.CreatedAt = day(6, 8).UTC()
+	todo.Recurring = &RecurringConfig{Frequency: "daily"}
+	// the last is the evening of the 8th in New York, though the 9th in UTC
+	todo.Occurrences = []time.Time{day(6, 8).UTC(), day(7, 8).UTC(), day(8, 21).UTC()}
+	todo.Completed = true
+
+	now := day(8, 22)
+	if current, longest := todo.Streak(now); current != 3 || longest != 3 {
+		t.Errorf("Streak() = %d, %d, want 3, 3", current, longest)
+	}
+	if missed := todo.MissedOccurrences(now); len(missed) != 0 {
+		t.Errorf("MissedOccurrences() = %v, want none", missed)
+	}
+	if history := todo.HabitHistory(now, 1); history[0].Done != 1 {
+		t.Errorf("HabitHistory() = %+v, want today done", history)
+	}
+	if todo.Reopen(now) {
+		t.Errorf("Reopen() reopened the todo on the evening it was completed")
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind string
+
+const (
+	// NotificationMention is sent when a user is mentioned in a comment
+	NotificationMention NotificationKind = "mention"
+	// NotificationAssignment is sent when a todo is assigned to a user
+	NotificationAssignment NotificationKind = "assignment"
+	// NotificationSnooze is sent when a todo snoozed by a user comes back
+	NotificationSnooze NotificationKind = "snooze"
+)
+
+// Notification is an entry in the inbox of a user
+type Notification struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Kind   NotificationKind
+	TodoID uuid.UUID
+	// CommentID is the comment a mention was made in
+	CommentID *uuid.UUID
+	// ActorID is the user who caused the notification, when known
+	ActorID   *uuid.UUID
+	Message   string
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+// NewNotification creates a new unread notification
+func NewNotification(userID uuid.UUID, kind NotificationKind, todoID uuid.UUID, message string) *Notification {
+	return &Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Kind:      kind,
+		TodoID:    todoID,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Read returns true once the notification has been marked as read
+func (n *Notification) Read() bool {
+	return n.ReadAt != nil
+}
+
+// MarkRead marks the notification as read at the time; it keeps the time it was first read
+func (n *Notification) MarkRead(at time.Time) {
+	if n.ReadAt == nil {
+		n.ReadAt = &at
+	}
+}
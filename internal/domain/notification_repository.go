@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Add(notification *Notification)
+	Get(id uuid.UUID) *Notification
+	// ForUser returns the notifications of the user, newest first
+	ForUser(userID uuid.UUID) []*Notification
+	// MarkRead marks the notification as read at the time, keeping the time it was first read
+	MarkRead(id uuid.UUID, at time.Time)
+}
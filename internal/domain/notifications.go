@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notifications is an in-memory NotificationRepository, oldest first, safe for use by concurrent requests
+//
+// The notifications are handed out as copies; they are only changed through the repository.
+type Notifications struct {
+	mu            sync.RWMutex
+	notifications []*Notification
+}
+
+func NewNotifications() *Notifications {
+	return &Notifications{}
+}
+
+// Add adds a notification
+func (n *Notifications) Add(notification *Notification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	added := *notification
+	n.notifications = append(n.notifications, &added)
+}
+
+// Get returns the notification with the ID, or nil when there is none
+func (n *Notifications) Get(id uuid.UUID) *Notification {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if notification := n.find(id); notification != nil {
+		c := *notification
+		return &c
+	}
+	return nil
+}
+
+// ForUser returns the notifications of the user, newest first
+func (n *Notifications) ForUser(userID uuid.UUID) []*Notification {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	list := make([]*Notification, 0)
+	for i := len(n.notifications) - 1; i >= 0; i-- {
+		if notification := n.notifications[i]; notification.UserID == userID {
+			c := *notification
+			list = append(list, &c)
+		}
+	}
+	return list
+}
+
+// MarkRead marks the notification with the ID as read at the time; one already read keeps the time it was
+// first read
+func (n *Notifications) MarkRead(id uuid.UUID, at time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if notification := n.find(id); notification != nil {
+		notification.MarkRead(at)
+	}
+}
+
+func (n *Notifications) find(id uuid.UUID) *Notification {
+	for _, notification := range n.notifications {
+		if notification.ID == id {
+			return notification
+		}
+	}
+	return nil
+}
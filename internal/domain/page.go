@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+)
+
+const (
+	// DefaultPageSize is how many todos a page holds when no limit is asked for
+	DefaultPageSize = 50
+	// MaxPageSize is the most todos a page holds
+	MaxPageSize = 200
+)
+
+// PageRequest asks for one page of todos in a sort order
+type PageRequest struct {
+	Sort SortOrder
+	// Cursor is the Next of the page before, or empty for the first page
+	Cursor string
+	// Limit is how many todos the page holds at most; zero asks for DefaultPageSize
+	Limit int
+}
+
+// Page is one page of a sorted list of todos
+type Page struct {
+	Todos []*Todo
+	// Next is the cursor of the page after this one, or empty on the last page
+	Next string
+}
+
+// cursor is where a page ended, written out as base64 JSON
+type cursor struct {
+	Sort  SortOrder  `json:"s,omitempty"`
+	After sortRecord `json:"a"`
+}
+
+// Page sorts the todos, given in their manual order, and returns those after the cursor
+//
+// The cursor holds what the last todo of the page before was sorted by and its ID rather than where it was
+// on the page, so the page picks up in the right place even when that todo has since changed or gone, or
+// others before it have. False is returned when the cursor was not made for the same sort order.
+func (r PageRequest) Page(todos []*Todo) (Page, bool) {
+	records := r.Sort.records(todos)
+
+	if r.Cursor != "" {
+		after, ok := decodeCursor(r.Cursor, r.Sort)
+		if !ok {
+			return Page{}, false
+		}
+		// the manual order is taken from where the todo now stands, so todos gone before it are not skipped over
+		for _, record := range records {
+			if record.ID == after.ID {
+				after.Position = record.Position
+				break
+			}
+		}
+		keys := r.Sort.keys()
+		start := sort.Search(len(records), func(i int) bool {
+			return compareRecords(keys, records[i], after) > 0
+		})
+		records = records[start:]
+	}
+
+	limit := r.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultPageSize
+	case limit > MaxPageSize:
+		limit = MaxPageSize
+	}
+
+	page := Page{Todos: make([]*Todo, 0, limit)}
+	for i, record := range records {
+		if i == limit {
+			page.Next = encodeCursor(r.Sort, records[i-1])
+			break
+		}
+		page.Todos = append(page.Todos, record.todo)
+	}
+	return page, true
+}
+
+func encodeCursor(order SortOrder, after sortRecord) string {
+	data, _ := json.Marshal(cursor{Sort: order, After: after})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, order SortOrder) (sortRecord, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return sortRecord{}, false
+	}
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil || c.Sort != order {
+		return sortRecord{}, false
+	}
+	return c.After, true
+}
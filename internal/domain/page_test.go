@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPageRequest_Page(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	todos := make([]*Todo, 0, 7)
+	for i := 0; i < 7; i++ {
+		todos = append(todos, &Todo{
+			ID:          uuid.New(),
+			Description: fmt.Sprintf("todo %d", i),
+			Priority:    Priority(i % 3),
+			CreatedAt:   now.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	descriptions := func(todos []*Todo) string {
+		var s string
+		for _, todo := range todos {
+			s += todo.Description[5:]
+		}
+		return s
+	}
+
+	tests := map[string]struct {
+		order SortOrder
+		limit int
+		want  []string
+	}{
+		"Manual":   {order: SortManual, limit: 3, want: []string{"012", "345", "6"}},
+		"Priority": {order: "priority,-created", limit: 2, want: []string{"25", "14", "03", "6"}},
+		"Whole":    {order: SortCreated, want: []string{"6543210"}},
+		"Exact":    {order: SortManual, limit: 7, want: []string{"0123456"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := PageRequest{Sort: tt.order, Limit: tt.limit}
+			for i, want := range tt.want {
+				page, ok := req.Page(todos)
+				if !ok {
+					t.Fatalf("Page() %d is not ok", i)
+				}
+				if got := descriptions(page.Todos); got != want {
+					t.Fatalf("Page() %d = %s, want %s", i, got, want)
+				}
+				if last := i == len(tt.want)-1; last != (page.Next == "") {
+					t.Fatalf("Page() %d Next = %q", i, page.Next)
+				}
+				req.Cursor = page.Next
+			}
+		})
+	}
+}
+
+func TestPageRequest_Page_changes(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	todos := make([]*Todo, 0, 4)
+	for i := 0; i < 4; i++ {
+		todos = append(todos, &Todo{ID: uuid.New(), Description: fmt.Sprintf("todo %d", i), CreatedAt: now.Add(time.Duration(i) * time.Hour)})
+	}
+
+	page, _ := PageRequest{Sort: SortCreated, Limit: 2}.Page(todos)
+	if len(page.Todos) != 2 || page.Todos[1] != todos[2] {
+		t.Fatalf("Page() = %v", page.Todos)
+	}
+
+	// the last todo of the page goes, and a newer todo turns up that belongs on the first page
+	todos = append(todos[:2], todos[3:]...)
+	todos = append(todos, &Todo{ID: uuid.New(), Description: "todo 4", CreatedAt: now.Add(4 * time.Hour)})
+	next, ok := PageRequest{Sort: SortCreated, Cursor: page.Next, Limit: 2}.Page(todos)
+	if !ok || len(next.Todos) != 2 || next.Todos[0].Description != "todo 1" || next.Todos[1].Description != "todo 0" {
+		t.Errorf("Page() after changes = %v, %v", next.Todos, ok)
+	}
+
+	if _, ok = (PageRequest{Sort: SortDueDate, Cursor: page.Next}).Page(todos); ok {
+		t.Errorf("Page() took a cursor made for another sort order")
+	}
+	if _, ok = (PageRequest{Cursor: "not a cursor"}).Page(todos); ok {
+		t.Errorf("Page() took a broken cursor")
+	}
+}
+
+func TestPageRequest_Page_manual(t *testing.T) {
+	todos := make([]*Todo, 0, 6)
+	for i := 0; i < 6; i++ {
+		todos = append(todos, &Todo{ID: uuid.New(), Description: fmt.Sprintf("todo %d", i)})
+	}
+
+	page, _ := PageRequest{Limit: 3}.Page(todos)
+	if len(page.Todos) != 3 || page.Todos[2] != todos[2] {
+		t.Fatalf("Page() = %v", page.Todos)
+	}
+
+	// todos on the first page go, as when they are deleted or snoozed, so the rest move up
+	todos = todos[2:]
+	next, ok := PageRequest{Cursor: page.Next, Limit: 3}.Page(todos)
+	if !ok || len(next.Todos) != 3 || next.Todos[0].Description != "todo 3" {
+		t.Errorf("Page() after a todo went = %v, %v, want the page to start at todo 3", next.Todos, ok)
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Query selects todos by their details; a zero value field matches every todo
+type Query struct {
+	// Search matches descriptions containing the text, ignoring case, with or without their Markdown formatting
+	Search string
+	// Tags must all be present on a todo
+	Tags       []string
+	Category   string
+	Priority   *Priority
+	DueFrom    *time.Time
+	DueTo      *time.Time
+	AssignedTo *uuid.UUID
+	Completed  *bool
+	// IncludeArchived includes archived todos which are otherwise skipped
+	IncludeArchived bool
+}
+
+// Matches returns true when the todo satisfies every condition of the query
+func (q Query) Matches(todo *Todo) bool {
+	if todo.Archived && !q.IncludeArchived {
+		return false
+	}
+	if q.Search != "" && !containsFold(todo.Description, q.Search) && !containsFold(todo.PlainDescription(), q.Search) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !todo.HasTag(tag) {
+			return false
+		}
+	}
+	if q.Category != "" && !strings.EqualFold(todo.Category, q.Category) {
+		return false
+	}
+	if q.Priority != nil && todo.Priority != *q.Priority {
+		return false
+	}
+	if q.DueFrom != nil && (todo.DueDate == nil || todo.DueDate.Before(*q.DueFrom)) {
+		return false
+	}
+	if q.DueTo != nil && (todo.DueDate == nil || todo.DueDate.After(*q.DueTo)) {
+		return false
+	}
+	if q.AssignedTo != nil && (todo.AssignedTo == nil || *todo.AssignedTo != *q.AssignedTo) {
+		return false
+	}
+	if q.Completed != nil && todo.Completed != *q.Completed {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
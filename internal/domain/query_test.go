@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestQuery_Matches(t *testing.T) {
+	var userID = uuid.New()
+	var due = time.Now().Add(48 * time.Hour)
+	var high = PriorityHigh
+	var low = PriorityLow
+	var completed = true
+	var todo = &Todo{
+		ID:          uuid.New(),
+		Description: "Bake a Cake",
+		Priority:    PriorityHigh,
+		Category:    "Cooking",
+		Tags:        []string{"baking", "dessert"},
+		DueDate:     &due,
+		AssignedTo:  &userID,
+	}
+	tests := map[string]struct {
+		query Query
+		todo  *Todo
+		want  bool
+	}{
+		"Empty": {
+			query: Query{},
+			todo:  todo,
+			want:  true,
+		},
+		"SearchIgnoresCase": {
+			query: Query{Search: "cake"},
+			todo:  todo,
+			want:  true,
+		},
+		"SearchMarkdownText": {
+			query: Query{Search: "chocolate cake"},
+			todo:  &Todo{Description: "Bake a **chocolate** [cake](https://example.com/recipe)"},
+			want:  true,
+		},
+		"AllTags": {
+			query: Query{Tags: []string{"Baking", "dessert"}},
+			todo:  todo,
+			want:  true,
+		},
+		"MissingTag": {
+			query: Query{Tags: []string{"baking", "daily"}},
+			todo:  todo,
+			want:  false,
+		},
+		"Category": {
+			query: Query{Category: "cooking"},
+			todo:  todo,
+			want:  true,
+		},
+		"Priority": {
+			query: Query{Priority: &high},
+			todo:  todo,
+			want:  true,
+		},
+		"OtherPriority": {
+			query: Query{Priority: &low},
+			todo:  todo,
+			want:  false,
+		},
+		"DueWithinRange": {
+			query: Query{DueFrom: ptr(time.Now()), DueTo: ptr(time.Now().Add(72 * time.Hour))},
+			todo:  todo,
+			want:  true,
+		},
+		"DueAfterRange": {
+			query: Query{DueTo: ptr(time.Now())},
+			todo:  todo,
+			want:  false,
+		},
+		"NoDueDate": {
+			query: Query{DueFrom: ptr(time.Now())},
+			todo:  &Todo{Description: "no due date"},
+			want:  false,
+		},
+		"Assignee": {
+			query: Query{AssignedTo: &userID},
+			todo:  todo,
+			want:  true,
+		},
+		"Completed": {
+			query: Query{Completed: &completed},
+			todo:  todo,
+			want:  false,
+		},
+		"ArchivedSkipped": {
+			query: Query{},
+			todo:  &Todo{Description: "archived", Archived: true},
+			want:  false,
+		},
+		"ArchivedIncluded": {
+			query: Query{IncludeArchived: true},
+			todo:  &Todo{Description: "archived", Archived: true},
+			want:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.query.Matches(tt.todo); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
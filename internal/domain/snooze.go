@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snooze keeps a todo out of the default views until its start date
+type Snooze struct {
+	Until time.Time
+	// UserID is who snoozed the todo
+	UserID uuid.UUID
+	// Notify asks for the user to be told when the todo comes back
+	Notify bool
+}
+
+// Snooze hides the todo until the time, replacing any snooze it already had
+func (t *Todo) Snooze(until time.Time, userID uuid.UUID, notify bool) {
+	t.snooze(Snooze{Until: until, UserID: userID, Notify: notify}, time.Now())
+}
+
+func (t *Todo) snooze(snooze Snooze, now time.Time) {
+	t.Snoozed = &snooze
+	t.touch(now)
+}
+
+// Unsnooze brings the todo back straight away
+func (t *Todo) Unsnooze() {
+	t.unsnooze(time.Now())
+}
+
+func (t *Todo) unsnooze(now time.Time) {
+	t.Snoozed = nil
+	t.touch(now)
+}
+
+// IsSnoozed returns true while the todo is hidden from the default views
+func (t *Todo) IsSnoozed(now time.Time) bool {
+	return t.Snoozed != nil && now.Before(t.Snoozed.Until)
+}
+
+// Wake ends a snooze whose time has come, returning the snooze it ended
+func (t *Todo) Wake(now time.Time) (*Snooze, bool) {
+	if t.Snoozed == nil || t.IsSnoozed(now) {
+		return nil, false
+	}
+	snooze := t.Snoozed
+	t.Snoozed = nil
+	t.touch(now)
+	return snooze, true
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTodo_Snooze(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	todo := NewTodo("Renew the passport")
+
+	if todo.IsSnoozed(now) {
+		t.Fatalf("a new todo is snoozed")
+	}
+	if _, ok := todo.Wake(now); ok {
+		t.Fatalf("Wake() woke a todo that was never snoozed")
+	}
+
+	todo.Snooze(now.Add(time.Hour), userID, true)
+	if !todo.IsSnoozed(now) || !todo.IsSnoozed(now.Add(59*time.Minute)) {
+		t.Fatalf("the todo is not snoozed for the hour")
+	}
+	if _, ok := todo.Wake(now.Add(30 * time.Minute)); ok {
+		t.Fatalf("Wake() woke the todo early")
+	}
+
+	snooze, ok := todo.Wake(now.Add(time.Hour))
+	if !ok || snooze.UserID != userID || !snooze.Notify || todo.Snoozed != nil {
+		t.Fatalf("Wake() = %+v, %v, want the snooze ended", snooze, ok)
+	}
+
+	// unsnoozing brings the todo back straight away
+	todo.Snooze(now.AddDate(0, 0, 7), userID, false)
+	todo.Unsnooze()
+	if todo.IsSnoozed(now) {
+		t.Errorf("the todo is still snoozed after Unsnooze()")
+	}
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SortOrder is the order todos are listed in
+//
+// An order is one or more sort keys separated by commas, such as "priority,-due"; each key after the first
+// breaks the ties left by those before it and a leading "-" turns a key around. Todos still tied keep the
+// order they were dragged into, and their IDs settle the rest.
+type SortOrder string
+
+const (
+	// SortManual keeps the order the todos were dragged into
+	SortManual      SortOrder = ""
+	SortDueDate     SortOrder = "due"
+	SortPriority    SortOrder = "priority"
+	SortCreated     SortOrder = "created"
+	SortUpdated     SortOrder = "updated"
+	SortDescription SortOrder = "description"
+)
+
+// sortKeyManual names the manual order as a key, so that it can come before other keys
+const sortKeyManual = "manual"
+
+// SortOrders are the orders todos can be listed in
+var SortOrders = []SortOrder{SortManual, SortDueDate, SortPriority, SortCreated, SortUpdated, SortDescription}
+
+// sortKey is one key of a sort order
+type sortKey struct {
+	name    string
+	reverse bool
+}
+
+// ParseSortOrder returns the sort order with the name, where an empty name is the manual order
+//
+// The keys are due, priority, created, updated, description and manual, each given at most once.
+func ParseSortOrder(name string) (SortOrder, bool) {
+	if strings.TrimSpace(name) == "" {
+		return SortManual, true
+	}
+
+	fields := strings.Split(name, ",")
+	keys := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		key := strings.TrimPrefix(field, "-")
+		if !isSortKey(key) || seen[key] {
+			return SortManual, false
+		}
+		seen[key] = true
+		keys = append(keys, field)
+	}
+	if len(keys) == 1 && keys[0] == sortKeyManual {
+		return SortManual, true
+	}
+	return SortOrder(strings.Join(keys, ",")), true
+}
+
+func isSortKey(name string) bool {
+	if name == sortKeyManual {
+		return true
+	}
+	for _, order := range SortOrders {
+		if order != SortManual && name == string(order) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort orders the todos in place, keeping the manual order between todos that compare the same
+//
+// Todos without a due date come after those with one whichever way the key runs; otherwise the highest
+// priority, the newest todos and descriptions from A to Z come first unless the key is turned around.
+func (o SortOrder) Sort(todos []*Todo) {
+	records := o.records(todos)
+	for i, record := range records {
+		todos[i] = record.todo
+	}
+}
+
+// records returns the sort records of the todos in order, positioned by where each todo was given
+func (o SortOrder) records(todos []*Todo) []sortRecord {
+	keys := o.keys()
+	records := make([]sortRecord, len(todos))
+	for i, todo := range todos {
+		records[i] = newSortRecord(todo, i, keys)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return compareRecords(keys, records[i], records[j]) < 0
+	})
+	return records
+}
+
+func (o SortOrder) keys() []sortKey {
+	if o == SortManual {
+		return nil
+	}
+	fields := strings.Split(string(o), ",")
+	keys := make([]sortKey, len(fields))
+	for i, field := range fields {
+		keys[i] = sortKey{name: strings.TrimPrefix(field, "-"), reverse: strings.HasPrefix(field, "-")}
+	}
+	return keys
+}
+
+// sortRecord holds what todos are compared by, so that a page can pick up after a todo that has since changed
+type sortRecord struct {
+	Due         *time.Time `json:"d,omitempty"`
+	Priority    Priority   `json:"p,omitempty"`
+	Created     time.Time  `json:"c"`
+	Updated     time.Time  `json:"u"`
+	Description string     `json:"t,omitempty"`
+	// ID is the todo, which a page picks up after wherever it now stands in the manual order
+	ID uuid.UUID `json:"id"`
+	// Position is where the todo was given; a cursor keeps it only for when its todo has since gone
+	Position int `json:"i"`
+	todo     *Todo
+}
+
+func newSortRecord(todo *Todo, position int, keys []sortKey) sortRecord {
+	record := sortRecord{
+		Due:      todo.DueDate,
+		Priority: todo.Priority,
+		Created:  todo.CreatedAt,
+		Updated:  todo.UpdatedAt,
+		ID:       todo.ID,
+		Position: position,
+		todo:     todo,
+	}
+	// the plain description takes some work, so it is only made when it is needed
+	for _, key := range keys {
+		if key.name == string(SortDescription) {
+			record.Description = strings.ToLower(todo.PlainDescription())
+		}
+	}
+	return record
+}
+
+// compareRecords returns a negative number when a comes before b, a positive one when it comes after,
+// and zero only for the same todo
+func compareRecords(keys []sortKey, a, b sortRecord) int {
+	for _, key := range keys {
+		var c int
+		switch key.name {
+		case string(SortDueDate):
+			if a.Due == nil || b.Due == nil {
+				// todos without a due date stay last either way
+				if c = compareBools(a.Due == nil, b.Due == nil); c != 0 {
+					return c
+				}
+				continue
+			}
+			c = compareTimes(*a.Due, *b.Due)
+		case string(SortPriority):
+			c = int(b.Priority) - int(a.Priority)
+		case string(SortCreated):
+			c = compareTimes(b.Created, a.Created)
+		case string(SortUpdated):
+			c = compareTimes(b.Updated, a.Updated)
+		case string(SortDescription):
+			c = strings.Compare(a.Description, b.Description)
+		case sortKeyManual:
+			c = a.Position - b.Position
+		}
+		if key.reverse {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	if c := a.Position - b.Position; c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
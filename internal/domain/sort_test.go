@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSortOrder_Sort(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	due := func(days int) *time.Time {
+		d := now.AddDate(0, 0, days)
+		return &d
+	}
+	rent := &Todo{Description: "Pay the **rent**", DueDate: due(2), Priority: PriorityHigh, CreatedAt: now, UpdatedAt: now.Add(3 * time.Hour)}
+	cat := &Todo{Description: "feed the cat", Priority: PriorityMedium, CreatedAt: now.Add(time.Hour), UpdatedAt: now.Add(time.Hour)}
+	banjo := &Todo{Description: "Learn the banjo", Priority: PriorityLow, CreatedAt: now.Add(2 * time.Hour), UpdatedAt: now}
+	taxes := &Todo{Description: "Do the taxes", DueDate: due(1), Priority: PriorityHigh, CreatedAt: now.Add(-time.Hour), UpdatedAt: now}
+
+	tests := map[string]struct {
+		order SortOrder
+		want  []*Todo
+	}{
+		"Manual":      {order: SortManual, want: []*Todo{rent, cat, banjo, taxes}},
+		"DueDate":     {order: SortDueDate, want: []*Todo{taxes, rent, cat, banjo}},
+		"Priority":    {order: SortPriority, want: []*Todo{rent, taxes, cat, banjo}},
+		"Created":     {order: SortCreated, want: []*Todo{banjo, cat, rent, taxes}},
+		"Updated":     {order: SortUpdated, want: []*Todo{rent, cat, banjo, taxes}},
+		"Description": {order: SortDescription, want: []*Todo{taxes, cat, banjo, rent}},
+		"Reversed":    {order: "-due", want: []*Todo{rent, taxes, cat, banjo}},
+		"TieBreaker":  {order: "priority,-created", want: []*Todo{taxes, rent, cat, banjo}},
+		"ManualFirst": {order: "manual,priority", want: []*Todo{rent, cat, banjo, taxes}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			todos := []*Todo{rent, cat, banjo, taxes}
+			tt.order.Sort(todos)
+			for i := range tt.want {
+				if todos[i] != tt.want[i] {
+					t.Fatalf("Sort()[%d] = %q, want %q", i, todos[i].Description, tt.want[i].Description)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	if order, ok := ParseSortOrder("Due"); !ok || order != SortDueDate {
+		t.Errorf("ParseSortOrder(Due) = %q, %v", order, ok)
+	}
+	if order, ok := ParseSortOrder(""); !ok || order != SortManual {
+		t.Errorf("ParseSortOrder() = %q, %v", order, ok)
+	}
+	if order, ok := ParseSortOrder(" Priority, -DUE "); !ok || order != "priority,-due" {
+		t.Errorf("ParseSortOrder(Priority, -DUE) = %q, %v", order, ok)
+	}
+	if order, ok := ParseSortOrder("manual"); !ok || order != SortManual {
+		t.Errorf("ParseSortOrder(manual) = %q, %v", order, ok)
+	}
+	for _, name := range []string{"shuffle", "due,-due", "priority,", "--due"} {
+		if _, ok := ParseSortOrder(name); ok {
+			t.Errorf("ParseSortOrder(%s) is a sort order", name)
+		}
+	}
+}
+
+func TestSortOrder_Sort_ties(t *testing.T) {
+	first, second := &Todo{ID: uuid.New()}, &Todo{ID: uuid.New()}
+	if first.ID.String() > second.ID.String() {
+		first, second = second, first
+	}
+	a, b := newSortRecord(first, 0, nil), newSortRecord(second, 0, nil)
+	if compareRecords(nil, a, b) >= 0 || compareRecords(nil, b, a) <= 0 || compareRecords(nil, a, a) != 0 {
+		t.Errorf("compareRecords() does not settle a tie on the todo IDs")
+	}
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is a stretch of time a user spent on a todo
+type TimeEntry struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Start  time.Time
+	// End is nil while the timer is still running
+	End  *time.Time
+	Note string
+}
+
+// Running returns true while the timer of the entry has not been stopped
+func (e TimeEntry) Running() bool {
+	return e.End == nil
+}
+
+// Duration returns the time spent, counting a running timer up to now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.End != nil {
+		end = *e.End
+	}
+	if end.Before(e.Start) {
+		return 0
+	}
+	return end.Sub(e.Start)
+}
+
+// StartTimer starts a timer for the user on the todo, or returns the one already running
+func (t *Todo) StartTimer(userID uuid.UUID, now time.Time) TimeEntry {
+	return t.startTimer(uuid.New(), userID, now)
+}
+
+func (t *Todo) startTimer(entryID, userID uuid.UUID, now time.Time) TimeEntry {
+	if running := t.RunningTimer(userID); running != nil {
+		return *running
+	}
+	entry := TimeEntry{ID: entryID, UserID: userID, Start: now}
+	t.TimeEntries = append(t.TimeEntries, entry)
+	t.touch(now)
+	return entry
+}
+
+// StopTimer stops the timer the user has running on the todo, returning false when there is none
+func (t *Todo) StopTimer(userID uuid.UUID, now time.Time) (TimeEntry, bool) {
+	running := t.RunningTimer(userID)
+	if running == nil {
+		return TimeEntry{}, false
+	}
+	running.End = &now
+	t.touch(now)
+	return *running, true
+}
+
+// RunningTimer returns the timer the user has running on the todo, or nil when there is none
+func (t *Todo) RunningTimer(userID uuid.UUID) *TimeEntry {
+	for i := range t.TimeEntries {
+		if t.TimeEntries[i].UserID == userID && t.TimeEntries[i].Running() {
+			return &t.TimeEntries[i]
+		}
+	}
+	return nil
+}
+
+// AddTimeEntry records time the user spent on the todo without running a timer
+func (t *Todo) AddTimeEntry(userID uuid.UUID, start, end time.Time, note string) TimeEntry {
+	entry := TimeEntry{ID: uuid.New(), UserID: userID, Start: start, End: &end, Note: note}
+	t.addTimeEntry(entry, time.Now())
+	return entry
+}
+
+func (t *Todo) addTimeEntry(entry TimeEntry, now time.Time) {
+	t.TimeEntries = append(t.TimeEntries, entry)
+	t.touch(now)
+}
+
+// TimeEntry returns the time entry with the ID, or nil when the todo has no such entry
+func (t *Todo) TimeEntry(id uuid.UUID) *TimeEntry {
+	for i := range t.TimeEntries {
+		if t.TimeEntries[i].ID == id {
+			return &t.TimeEntries[i]
+		}
+	}
+	return nil
+}
+
+// RemoveTimeEntry removes the time entry, returning false when there is none to remove
+func (t *Todo) RemoveTimeEntry(id uuid.UUID) bool {
+	return t.removeTimeEntry(id, time.Now())
+}
+
+func (t *Todo) removeTimeEntry(id uuid.UUID, now time.Time) bool {
+	for i := range t.TimeEntries {
+		if t.TimeEntries[i].ID == id {
+			t.TimeEntries = append(t.TimeEntries[:i], t.TimeEntries[i+1:]...)
+			t.touch(now)
+			return true
+		}
+	}
+	return false
+}
+
+// TimeSpent returns the time spent on the todo by everyone, counting running timers up to now
+func (t *Todo) TimeSpent(now time.Time) time.Duration {
+	var spent time.Duration
+	for _, entry := range t.TimeEntries {
+		spent += entry.Duration(now)
+	}
+	return spent
+}
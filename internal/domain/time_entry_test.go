@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTodo_Timers(t *testing.T) {
+	todo := NewTodo("Write the report")
+	alice, bob := uuid.New(), uuid.New()
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	first := todo.StartTimer(alice, start)
+	if again := todo.StartTimer(alice, start.Add(time.Minute)); again.ID != first.ID {
+		t.Errorf("StartTimer() started a second timer for the same user")
+	}
+	todo.StartTimer(bob, start.Add(30*time.Minute))
+	if _, ok := todo.StopTimer(bob, start.Add(45*time.Minute)); !ok {
+		t.Fatalf("StopTimer() found no timer for bob")
+	}
+	if _, ok := todo.StopTimer(bob, start.Add(time.Hour)); ok {
+		t.Errorf("StopTimer() stopped a timer that was not running")
+	}
+
+	// alice is still running, so her time counts up to now
+	now := start.Add(time.Hour)
+	if got := todo.TimeSpent(now); got != 75*time.Minute {
+		t.Errorf("TimeSpent() = %v, want 1h15m", got)
+	}
+	if running := todo.RunningTimer(alice); running == nil || running.Duration(now) != time.Hour {
+		t.Errorf("RunningTimer() = %v, want the timer alice started an hour ago", running)
+	}
+
+	manual := todo.AddTimeEntry(bob, start.Add(-2*time.Hour), start.Add(-90*time.Minute), "reading")
+	if got := todo.TimeSpent(now); got != 105*time.Minute {
+		t.Errorf("TimeSpent() = %v, want 1h45m", got)
+	}
+	if !todo.RemoveTimeEntry(manual.ID) || todo.TimeEntry(manual.ID) != nil {
+		t.Errorf("RemoveTimeEntry() did not remove the entry")
+	}
+}
@@ -1,9 +1,12 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/markdown"
 )
 
 type Priority int
@@ -14,6 +17,30 @@ const (
 	PriorityHigh
 )
 
+// String returns the lowercase name of the priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePriority parses a priority name, ignoring case
+func ParsePriority(s string) (Priority, bool) {
+	for _, p := range []Priority{PriorityLow, PriorityMedium, PriorityHigh} {
+		if strings.EqualFold(s, p.String()) {
+			return p, true
+		}
+	}
+	return PriorityMedium, false
+}
+
 type Todo struct {
 	ID          uuid.UUID
 	Description string
@@ -28,8 +55,22 @@ type Todo struct {
 	ParentID    *uuid.UUID
 	AssignedTo  *uuid.UUID
 	Comments    []Comment
-	Recurring   *RecurringConfig
+	Attachments []Attachment
+	// Estimate is how long the todo was expected to take, or zero when nobody said
+	Estimate    time.Duration
+	TimeEntries []TimeEntry
+	Pomodoros   []Pomodoro
+	// Snoozed is set while the todo is put off until a start date
+	Snoozed   *Snooze
+	Recurring *RecurringConfig
+	// Occurrences are when a recurring todo was completed, oldest first
+	Occurrences []time.Time
 	Archived    bool
+	// Version counts the edits made to the fields of the todo itself, so that an edit made to an older version
+	// can be turned away; comments, timers, attachments, pomodoros, snoozes, subtasks and reopening leave it be
+	Version int
+	// Revision counts every change made to the todo, to tell a newer copy of it from an older one
+	Revision int
 }
 
 type Comment struct {
@@ -37,6 +78,10 @@ type Comment struct {
 	Content   string
 	CreatedAt time.Time
 	UserID    uuid.UUID
+	// ParentID is the comment this one replies to
+	ParentID *uuid.UUID
+	// EditedAt is set once the content has been changed
+	EditedAt *time.Time
 }
 
 type RecurringConfig struct {
@@ -58,47 +103,142 @@ func NewTodo(description string) *Todo {
 		Tags:        make([]string, 0),
 		Subtasks:    make([]*Todo, 0),
 		Comments:    make([]Comment, 0),
+		Version:     1,
+		Revision:    1,
 	}
 }
 
 // Update updates a todo
+//
+// Completing a recurring todo records an occurrence; opening it again in the same period takes the occurrence back.
 func (t *Todo) Update(completed bool, description string) {
-	t.Completed = completed
+	t.update(completed, description, time.Now())
+}
+
+func (t *Todo) update(completed bool, description string, now time.Time) {
+	t.complete(completed, now)
 	t.Description = description
-	t.UpdatedAt = time.Now()
+	t.Touch(now)
+}
+
+// complete sets whether the todo is completed, recording or taking back the occurrence of a recurring todo
+func (t *Todo) complete(completed bool, now time.Time) {
+	if t.Recurring != nil && completed != t.Completed {
+		switch completed {
+		case true:
+			t.recordOccurrence(now)
+		default:
+			t.undoOccurrence(now)
+		}
+	}
+	t.Completed = completed
+}
+
+// Touch marks the fields of the todo as edited at the time, moving it on to its next version
+func (t *Todo) Touch(now time.Time) {
+	t.touch(now)
+	t.Version++
+}
+
+// touch marks the todo as changed at the time by what is kept on it rather than by an edit, moving it on to
+// its next revision but not its next version, so that an edit being made to it is not turned away
+func (t *Todo) touch(now time.Time) {
+	t.UpdatedAt = now
+	t.Revision++
 }
 
 // AddSubtask adds a subtask to the todo
 func (t *Todo) AddSubtask(subtask *Todo) {
-	subtask.ParentID = &t.ID
+	t.addSubtask(subtask, time.Now())
+}
+
+func (t *Todo) addSubtask(subtask *Todo, now time.Time) {
+	parentID := t.ID
+	subtask.ParentID = &parentID
 	t.Subtasks = append(t.Subtasks, subtask)
-	t.UpdatedAt = time.Now()
+	t.touch(now)
 }
 
-// AddComment adds a comment to the todo
-func (t *Todo) AddComment(content string, userID uuid.UUID) {
-	comment := Comment{
-		ID:        uuid.New(),
-		Content:   content,
-		CreatedAt: time.Now(),
-		UserID:    userID,
+// PlainDescription returns the description without its Markdown formatting
+func (t *Todo) PlainDescription() string {
+	return markdown.PlainText(t.Description)
+}
+
+// HasTag returns true when the todo has the tag, ignoring case
+func (t *Todo) HasTag(tag string) bool {
+	for _, existing := range t.Tags {
+		if strings.EqualFold(existing, tag) {
+			return true
+		}
 	}
-	t.Comments = append(t.Comments, comment)
-	t.UpdatedAt = time.Now()
+	return false
 }
 
 // Archive marks the todo as archived
 func (t *Todo) Archive() {
 	t.Archived = true
-	t.UpdatedAt = time.Now()
+	t.Touch(time.Now())
 }
 
 // SetRecurring sets the recurring configuration
 func (t *Todo) SetRecurring(frequency string, endDate *time.Time) {
+	t.setRecurring(frequency, endDate, time.Now())
+}
+
+func (t *Todo) setRecurring(frequency string, endDate *time.Time, now time.Time) {
 	t.Recurring = &RecurringConfig{
 		Frequency:      frequency,
 		EndDate:        endDate,
-		LastOccurrence: time.Now(),
+		LastOccurrence: now,
+	}
+	t.Touch(now)
+}
+
+// clone copies the todo, so that changing the copy leaves the todo as it was
+//
+// The subtasks are copied too; copies holds the copies made so far, so that a todo reached more than once is
+// copied once.
+func (t *Todo) clone(copies map[*Todo]*Todo) *Todo {
+	if copies == nil {
+		copies = make(map[*Todo]*Todo)
+	}
+	if c, ok := copies[t]; ok {
+		return c
+	}
+	c := *t
+	copies[t] = &c
+
+	c.DueDate = clonePointer(t.DueDate)
+	c.ParentID = clonePointer(t.ParentID)
+	c.AssignedTo = clonePointer(t.AssignedTo)
+	c.Snoozed = clonePointer(t.Snoozed)
+	c.Recurring = clonePointer(t.Recurring)
+	c.Tags = cloneSlice(t.Tags)
+	c.Comments = cloneSlice(t.Comments)
+	c.Attachments = cloneSlice(t.Attachments)
+	c.TimeEntries = cloneSlice(t.TimeEntries)
+	c.Pomodoros = cloneSlice(t.Pomodoros)
+	c.Occurrences = cloneSlice(t.Occurrences)
+	if t.Subtasks != nil {
+		c.Subtasks = make([]*Todo, len(t.Subtasks))
+		for i, subtask := range t.Subtasks {
+			c.Subtasks[i] = subtask.clone(copies)
+		}
+	}
+	return &c
+}
+
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
 	}
-	t.UpdatedAt = time.Now()
+	return append(make([]T, 0, len(s)), s...)
 }
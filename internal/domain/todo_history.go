@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"time"
+)
+
+// TodoHistory is kept by todo repositories that remember every change made to the todos
+type TodoHistory interface {
+	// TodosAt returns the todos as they were at the time, in the order they were in
+	TodosAt(at time.Time) ([]*Todo, error)
+}
@@ -6,8 +6,22 @@ import (
 	"github.com/google/uuid"
 )
 
+// TodoRepository keeps the todos
+//
+// The todos it hands out are copies; changing one changes nothing in the repository. Todos are changed through
+// Change, which makes the changes to the todo as it is stored while no one else can, so that no change is lost.
 type TodoRepository interface {
 	Add(description string) *Todo
+	// Save adds the todos, or replaces the todos with the same IDs where they stand, all at once
+	//
+	// The subtasks of each todo are linked by ID to the todos saved with it or already in the repository.
+	Save(todos ...*Todo)
+	// AddSubtask adds the subtask and puts it under its parent, returning ErrTodoNotFound when there is no such
+	// parent
+	AddSubtask(parentID uuid.UUID, subtask *Todo) (*Todo, error)
+	// Change makes the changes to the todo all at once, or none of them when one fails, and returns the todo as
+	// they left it
+	Change(id uuid.UUID, changes ...Change) (*Todo, error)
 	Remove(id uuid.UUID)
 	Update(id uuid.UUID, completed bool, description string) *Todo
 	Search(search string) []*Todo
@@ -26,4 +40,5 @@ type TodoRepository interface {
 	GetSubtasks(parentID uuid.UUID) []*Todo
 	GetOverdue() []*Todo
 	GetUpcoming(days int) []*Todo
+	Find(query Query) []*Todo
 }
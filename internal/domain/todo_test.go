@@ -99,6 +99,26 @@ func TestTodo_Update(t *testing.T) {
 			if todo.Description != tt.args.description {
 				t1.Errorf("Update() = %v, want %v", todo.Description, tt.args.description)
 			}
+			if todo.Version != 1 {
+				t1.Errorf("Update() Version = %v, want 1", todo.Version)
+			}
 		})
 	}
 }
+
+func TestTodo_Touch(t *testing.T) {
+	todo := NewTodo("test")
+	if todo.Version != 1 {
+		t.Fatalf("NewTodo() Version = %v, want 1", todo.Version)
+	}
+
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	todo.Touch(now)
+	todo.AddComment("a comment", uuid.New())
+	if todo.Version != 2 || todo.Revision != 3 {
+		t.Errorf("Version = %v, Revision = %v after an edit and a comment, want 2 and 3", todo.Version, todo.Revision)
+	}
+	if todo.UpdatedAt.Equal(now) {
+		t.Errorf("UpdatedAt = %v, want the time of the last change", todo.UpdatedAt)
+	}
+}
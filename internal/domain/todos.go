@@ -1,206 +1,326 @@
 package domain
 
 import (
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// Todos is a list of Todo
-type Todos []*Todo
+// Todos is a list of Todo kept in memory, safe for use by the handlers and the scheduled jobs at once
+//
+// The todos handed out are copies, so that nobody reads a todo while it is being changed; todos are changed
+// through Change, which makes the changes while holding the lock.
+type Todos struct {
+	mu   sync.RWMutex
+	list []*Todo
+	byID map[uuid.UUID]*Todo
+	now  func() time.Time
+}
 
 // NewTodos creates a new list of todos
 func NewTodos() *Todos {
-	return &Todos{}
+	return &Todos{
+		byID: make(map[uuid.UUID]*Todo),
+		now:  time.Now,
+	}
 }
 
 // Add adds a todo to the list
 func (l *Todos) Add(description string) *Todo {
 	todo := NewTodo(description)
-	*l = append(*l, todo)
-	return todo
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.put(todo).clone(nil)
+}
+
+// Save adds the todos to the end of the list, or replaces the todos with the same IDs where they stand, all at once
+//
+// The subtasks of each todo are linked by ID to the todos saved with it or already in the list.
+func (l *Todos) Save(todos ...*Todo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	saved := make([]*Todo, len(todos))
+	for i, todo := range todos {
+		saved[i] = l.put(todo.clone(nil))
+	}
+	for i, todo := range todos {
+		if todo.Subtasks == nil {
+			continue
+		}
+		saved[i].Subtasks = make([]*Todo, 0, len(todo.Subtasks))
+		for _, subtask := range todo.Subtasks {
+			if stored, ok := l.byID[subtask.ID]; ok {
+				saved[i].Subtasks = append(saved[i].Subtasks, stored)
+			}
+		}
+	}
+}
+
+// AddSubtask adds the subtask to the end of the list and under its parent, returning ErrTodoNotFound when
+// there is no such parent
+func (l *Todos) AddSubtask(parentID uuid.UUID, subtask *Todo) (*Todo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	parent, ok := l.byID[parentID]
+	if !ok {
+		return nil, ErrTodoNotFound
+	}
+	stored := subtask.clone(nil)
+	stored.Subtasks = make([]*Todo, 0)
+	stored = l.put(stored)
+	parent.addSubtask(stored, stored.CreatedAt)
+	return stored.clone(nil), nil
+}
+
+// Change makes the changes to the todo, returning the todo as they left it
+//
+// The changes are made all at once or not at all: when one fails, its error is returned and the todo is left as
+// it was. ErrTodoNotFound is returned when there is no such todo.
+func (l *Todos) Change(id uuid.UUID, changes ...Change) (*Todo, error) {
+	return l.ChangeAt(id, l.now(), changes...)
+}
+
+// ChangeAt makes the changes to the todo as Change does, as though they were made at the time
+//
+// It is for repositories keeping a log of the changes, which play them back at the times they were made.
+func (l *Todos) ChangeAt(id uuid.UUID, at time.Time, changes ...Change) (*Todo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stored, ok := l.byID[id]
+	if !ok {
+		return nil, ErrTodoNotFound
+	}
+	changed := stored.clone(nil)
+	for _, change := range changes {
+		if err := change.Apply(changed, at); err != nil {
+			return nil, err
+		}
+	}
+	// the subtasks are changed through changes of their own
+	changed.Subtasks = stored.Subtasks
+	// the changes made together move the todo on one version and one revision, however many of them touched it
+	if changed.Version > stored.Version {
+		changed.Version = stored.Version + 1
+	}
+	if changed.Revision > stored.Revision {
+		changed.Revision = stored.Revision + 1
+	}
+	*stored = *changed
+	return stored.clone(nil), nil
 }
 
-// Remove removes a todo from the list
+// Remove removes a todo from the list, and from under its parent
 func (l *Todos) Remove(id uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	index := l.indexOf(id)
 	if index == -1 {
 		return
 	}
-	*l = append((*l)[:index], (*l)[index+1:]...)
+	todo := l.list[index]
+	l.list = append(l.list[:index], l.list[index+1:]...)
+	delete(l.byID, id)
+
+	if todo.ParentID == nil {
+		return
+	}
+	if parent, ok := l.byID[*todo.ParentID]; ok {
+		subtasks := make([]*Todo, 0, len(parent.Subtasks))
+		for _, subtask := range parent.Subtasks {
+			if subtask.ID != id {
+				subtasks = append(subtasks, subtask)
+			}
+		}
+		parent.Subtasks = subtasks
+	}
 }
 
 // Update updates a todo in the list
 func (l *Todos) Update(id uuid.UUID, completed bool, description string) *Todo {
-	index := l.indexOf(id)
-	if index == -1 {
+	todo, err := l.Change(id, Update{Completed: completed, Description: description})
+	if err != nil {
 		return nil
 	}
-	todo := (*l)[index]
-	todo.Update(completed, description)
 	return todo
 }
 
 // Search returns a list of todos that match the search string
+//
+// The search string is matched against the description both as written and without its Markdown formatting.
 func (l *Todos) Search(search string) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if strings.Contains(todo.Description, search) {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool {
+		return strings.Contains(todo.Description, search) || strings.Contains(todo.PlainDescription(), search)
+	})
 }
 
 // All returns a copy of the list of todos
 func (l *Todos) All() []*Todo {
-	list := make([]*Todo, len(*l))
-	copy(list, *l)
-	return list
+	return l.filter(func(*Todo) bool { return true })
 }
 
 // Get returns a todo by id
 func (l *Todos) Get(id uuid.UUID) *Todo {
-	index := l.indexOf(id)
-	if index == -1 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	todo, ok := l.byID[id]
+	if !ok {
 		return nil
 	}
-	return (*l)[index]
+	return todo.clone(nil)
 }
 
 // Reorder reorders the list of todos
+//
+// The todos given take the places they held between them; todos left out, such as those hidden from view, keep theirs.
 func (l *Todos) Reorder(ids []uuid.UUID) []*Todo {
-	newTodos := make([]*Todo, len(ids))
-	for i, id := range ids {
-		newTodos[i] = (*l)[l.indexOf(id)]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	indexes := make(map[uuid.UUID]int, len(l.list))
+	for i, todo := range l.list {
+		indexes[todo.ID] = i
+	}
+	newTodos := make([]*Todo, 0, len(ids))
+	places := make([]int, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if index, ok := indexes[id]; ok && !seen[id] {
+			seen[id] = true
+			newTodos = append(newTodos, l.list[index])
+			places = append(places, index)
+		}
 	}
-	copy(*l, newTodos)
-	return newTodos
+	sort.Ints(places)
+	for i, index := range places {
+		l.list[index] = newTodos[i]
+	}
+	return cloneAll(newTodos)
 }
 
 // GetByCategory returns todos in the specified category
 func (l *Todos) GetByCategory(category string) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.Category == category {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.Category == category })
 }
 
 // GetByTag returns todos with the specified tag
 func (l *Todos) GetByTag(tag string) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
+	return l.filter(func(todo *Todo) bool {
 		for _, t := range todo.Tags {
 			if t == tag {
-				list = append(list, todo)
-				break
+				return true
 			}
 		}
-	}
-	return list
+		return false
+	})
 }
 
 // GetByPriority returns todos with the specified priority
 func (l *Todos) GetByPriority(priority Priority) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.Priority == priority {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.Priority == priority })
 }
 
 // GetByDueDate returns todos due between start and end dates
 func (l *Todos) GetByDueDate(start, end time.Time) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.DueDate != nil && !todo.DueDate.Before(start) && !todo.DueDate.After(end) {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool {
+		return todo.DueDate != nil && !todo.DueDate.Before(start) && !todo.DueDate.After(end)
+	})
 }
 
 // GetByAssignee returns todos assigned to the specified user
 func (l *Todos) GetByAssignee(userID uuid.UUID) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.AssignedTo != nil && *todo.AssignedTo == userID {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.AssignedTo != nil && *todo.AssignedTo == userID })
 }
 
 // GetRecurring returns all recurring todos
 func (l *Todos) GetRecurring() []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.Recurring != nil {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.Recurring != nil })
 }
 
 // GetArchived returns all archived todos
 func (l *Todos) GetArchived() []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.Archived {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.Archived })
 }
 
 // GetSubtasks returns all subtasks for a given parent todo
 func (l *Todos) GetSubtasks(parentID uuid.UUID) []*Todo {
-	list := make([]*Todo, 0)
-	for _, todo := range *l {
-		if todo.ParentID != nil && *todo.ParentID == parentID {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool { return todo.ParentID != nil && *todo.ParentID == parentID })
 }
 
 // GetOverdue returns all overdue todos
 func (l *Todos) GetOverdue() []*Todo {
-	list := make([]*Todo, 0)
 	now := time.Now()
-	for _, todo := range *l {
-		if todo.DueDate != nil && todo.DueDate.Before(now) && !todo.Completed {
-			list = append(list, todo)
-		}
-	}
-	return list
+	return l.filter(func(todo *Todo) bool {
+		return todo.DueDate != nil && todo.DueDate.Before(now) && !todo.Completed
+	})
 }
 
 // GetUpcoming returns todos due in the next specified number of days
 func (l *Todos) GetUpcoming(days int) []*Todo {
-	list := make([]*Todo, 0)
 	now := time.Now()
 	end := now.AddDate(0, 0, days)
-	for _, todo := range *l {
-		if todo.DueDate != nil && !todo.DueDate.Before(now) && !todo.DueDate.After(end) {
+	return l.filter(func(todo *Todo) bool {
+		return todo.DueDate != nil && !todo.DueDate.Before(now) && !todo.DueDate.After(end)
+	})
+}
+
+// Find returns the todos matching the query
+func (l *Todos) Find(query Query) []*Todo {
+	return l.filter(query.Matches)
+}
+
+// filter returns copies of the todos for which keep returns true, in the order of the list
+func (l *Todos) filter(keep func(todo *Todo) bool) []*Todo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	list := make([]*Todo, 0)
+	for _, todo := range l.list {
+		if keep(todo) {
 			list = append(list, todo)
 		}
 	}
-	return list
+	return cloneAll(list)
+}
+
+// put adds the todo to the end of the list, or puts it in the place of the todo with the same ID, returning
+// the todo as it is stored
+func (l *Todos) put(todo *Todo) *Todo {
+	if stored, ok := l.byID[todo.ID]; ok {
+		// the todos holding it as a subtask keep holding it
+		*stored = *todo
+		return stored
+	}
+	l.list = append(l.list, todo)
+	l.byID[todo.ID] = todo
+	return todo
 }
 
 // indexOf returns the index of the todo with the given id or -1 if not found
 func (l *Todos) indexOf(id uuid.UUID) int {
-	for i, todo := range *l {
+	for i, todo := range l.list {
 		if todo.ID == id {
 			return i
 		}
 	}
 	return -1
 }
+
+// cloneAll copies the todos, sharing the copies of any subtasks among them
+func cloneAll(todos []*Todo) []*Todo {
+	copies := make(map[*Todo]*Todo, len(todos))
+	list := make([]*Todo, len(todos))
+	for i, todo := range todos {
+		list[i] = todo.clone(copies)
+	}
+	return list
+}
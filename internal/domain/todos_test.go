@@ -2,6 +2,7 @@ package domain
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,12 +14,12 @@ func TestTodos_Add(t *testing.T) {
 		description string
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 		want *Todo
 	}{
 		"AddEmpty": {
-			l:    Todos{},
+			l:    []*Todo{},
 			args: args{description: "test"},
 			want: &Todo{
 				ID:          uuid.New(),
@@ -28,7 +29,7 @@ func TestTodos_Add(t *testing.T) {
 			},
 		},
 		"AddNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				{
 					ID:          uuid.New(),
 					Description: "test",
@@ -47,9 +48,10 @@ func TestTodos_Add(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			l := todosOf(tt.l...)
 			initialLength := len(tt.l)
 
-			got := tt.l.Add(tt.args.description)
+			got := l.Add(tt.args.description)
 
 			if got.ID == uuid.Nil {
 				t.Errorf("todo.ID = %v, want %v", got, tt.want)
@@ -63,11 +65,12 @@ func TestTodos_Add(t *testing.T) {
 			if got.CreatedAt.IsZero() {
 				t.Errorf("todo.CreatedAt = %v, want %v", got, tt.want)
 			}
-			if len(tt.l) != initialLength+1 {
+			all := l.All()
+			if len(all) != initialLength+1 {
 				t.Errorf("len(todos) = %v, want %v", got, tt.want)
 			}
-			if tt.l[initialLength] != got {
-				t.Errorf("todos[%v] = %v, want %v", initialLength, tt.l[initialLength], tt.want)
+			if !reflect.DeepEqual(all[initialLength], got) {
+				t.Errorf("todos[%v] = %v, want %v", initialLength, all[initialLength], tt.want)
 			}
 		})
 	}
@@ -83,16 +86,16 @@ func TestTodos_All(t *testing.T) {
 	var fourthID = uuid.New()
 	var fourth = &Todo{ID: fourthID, Description: "fourth"}
 	tests := map[string]struct {
-		l Todos
+		l []*Todo
 
 		want []*Todo
 	}{
 		"AllEmpty": {
-			l:    Todos{},
+			l:    []*Todo{},
 			want: []*Todo{},
 		},
 		"AllNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 			},
@@ -102,7 +105,7 @@ func TestTodos_All(t *testing.T) {
 			},
 		},
 		"AllMany": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 				third,
@@ -118,7 +121,7 @@ func TestTodos_All(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			if got := tt.l.All(); !reflect.DeepEqual(got, tt.want) {
+			if got := todosOf(tt.l...).All(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("All() = %v, want %v", got, tt.want)
 			}
 		})
@@ -131,19 +134,19 @@ func TestTodos_Get(t *testing.T) {
 		id uuid.UUID
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 		want *Todo
 	}{
 		"GetEmpty": {
-			l: Todos{},
+			l: []*Todo{},
 			args: args{
 				id: uuid.New(),
 			},
 			want: nil,
 		},
 		"GetNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				{ID: uuid.New()},
 			},
 			args: args{
@@ -152,7 +155,7 @@ func TestTodos_Get(t *testing.T) {
 			want: nil,
 		},
 		"GetExisting": {
-			l: Todos{
+			l: []*Todo{
 				{ID: existingID},
 			},
 			args: args{
@@ -161,7 +164,7 @@ func TestTodos_Get(t *testing.T) {
 			want: &Todo{ID: existingID},
 		},
 		"GetExistingMultiple": {
-			l: Todos{
+			l: []*Todo{
 				{ID: uuid.New()},
 				{ID: existingID},
 				{ID: uuid.New()},
@@ -174,7 +177,7 @@ func TestTodos_Get(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := tt.l.Get(tt.args.id)
+			got := todosOf(tt.l...).Get(tt.args.id)
 
 			if tt.want == nil && got != nil {
 				t.Errorf("todo = %v, want %v", got, tt.want)
@@ -196,17 +199,17 @@ func TestTodos_Remove(t *testing.T) {
 		id uuid.UUID
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 	}{
 		"RemoveEmpty": {
-			l: Todos{},
+			l: []*Todo{},
 			args: args{
 				id: uuid.New(),
 			},
 		},
 		"RemoveNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				{ID: uuid.New()},
 			},
 			args: args{
@@ -214,7 +217,7 @@ func TestTodos_Remove(t *testing.T) {
 			},
 		},
 		"RemoveExisting": {
-			l: Todos{
+			l: []*Todo{
 				{ID: existingID},
 			},
 			args: args{
@@ -222,7 +225,7 @@ func TestTodos_Remove(t *testing.T) {
 			},
 		},
 		"RemoveExistingMultiple": {
-			l: Todos{
+			l: []*Todo{
 				{ID: uuid.New()},
 				{ID: existingID},
 				{ID: uuid.New()},
@@ -234,15 +237,29 @@ func TestTodos_Remove(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			tt.l.Remove(tt.args.id)
+			l := todosOf(tt.l...)
+			l.Remove(tt.args.id)
 
-			if tt.l.Get(tt.args.id) != nil {
-				t.Errorf("todo = %v, want %v", tt.l.Get(tt.args.id), nil)
+			if l.Get(tt.args.id) != nil {
+				t.Errorf("todo = %v, want %v", l.Get(tt.args.id), nil)
 			}
 		})
 	}
 }
 
+func TestTodos_Remove_subtask(t *testing.T) {
+	l := NewTodos()
+	trip := l.Add("Plan vacation")
+	flights, _ := l.AddSubtask(trip.ID, NewTodo("Book flights"))
+	hotel, _ := l.AddSubtask(trip.ID, NewTodo("Book a hotel"))
+
+	l.Remove(flights.ID)
+
+	if subtasks := l.Get(trip.ID).Subtasks; len(subtasks) != 1 || subtasks[0].ID != hotel.ID {
+		t.Errorf("Subtasks = %v, want only the hotel", subtasks)
+	}
+}
+
 func TestTodos_Reorder(t *testing.T) {
 	var firstID = uuid.New()
 	var first = &Todo{ID: firstID}
@@ -257,19 +274,19 @@ func TestTodos_Reorder(t *testing.T) {
 		ids []uuid.UUID
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 		want []*Todo
 	}{
 		"ReorderEmpty": {
-			l: Todos{},
+			l: []*Todo{},
 			args: args{
 				ids: []uuid.UUID{},
 			},
 			want: []*Todo{},
 		},
 		"ReorderNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 			},
@@ -282,7 +299,7 @@ func TestTodos_Reorder(t *testing.T) {
 			},
 		},
 		"RecorderMany": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 				third,
@@ -301,7 +318,7 @@ func TestTodos_Reorder(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := tt.l.Reorder(tt.args.ids)
+			got := todosOf(tt.l...).Reorder(tt.args.ids)
 
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Reorder() = %v, want %v", got, tt.want)
@@ -310,6 +327,18 @@ func TestTodos_Reorder(t *testing.T) {
 	}
 }
 
+func TestTodos_Reorder_partial(t *testing.T) {
+	first, second, third, fourth := &Todo{ID: uuid.New()}, &Todo{ID: uuid.New()}, &Todo{ID: uuid.New()}, &Todo{ID: uuid.New()}
+	l := todosOf(first, second, third, fourth)
+
+	// the second is hidden, so only the others are sorted
+	l.Reorder([]uuid.UUID{fourth.ID, first.ID, third.ID, uuid.New()})
+
+	if got, want := l.All(), []*Todo{fourth, second, first, third}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reorder() left %v, want %v", got, want)
+	}
+}
+
 func TestTodos_Search(t *testing.T) {
 	var firstID = uuid.New()
 	var first = &Todo{ID: firstID, Description: "first"}
@@ -323,19 +352,19 @@ func TestTodos_Search(t *testing.T) {
 		search string
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 		want []*Todo
 	}{
 		"SearchEmpty": {
-			l: Todos{},
+			l: []*Todo{},
 			args: args{
 				search: "first",
 			},
 			want: []*Todo{},
 		},
 		"SearchNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 			},
@@ -347,7 +376,7 @@ func TestTodos_Search(t *testing.T) {
 			},
 		},
 		"SearchMany": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 				third,
@@ -362,7 +391,7 @@ func TestTodos_Search(t *testing.T) {
 			},
 		},
 		"SearchManyNone": {
-			l: Todos{
+			l: []*Todo{
 				first,
 				second,
 				third,
@@ -376,7 +405,7 @@ func TestTodos_Search(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			if got := tt.l.Search(tt.args.search); !reflect.DeepEqual(got, tt.want) {
+			if got := todosOf(tt.l...).Search(tt.args.search); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Search() = %v, want %v", got, tt.want)
 			}
 		})
@@ -393,12 +422,12 @@ func TestTodos_Update(t *testing.T) {
 		description string
 	}
 	tests := map[string]struct {
-		l    Todos
+		l    []*Todo
 		args args
 		want *Todo
 	}{
 		"UpdateEmpty": {
-			l: Todos{},
+			l: []*Todo{},
 			args: args{
 				id:          firstID,
 				completed:   true,
@@ -407,7 +436,7 @@ func TestTodos_Update(t *testing.T) {
 			want: nil,
 		},
 		"UpdateNonEmpty": {
-			l: Todos{
+			l: []*Todo{
 				{ID: firstID, Description: "first", Completed: false},
 			},
 			args: args{
@@ -418,7 +447,7 @@ func TestTodos_Update(t *testing.T) {
 			want: nil,
 		},
 		"UpdateExisting": {
-			l: Todos{
+			l: []*Todo{
 				{ID: firstID, Description: "first", Completed: false},
 				{ID: secondID, Description: "second", Completed: false},
 				{ID: thirdID, Description: "third", Completed: false},
@@ -432,14 +461,120 @@ func TestTodos_Update(t *testing.T) {
 				ID:          secondID,
 				Description: "SECOND",
 				Completed:   true,
+				Version:     1,
+				Revision:    1,
 			},
 		},
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			if got := tt.l.Update(tt.args.id, tt.args.completed, tt.args.description); !reflect.DeepEqual(got, tt.want) {
+			got := todosOf(tt.l...).Update(tt.args.id, tt.args.completed, tt.args.description)
+			if got != nil {
+				if got.UpdatedAt.IsZero() {
+					t.Errorf("Update() UpdatedAt = %v, want a timestamp", got.UpdatedAt)
+				}
+				got.UpdatedAt = time.Time{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Update() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+// todosOf returns a list holding the todos
+func todosOf(todos ...*Todo) *Todos {
+	l := NewTodos()
+	l.Save(todos...)
+	return l
+}
+
+func TestTodos_Change(t *testing.T) {
+	l := NewTodos()
+	todo := l.Add("Feed the cat")
+
+	// the todos handed out are copies
+	todo.Description = "Feed the dog"
+	if got := l.Get(todo.ID); got.Description != "Feed the cat" {
+		t.Fatalf("Description = %q after changing a copy, want it unchanged", got.Description)
+	}
+
+	changed, err := l.Change(todo.ID, Rename{Description: "Feed the cats"}, SetPriority{Priority: PriorityHigh})
+	if err != nil || changed.Description != "Feed the cats" || changed.Priority != PriorityHigh || changed.Version != 2 {
+		t.Fatalf("Change() = %+v, %v, want both changes made in one version", changed, err)
+	}
+
+	// a change that fails leaves the todo as it was, however many were made before it
+	_, err = l.Change(todo.ID, Rename{Description: "Feed the fish"}, Wake{Now: time.Now()})
+	if err != ErrUnchanged {
+		t.Fatalf("Change() error = %v, want %v", err, ErrUnchanged)
+	}
+	if got := l.Get(todo.ID); got.Description != "Feed the cats" || got.Version != 2 {
+		t.Errorf("Change() left %+v after failing, want it as it was", got)
+	}
+
+	// changes expecting a version made since are not made
+	if _, err = l.Change(todo.ID, Expect{Version: 1}, Rename{Description: "Feed the fish"}); err != ErrVersionConflict {
+		t.Errorf("Change() error = %v, want %v", err, ErrVersionConflict)
+	}
+	if changed, err = l.Change(todo.ID, Expect{Version: 2}, Rename{Description: "Feed the fish"}); err != nil || changed.Version != 3 {
+		t.Errorf("Change() = %+v, %v, want version 3", changed, err)
+	}
+
+	// a comment, a timer or a snooze is not an edit, so an edit to the version before it is still made
+	revision := changed.Revision
+	if changed, err = l.Change(todo.ID, AddComment{Comment: Comment{ID: uuid.New(), Content: "meow"}}); err != nil || changed.Version != 3 || changed.Revision != revision+1 {
+		t.Errorf("Change() = %+v, %v, want version 3 at the next revision", changed, err)
+	}
+	if changed, err = l.Change(todo.ID, Expect{Version: 3}, Rename{Description: "Feed the fishes"}); err != nil || changed.Version != 4 {
+		t.Errorf("Change() = %+v, %v, want version 4 after a comment", changed, err)
+	}
+
+	if _, err = l.Change(uuid.New(), Rename{Description: "Feed the fish"}); err != ErrTodoNotFound {
+		t.Errorf("Change() error = %v, want %v", err, ErrTodoNotFound)
+	}
+}
+
+func TestTodos_Change_concurrent(t *testing.T) {
+	l := NewTodos()
+	todo := l.Add("Feed the cat")
+
+	const changes = 50
+	var wg sync.WaitGroup
+	for i := 0; i < changes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _ = l.Change(todo.ID, AddComment{Comment: Comment{ID: uuid.New(), Content: "meow", UserID: uuid.New()}})
+			_ = l.All()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := l.Get(todo.ID); len(got.Comments) != changes || got.Revision != changes+1 {
+		t.Errorf("Change() left %d comments at revision %d, want %d at %d", len(got.Comments), got.Revision, changes, changes+1)
+	}
+}
+
+func TestTodos_AddSubtask(t *testing.T) {
+	l := NewTodos()
+	parent := l.Add("Clean the house")
+
+	subtask, err := l.AddSubtask(parent.ID, NewTodo("Dust the shelves"))
+	if err != nil || subtask.ParentID == nil || *subtask.ParentID != parent.ID {
+		t.Fatalf("AddSubtask() = %+v, %v, want a subtask of the parent", subtask, err)
+	}
+	if _, err = l.Change(subtask.ID, Complete{Completed: true}); err != nil {
+		t.Fatalf("Change() error = %v", err)
+	}
+
+	// the parent holds the subtask as it is now
+	got := l.Get(parent.ID)
+	if len(got.Subtasks) != 1 || got.Subtasks[0].ID != subtask.ID || !got.Subtasks[0].Completed {
+		t.Errorf("Subtasks = %v, want the completed subtask", got.Subtasks)
+	}
+
+	if _, err = l.AddSubtask(uuid.New(), NewTodo("Mop the floor")); err != ErrTodoNotFound {
+		t.Errorf("AddSubtask() error = %v, want %v", err, ErrTodoNotFound)
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// usernamePattern is what a username may look like so that it can be mentioned as @username
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,30}[a-z0-9_]$`)
+
+type User struct {
+	ID uuid.UUID
+	// Username is unique, lower case, and how the user is mentioned in comments
+	Username  string
+	Name      string
+	CreatedAt time.Time
+}
+
+// NormalizeUsername returns the username as it is stored, and false when it cannot be used
+func NormalizeUsername(username string) (string, bool) {
+	username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+	return username, usernamePattern.MatchString(username)
+}
+
+// DisplayName returns the name of the user, or their username when they have not given one
+func (u *User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Username
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+type UserRepository interface {
+	// Save adds the user or replaces the user with the same ID
+	Save(user *User)
+	Get(id uuid.UUID) *User
+	// GetByUsername finds a user by username, ignoring case
+	GetByUsername(username string) *User
+	All() []*User
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := map[string]struct {
+		username string
+		want     string
+		wantOK   bool
+	}{
+		"Lowercased":    {username: " @Alice ", want: "alice", wantOK: true},
+		"Punctuation":   {username: "bob.smith-2_", want: "bob.smith-2_", wantOK: true},
+		"TooShort":      {username: "a", want: "a", wantOK: false},
+		"Spaces":        {username: "bob smith", want: "bob smith", wantOK: false},
+		"TrailingDot":   {username: "bob.", want: "bob.", wantOK: false},
+		"LeadingDash":   {username: "-bob", want: "-bob", wantOK: false},
+		"NotASCII":      {username: "zoë", want: "zoë", wantOK: false},
+		"ThirtyTwoLong": {username: "abcdefghijabcdefghijabcdefghij12", want: "abcdefghijabcdefghijabcdefghij12", wantOK: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := NormalizeUsername(tt.username)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizeUsername() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestUsers_GetByUsername(t *testing.T) {
+	users := NewUsers()
+	alice := &User{ID: uuid.New(), Username: "alice"}
+	users.Save(alice)
+	users.Save(&User{ID: uuid.New(), Username: "bob"})
+
+	if got := users.GetByUsername("Alice"); got == nil || *got != *alice {
+		t.Errorf("GetByUsername() = %v, want %v", got, alice)
+	}
+	if got := users.GetByUsername("carol"); got != nil {
+		t.Errorf("GetByUsername() = %v, want nil", got)
+	}
+	if got := users.All(); len(got) != 2 || got[0].ID != alice.ID {
+		t.Errorf("All() = %v, want alice then bob", got)
+	}
+}
+
+func TestUsers_concurrent(t *testing.T) {
+	users := NewUsers()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				user := &User{ID: uuid.New(), Username: "user"}
+				users.Save(user)
+				users.Get(user.ID).Name = "changed"
+				users.All()
+			}
+		}()
+	}
+	wg.Wait()
+
+	all := users.All()
+	if len(all) != 800 {
+		t.Fatalf("All() = %d users, want 800", len(all))
+	}
+	// the users handed out are copies
+	if all[0].Name != "" {
+		t.Errorf("changing a user handed out changed it in the repository")
+	}
+}
+
+func TestNotifications_ForUser(t *testing.T) {
+	var userID = uuid.New()
+	notifications := NewNotifications()
+	first := NewNotification(userID, NotificationMention, uuid.New(), "first")
+	second := NewNotification(userID, NotificationAssignment, uuid.New(), "second")
+	notifications.Add(first)
+	notifications.Add(NewNotification(uuid.New(), NotificationMention, uuid.New(), "someone else"))
+	notifications.Add(second)
+
+	got := notifications.ForUser(userID)
+	if len(got) != 2 || got[0].ID != second.ID || got[1].ID != first.ID {
+		t.Errorf("ForUser() = %v, want the newest first", got)
+	}
+
+	// the notifications handed out are copies, changed only through the repository
+	got[1].MarkRead(time.Now())
+	if notifications.Get(first.ID).Read() {
+		t.Error("marking a copy read marked the notification read")
+	}
+	readAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	notifications.MarkRead(first.ID, readAt)
+	notifications.MarkRead(first.ID, readAt.Add(time.Hour))
+	if read := notifications.Get(first.ID); !read.Read() || !read.ReadAt.Equal(readAt) {
+		t.Errorf("MarkRead() changed ReadAt to %v, want %v", read.ReadAt, readAt)
+	}
+}
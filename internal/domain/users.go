@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Users is an in-memory UserRepository, safe for use by concurrent requests
+//
+// The users are kept and handed out as copies, so a user changed by one request is not seen half changed by
+// another until it is saved.
+type Users struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]*User
+}
+
+func NewUsers() *Users {
+	return &Users{users: make(map[uuid.UUID]*User)}
+}
+
+// Save adds the user or replaces the user with the same ID
+func (u *Users) Save(user *User) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	saved := *user
+	u.users[user.ID] = &saved
+}
+
+// Get returns the user with the ID, or nil when there is none
+func (u *Users) Get(id uuid.UUID) *User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return copyUser(u.users[id])
+}
+
+// GetByUsername returns the user with the username, ignoring case, or nil when there is none
+func (u *Users) GetByUsername(username string) *User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, user := range u.users {
+		if strings.EqualFold(user.Username, username) {
+			return copyUser(user)
+		}
+	}
+	return nil
+}
+
+// All returns the users ordered by username
+func (u *Users) All() []*User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	list := make([]*User, 0, len(u.users))
+	for _, user := range u.users {
+		list = append(list, copyUser(user))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Username < list[j].Username
+	})
+	return list
+}
+
+func copyUser(user *User) *User {
+	if user == nil {
+		return nil
+	}
+	c := *user
+	return &c
+}
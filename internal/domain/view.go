@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// View is a search saved by a user under a name
+type View struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+	Query  Query
+	Sort   SortOrder
+	// Pinned views are shown in the sidebar
+	Pinned    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewView creates a new view of the user
+func NewView(userID uuid.UUID, name string, query Query, order SortOrder, pinned bool) *View {
+	now := time.Now()
+	return &View{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Query:     query,
+		Sort:      order,
+		Pinned:    pinned,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Todos returns the todos in the view, in its order, leaving out those snoozed until later
+func (v *View) Todos(todos TodoRepository, now time.Time) []*Todo {
+	list := make([]*Todo, 0)
+	for _, todo := range todos.Find(v.Query) {
+		if !todo.IsSnoozed(now) {
+			list = append(list, todo)
+		}
+	}
+	v.Sort.Sort(list)
+	return list
+}
+
+// copy returns a copy of the view sharing nothing that can be changed with it
+func (v *View) copy() *View {
+	c := *v
+	if v.Query.Tags != nil {
+		c.Query.Tags = append([]string(nil), v.Query.Tags...)
+	}
+	return &c
+}
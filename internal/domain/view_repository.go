@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+type ViewRepository interface {
+	// Save adds the view or replaces the view with the same ID
+	Save(view *View)
+	Get(id uuid.UUID) *View
+	// ForUser returns the views of the user ordered by name
+	ForUser(userID uuid.UUID) []*View
+	// All returns the views of every user ordered by name
+	All() []*View
+	Remove(id uuid.UUID)
+}
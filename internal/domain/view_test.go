@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestView_Todos(t *testing.T) {
+	now := time.Now()
+	cat := NewTodo("Feed the cat")
+	cat.Tags = []string{"pets"}
+	dog := NewTodo("Walk the dog")
+	dog.Tags = []string{"pets"}
+	dog.Priority = PriorityHigh
+	fish := NewTodo("Feed the fish")
+	fish.Tags = []string{"pets"}
+	fish.Snooze(now.Add(time.Hour), uuid.New(), false)
+	list := NewTodos()
+	list.Save(cat, dog, fish, NewTodo("Bake a cake"))
+
+	view := NewView(uuid.New(), "Pets", Query{Tags: []string{"pets"}}, SortPriority, true)
+	got := view.Todos(list, now)
+	if len(got) != 2 || got[0].ID != dog.ID || got[1].ID != cat.ID {
+		t.Errorf("Todos() = %v, want the dog then the cat", got)
+	}
+}
+
+func TestViews_ForUser(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	views := NewViews()
+	work := NewView(alice, "work", Query{}, SortManual, false)
+	home := NewView(alice, "Home", Query{}, SortManual, false)
+	views.Save(work)
+	views.Save(home)
+	views.Save(NewView(bob, "Bob's", Query{}, SortManual, false))
+
+	got := views.ForUser(alice)
+	if len(got) != 2 || got[0].ID != home.ID || got[1].ID != work.ID {
+		t.Errorf("ForUser() = %v, want home then work", got)
+	}
+
+	// the views handed out are copies, changed only by saving them
+	got[0].Query.Tags = append(got[0].Query.Tags, "garden")
+	got[0].Name = "Garden"
+	if view := views.Get(home.ID); view.Name != "Home" || len(view.Query.Tags) != 0 {
+		t.Errorf("changing a view handed out changed it to %+v", view)
+	}
+
+	views.Remove(home.ID)
+	if views.Get(home.ID) != nil || len(views.ForUser(alice)) != 1 {
+		t.Errorf("Remove() left the view")
+	}
+}
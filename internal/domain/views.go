@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Views is an in-memory ViewRepository, safe for use by concurrent requests
+//
+// The views are kept and handed out as copies, so a changed view is only seen by others once it is saved.
+type Views struct {
+	mu    sync.RWMutex
+	views map[uuid.UUID]*View
+}
+
+func NewViews() *Views {
+	return &Views{views: make(map[uuid.UUID]*View)}
+}
+
+// Save adds the view or replaces the view with the same ID
+func (v *Views) Save(view *View) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.views[view.ID] = view.copy()
+}
+
+// Get returns the view with the ID, or nil when there is none
+func (v *Views) Get(id uuid.UUID) *View {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if view, ok := v.views[id]; ok {
+		return view.copy()
+	}
+	return nil
+}
+
+// ForUser returns the views of the user ordered by name
+func (v *Views) ForUser(userID uuid.UUID) []*View {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	list := make([]*View, 0)
+	for _, view := range v.views {
+		if view.UserID == userID {
+			list = append(list, view.copy())
+		}
+	}
+	sortViews(list)
+	return list
+}
+
+// All returns the views of every user ordered by name
+func (v *Views) All() []*View {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	list := make([]*View, 0, len(v.views))
+	for _, view := range v.views {
+		list = append(list, view.copy())
+	}
+	sortViews(list)
+	return list
+}
+
+func sortViews(list []*View) {
+	sort.Slice(list, func(i, j int) bool {
+		if a, b := strings.ToLower(list[i].Name), strings.ToLower(list[j].Name); a != b {
+			return a < b
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+}
+
+// Remove removes the view with the ID
+func (v *Views) Remove(id uuid.UUID) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.views, id)
+}
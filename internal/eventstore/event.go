@@ -0,0 +1,237 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// kind names what happened to the todos in an event
+type kind string
+
+const (
+	todoAdded    kind = "TodoAdded"
+	todosSaved   kind = "TodosSaved"
+	subtaskAdded kind = "SubtaskAdded"
+	todoRemoved  kind = "TodoRemoved"
+	reordered    kind = "Reordered"
+	// todoChanged is several changes made to a todo at once
+	todoChanged kind = "TodoChanged"
+	// todoReplaced is a change the store has no kind of event for, written as the todo it left
+	todoReplaced kind = "TodoReplaced"
+)
+
+// changeKinds are the kinds of event written for the changes made to a todo, each with the change it is read
+// back into
+var changeKinds = map[kind]func() domain.Change{
+	"TodoUpdated":       func() domain.Change { return &domain.Update{} },
+	"TodoRenamed":       func() domain.Change { return &domain.Rename{} },
+	"CompletedSet":      func() domain.Change { return &domain.Complete{} },
+	"PrioritySet":       func() domain.Change { return &domain.SetPriority{} },
+	"CategorySet":       func() domain.Change { return &domain.SetCategory{} },
+	"TagsSet":           func() domain.Change { return &domain.SetTags{} },
+	"DueDateSet":        func() domain.Change { return &domain.SetDueDate{} },
+	"ArchivedSet":       func() domain.Change { return &domain.SetArchived{} },
+	"RecurringSet":      func() domain.Change { return &domain.SetRecurring{} },
+	"EstimateSet":       func() domain.Change { return &domain.SetEstimate{} },
+	"TodoAssigned":      func() domain.Change { return &domain.Assign{} },
+	"TodoSnoozed":       func() domain.Change { return &domain.SetSnooze{} },
+	"TodoUnsnoozed":     func() domain.Change { return &domain.Unsnooze{} },
+	"TodoWoken":         func() domain.Change { return &domain.Wake{} },
+	"TodoReopened":      func() domain.Change { return &domain.Reopen{} },
+	"CommentAdded":      func() domain.Change { return &domain.AddComment{} },
+	"CommentEdited":     func() domain.Change { return &domain.EditComment{} },
+	"CommentRemoved":    func() domain.Change { return &domain.RemoveComment{} },
+	"TimerStarted":      func() domain.Change { return &domain.StartTimer{} },
+	"TimerStopped":      func() domain.Change { return &domain.StopTimer{} },
+	"TimeEntryAdded":    func() domain.Change { return &domain.AddTimeEntry{} },
+	"TimeEntryRemoved":  func() domain.Change { return &domain.RemoveTimeEntry{} },
+	"AttachmentAdded":   func() domain.Change { return &domain.AddAttachment{} },
+	"AttachmentRemoved": func() domain.Change { return &domain.RemoveAttachment{} },
+	"PomodoroAdded":     func() domain.Change { return &domain.AddPomodoro{} },
+}
+
+// kindsOfChanges names the kind of event for each type of change in changeKinds
+var kindsOfChanges = func() map[reflect.Type]kind {
+	kinds := make(map[reflect.Type]kind, len(changeKinds))
+	for k, newChange := range changeKinds {
+		kinds[reflect.TypeOf(newChange()).Elem()] = k
+	}
+	return kinds
+}()
+
+// event is a change to the todos as it is written to the log, one JSON object a line
+//
+// Each kind of event carries only what it needs to be played back.
+type event struct {
+	Seq  int64     `json:"seq"`
+	Kind kind      `json:"kind"`
+	At   time.Time `json:"at"`
+	// TodoID is the todo changed or removed
+	TodoID *uuid.UUID `json:"todoId,omitempty"`
+	// ParentID is the todo a subtask was added under
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+	// Todo is the record of a todo added, or of a todo as a change the store has no kind of event for left it
+	Todo json.RawMessage `json:"todo,omitempty"`
+	// Todos are the records of the todos saved together
+	Todos []json.RawMessage `json:"todos,omitempty"`
+	// Change is the change made, for the kinds of change
+	Change json.RawMessage `json:"change,omitempty"`
+	// Changes are the changes made at once, for TodoChanged
+	Changes []change `json:"changes,omitempty"`
+	// Order is the IDs of the todos reordered, in their new places
+	Order []uuid.UUID `json:"order,omitempty"`
+}
+
+// change is one of several changes made to a todo at once
+type change struct {
+	Kind   kind            `json:"kind"`
+	Change json.RawMessage `json:"change"`
+}
+
+// changeEvent returns the event for the changes made to a todo, and false when they changed nothing worth
+// writing
+//
+// Changes the store has no kind of event for, such as those made by a CalDAV client, are written as the todo
+// they left.
+func changeEvent(id uuid.UUID, changes []domain.Change, changed *domain.Todo) (event, bool, error) {
+	written := make([]change, 0, len(changes))
+	for _, c := range changes {
+		// an expected version changes nothing, and was checked as the change was made; it is not written, as
+		// the events of the log were all made and are played back without checking
+		if _, ok := c.(domain.Expect); ok {
+			continue
+		}
+		k, ok := kindsOfChanges[reflect.TypeOf(c)]
+		if !ok {
+			data, err := encodeRecord(changed)
+			return event{Kind: todoReplaced, TodoID: &id, Todo: data}, true, err
+		}
+		data, err := json.Marshal(c)
+		if err != nil {
+			return event{}, false, err
+		}
+		written = append(written, change{Kind: k, Change: data})
+	}
+
+	switch len(written) {
+	case 0:
+		return event{}, false, nil
+	case 1:
+		return event{Kind: written[0].Kind, TodoID: &id, Change: written[0].Change}, true, nil
+	default:
+		return event{Kind: todoChanged, TodoID: &id, Changes: written}, true, nil
+	}
+}
+
+// apply plays the event back onto the todos
+func apply(todos *domain.Todos, e event) error {
+	switch e.Kind {
+	case todoAdded, todoReplaced:
+		todo, err := decodeRecord(e.Todo)
+		if err != nil {
+			return err
+		}
+		todos.Save(todo)
+		return nil
+	case todosSaved:
+		list := make([]*domain.Todo, len(e.Todos))
+		for i, data := range e.Todos {
+			todo, err := decodeRecord(data)
+			if err != nil {
+				return err
+			}
+			list[i] = todo
+		}
+		todos.Save(list...)
+		return nil
+	case subtaskAdded:
+		todo, err := decodeRecord(e.Todo)
+		if err != nil {
+			return err
+		}
+		if e.ParentID == nil {
+			return fmt.Errorf("%s without a parent", e.Kind)
+		}
+		_, err = todos.AddSubtask(*e.ParentID, todo)
+		return err
+	case todoRemoved:
+		if e.TodoID != nil {
+			todos.Remove(*e.TodoID)
+		}
+		return nil
+	case reordered:
+		todos.Reorder(e.Order)
+		return nil
+	}
+
+	if e.TodoID == nil {
+		return fmt.Errorf("%s without a todo", e.Kind)
+	}
+	written := e.Changes
+	if e.Kind != todoChanged {
+		written = []change{{Kind: e.Kind, Change: e.Change}}
+	}
+	changes := make([]domain.Change, len(written))
+	for i, w := range written {
+		newChange, ok := changeKinds[w.Kind]
+		if !ok {
+			return fmt.Errorf("unknown kind of event %q", w.Kind)
+		}
+		c := newChange()
+		if err := json.Unmarshal(w.Change, c); err != nil {
+			return err
+		}
+		changes[i] = c
+	}
+	_, err := todos.ChangeAt(*e.TodoID, e.At, changes...)
+	return err
+}
+
+// record is a todo as it is written to the log
+//
+// Subtasks are named by ID rather than written out, so that each todo is written alone and a subtask is the
+// same todo in the list and under its parent once the records are read back.
+type record struct {
+	domain.Todo
+	Subtasks []uuid.UUID
+}
+
+func newRecord(todo *domain.Todo) record {
+	r := record{Todo: *todo}
+	r.Todo.Subtasks = nil
+	// a todo saved without subtasks keeps those it has, so none is kept apart from an empty list
+	if todo.Subtasks != nil {
+		r.Subtasks = make([]uuid.UUID, len(todo.Subtasks))
+		for i, subtask := range todo.Subtasks {
+			r.Subtasks[i] = subtask.ID
+		}
+	}
+	return r
+}
+
+func encodeRecord(todo *domain.Todo) ([]byte, error) {
+	return json.Marshal(newRecord(todo))
+}
+
+// decodeRecord reads a record back into a todo whose subtasks carry only their IDs, for the repository to
+// link to the todos it holds
+func decodeRecord(data []byte) (*domain.Todo, error) {
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	todo := r.Todo
+	if r.Subtasks != nil {
+		todo.Subtasks = make([]*domain.Todo, len(r.Subtasks))
+		for i, id := range r.Subtasks {
+			todo.Subtasks[i] = &domain.Todo{ID: id}
+		}
+	}
+	return &todo, nil
+}
@@ -0,0 +1,278 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+const snapshotDir = "snapshots"
+
+// snapshot is the todos as they were after an event, so that the events before it need not be played back
+//
+// Snapshots are kept in files named by the last event in them and when it happened, so that the one to start
+// from can be picked without reading them all.
+type snapshot struct {
+	Seq   int64             `json:"seq"`
+	At    time.Time         `json:"at"`
+	Todos []json.RawMessage `json:"todos"`
+}
+
+// Snapshot writes a snapshot of the todos as they are now, unless nothing has changed since the last one, and
+// starts the log afresh
+//
+// The events of the log are moved to the segments directory rather than thrown away, so that the todos can
+// still be rebuilt as they were before the snapshot.
+func (s *Store) Snapshot() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writePending(); err != nil {
+		return err
+	}
+	if s.seq == s.snapshotSeq {
+		return nil
+	}
+
+	todos := s.todos.All()
+	snap := snapshot{Seq: s.seq, At: s.last, Todos: make([]json.RawMessage, len(todos))}
+	for i, todo := range todos {
+		data, err := encodeRecord(todo)
+		if err != nil {
+			return err
+		}
+		snap.Todos[i] = data
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	// write next to the snapshot and move it into place so that a snapshot is never read half written
+	dir := filepath.Join(s.dir, snapshotDir)
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(dir, snapshotName(snap.Seq, snap.At))); err != nil {
+		return err
+	}
+
+	s.snapshotSeq = snap.Seq
+	return s.compact()
+}
+
+// compact moves the log to the segments directory, named by its last event, and starts a new one
+//
+// The log is left in place when the new one cannot be started.
+func (s *Store) compact() error {
+	path := filepath.Join(s.dir, logName)
+	segment := filepath.Join(s.dir, segmentDir, segmentName(s.seq))
+	if err := os.Rename(path, segment); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		if rerr := os.Rename(segment, path); rerr != nil {
+			s.logger.Printf("moving %s back: %v", segment, rerr)
+		}
+		return err
+	}
+
+	old := s.log
+	s.log, s.size = f, 0
+	return old.Close()
+}
+
+// TodosAt returns the todos as they were at the time, in the order they were in
+//
+// The todos are rebuilt from the latest snapshot taken by then and the events that followed it up to the time,
+// without holding up changes to the store while they are. They are copies; changing them changes nothing in the
+// store.
+func (s *Store) TodosAt(at time.Time) ([]*domain.Todo, error) {
+	// take hold of the log as it is now, as a snapshot may move it to the segments while it is read
+	s.mu.Lock()
+	_ = s.writePending()
+	f, err := os.Open(filepath.Join(s.dir, logName))
+	size := s.size
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	todos := domain.NewTodos()
+	var played int64
+	snap, err := s.latestSnapshot(at)
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		if err = load(todos, snap.Todos); err != nil {
+			return nil, fmt.Errorf("loading snapshot %d: %w", snap.Seq, err)
+		}
+		played = snap.Seq
+	}
+
+	visit := func(e event) (bool, error) {
+		if e.At.After(at) {
+			return false, nil
+		}
+		// a log moved to the segments since it was taken hold of is read twice
+		if e.Seq <= played {
+			return true, nil
+		}
+		if err := apply(todos, e); err != nil {
+			return false, fmt.Errorf("playing back event %d: %w", e.Seq, err)
+		}
+		played = e.Seq
+		return true, nil
+	}
+	more, err := s.readSegments(played, visit)
+	if err != nil || !more {
+		return todos.All(), err
+	}
+	var read int64
+	if err = readEvents(io.LimitReader(f, size), visit, &read); err != nil {
+		return nil, err
+	}
+	return todos.All(), nil
+}
+
+// load adds the todos of a snapshot
+func load(todos *domain.Todos, records []json.RawMessage) error {
+	list := make([]*domain.Todo, len(records))
+	for i, data := range records {
+		todo, err := decodeRecord(data)
+		if err != nil {
+			return err
+		}
+		list[i] = todo
+	}
+	todos.Save(list...)
+	return nil
+}
+
+// readSegments plays back the events of the segments that follow the event, oldest first, returning false
+// when visit stopped it
+func (s *Store) readSegments(after int64, visit func(e event) (bool, error)) (bool, error) {
+	dir := filepath.Join(s.dir, segmentDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// the names are zero-padded, so they are read oldest first
+	for _, entry := range entries {
+		last, ok := parseSegmentName(entry.Name())
+		if !ok || last <= after {
+			continue
+		}
+		more := true
+		err := func() error {
+			f, err := os.Open(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			var size int64
+			return readEvents(f, func(e event) (bool, error) {
+				more, err = visit(e)
+				return more, err
+			}, &size)
+		}()
+		if err != nil {
+			return false, fmt.Errorf("reading segment %s: %w", entry.Name(), err)
+		}
+		if !more {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func segmentName(seq int64) string {
+	return fmt.Sprintf("events-%020d.jsonl", seq)
+}
+
+func parseSegmentName(name string) (int64, bool) {
+	var seq int64
+	if n, err := fmt.Sscanf(name, "events-%020d.jsonl", &seq); err != nil || n != 1 || segmentName(seq) != name {
+		return 0, false
+	}
+	return seq, true
+}
+
+// latestSnapshot reads the latest snapshot taken by the time, or the latest of all for the zero time
+//
+// Nil is returned when there is no such snapshot. A snapshot that cannot be read is passed over for the one
+// before it, as the events it holds are still in the segments.
+func (s *Store) latestSnapshot(by time.Time) (*snapshot, error) {
+	dir := filepath.Join(s.dir, snapshotDir)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		seq, at, ok := parseSnapshotName(entry.Name())
+		if !ok || seq == 0 || (!by.IsZero() && at.After(by)) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	// the names start with the zero-padded sequence number, so they sort oldest first
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var snap snapshot
+		if err = json.Unmarshal(data, &snap); err != nil {
+			s.logger.Printf("passing over snapshot %s: %v", name, err)
+			continue
+		}
+		return &snap, nil
+	}
+	return nil, nil
+}
+
+func snapshotName(seq int64, at time.Time) string {
+	return fmt.Sprintf("%020d-%020d.json", seq, at.UnixNano())
+}
+
+func parseSnapshotName(name string) (int64, time.Time, bool) {
+	var seq, nanos int64
+	if n, err := fmt.Sscanf(name, "%020d-%020d.json", &seq, &nanos); err != nil || n != 2 || snapshotName(seq, time.Unix(0, nanos)) != name {
+		return 0, time.Time{}, false
+	}
+	return seq, time.Unix(0, nanos), true
+}
@@ -0,0 +1,421 @@
+// Package eventstore keeps the todos as an append-only log of the changes made to them
+//
+// Every change is written as an event, such as TodoAdded, CommentAdded or Reordered, to events.jsonl in the
+// store's directory. The todos are rebuilt by playing the events back when the store is opened, starting from
+// the latest snapshot, and can be rebuilt as they were at any earlier time. Each snapshot starts the log
+// afresh; the events it held are kept in the segments directory for looking back.
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+const (
+	logName    = "events.jsonl"
+	segmentDir = "segments"
+)
+
+var errClosed = errors.New("the event store is closed")
+
+// Store is a todo repository that writes every change to its log
+//
+// The todos are kept in a domain.Todos, which hands out copies, so they are changed only through the calls of
+// the store. Each call that changes them writes an event for the change as it is made, while no other change
+// can be made, so that the events are in the order the changes were.
+type Store struct {
+	mu     sync.Mutex
+	dir    string
+	logger *log.Logger
+	now    func() time.Time
+
+	todos *domain.Todos
+	log   *os.File
+	// size is how long the log is, to cut off a half-written batch of events
+	size int64
+	seq  int64
+	// last is when the last event happened; events never go back in time
+	last time.Time
+	// pending are the events the log could not take, written again with the next
+	pending []event
+	// snapshotSeq is the last event in the latest snapshot
+	snapshotSeq int64
+	// err is why the last events could not be written, until they are
+	err    error
+	closed bool
+}
+
+var (
+	_ domain.TodoRepository = (*Store)(nil)
+	_ domain.TodoHistory    = (*Store)(nil)
+)
+
+// Open opens the store kept in the directory, creating it when it is not there, and plays back its events
+//
+// Errors writing events as the todos are changed are logged to the logger; the events are written again by
+// the next call.
+func Open(dir string, logger *log.Logger) (*Store, error) {
+	for _, sub := range []string{snapshotDir, segmentDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Store{
+		dir:    dir,
+		logger: logger,
+		now:    time.Now,
+		todos:  domain.NewTodos(),
+	}
+
+	snap, err := s.latestSnapshot(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		if err = load(s.todos, snap.Todos); err != nil {
+			return nil, fmt.Errorf("loading snapshot %d: %w", snap.Seq, err)
+		}
+		s.seq, s.last, s.snapshotSeq = snap.Seq, snap.At, snap.Seq
+	}
+
+	visit := func(e event) (bool, error) {
+		if e.Seq <= s.seq {
+			return true, nil
+		}
+		if err := apply(s.todos, e); err != nil {
+			return false, fmt.Errorf("playing back event %d: %w", e.Seq, err)
+		}
+		s.seq, s.last = e.Seq, e.At
+		return true, nil
+	}
+	// the segments are only read when the latest snapshot could not be, or was taken before the log was last
+	// started afresh
+	if _, err = s.readSegments(s.seq, visit); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, logName)
+	size, err := s.replay(path, visit)
+	if err != nil {
+		return nil, err
+	}
+	if s.log, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644); err != nil {
+		return nil, err
+	}
+	s.size = size
+	return s, nil
+}
+
+// replay plays back the events of the log, returning how long the log is
+//
+// A last line cut short, as by a crash while it was being written, is cut off the log.
+func (s *Store) replay(path string, visit func(e event) (bool, error)) (int64, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var size int64
+	err = readEvents(f, visit, &size)
+	if errors.Is(err, errTornEvent) {
+		s.logger.Printf("cutting a half-written event off the end of %s", path)
+		return size, f.Truncate(size)
+	}
+	return size, err
+}
+
+// errTornEvent is a last line of the log that is not a whole event
+var errTornEvent = errors.New("half-written event")
+
+// readEvents reads the log an event at a time until visit returns false, keeping size at the end of the
+// last whole event read
+func readEvents(r io.Reader, visit func(e event) (bool, error), size *int64) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				return errTornEvent
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var e event
+		if err = json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("reading the event after byte %d: %w", *size, err)
+		}
+		*size += int64(len(line))
+		if more, err := visit(e); err != nil || !more {
+			return err
+		}
+	}
+}
+
+// Flush writes the events that could not be written as the changes were made
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = s.writePending()
+	return s.err
+}
+
+// Check returns why the store cannot take changes: it has been closed, its log has gone or the last events
+// could not be written to it
+func (s *Store) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errClosed
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, logName)); err != nil {
+		return err
+	}
+	return s.err
+}
+
+// Close writes the last events and closes the log
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.writePending()
+	s.err = err
+	s.closed = true
+	if cerr := s.log.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// at returns when a change made now happens; events never go back in time
+func (s *Store) at() time.Time {
+	at := s.now()
+	if at.Before(s.last) {
+		at = s.last
+	}
+	return at
+}
+
+// write numbers the event as happening at the time and writes it to the log, along with any the log could not
+// take before, logging what cannot be written
+//
+// An event that could not be made, as its todo could not be encoded, is logged and left out.
+func (s *Store) write(e event, err error, at time.Time) {
+	if err != nil {
+		s.err = err
+		s.logger.Printf("making a todo event: %v", err)
+		return
+	}
+	s.seq++
+	e.Seq, e.At = s.seq, at
+	s.last = at
+	s.pending = append(s.pending, e)
+
+	if s.err = s.writePending(); s.err != nil {
+		s.logger.Printf("writing todo events: %v", s.err)
+	}
+}
+
+// writePending writes the events the log has yet to take
+func (s *Store) writePending() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range s.pending {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := s.append(buf.Bytes()); err != nil {
+		return err
+	}
+	s.pending = nil
+	return nil
+}
+
+// append writes the lines to the end of the log, leaving the log as it was when they cannot all be written
+func (s *Store) append(lines []byte) error {
+	n, err := s.log.Write(lines)
+	if err == nil {
+		err = s.log.Sync()
+	}
+	if err != nil {
+		if n > 0 {
+			_ = s.log.Truncate(s.size)
+		}
+		return err
+	}
+	s.size += int64(n)
+	return nil
+}
+
+func (s *Store) Add(description string) *domain.Todo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.at()
+	todo := s.todos.Add(description)
+	data, err := encodeRecord(todo)
+	s.write(event{Kind: todoAdded, TodoID: &todo.ID, Todo: data}, err, at)
+	return todo
+}
+
+func (s *Store) Save(todos ...*domain.Todo) {
+	if len(todos) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.at()
+	s.todos.Save(todos...)
+	e := event{Kind: todosSaved, Todos: make([]json.RawMessage, len(todos))}
+	var err error
+	for i, todo := range todos {
+		if e.Todos[i], err = encodeRecord(todo); err != nil {
+			break
+		}
+	}
+	s.write(e, err, at)
+}
+
+func (s *Store) AddSubtask(parentID uuid.UUID, subtask *domain.Todo) (*domain.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.at()
+	added, err := s.todos.AddSubtask(parentID, subtask)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodeRecord(added)
+	s.write(event{Kind: subtaskAdded, ParentID: &parentID, TodoID: &added.ID, Todo: data}, err, at)
+	return added, nil
+}
+
+func (s *Store) Change(id uuid.UUID, changes ...domain.Change) (*domain.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.at()
+	changed, err := s.todos.ChangeAt(id, at, changes...)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok, err := changeEvent(id, changes, changed); ok || err != nil {
+		s.write(e, err, at)
+	}
+	return changed, nil
+}
+
+func (s *Store) Remove(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.todos.Get(id) == nil {
+		return
+	}
+	at := s.at()
+	s.todos.Remove(id)
+	s.write(event{Kind: todoRemoved, TodoID: &id}, nil, at)
+}
+
+func (s *Store) Update(id uuid.UUID, completed bool, description string) *domain.Todo {
+	todo, err := s.Change(id, domain.Update{Completed: completed, Description: description})
+	if err != nil {
+		return nil
+	}
+	return todo
+}
+
+func (s *Store) Reorder(ids []uuid.UUID) []*domain.Todo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.at()
+	list := s.todos.Reorder(ids)
+	if len(list) > 0 {
+		s.write(event{Kind: reordered, Order: ids}, nil, at)
+	}
+	return list
+}
+
+// The todos are read without the lock of the store, as domain.Todos keeps them safe to read while they change
+
+func (s *Store) Get(id uuid.UUID) *domain.Todo {
+	return s.todos.Get(id)
+}
+
+func (s *Store) Search(search string) []*domain.Todo {
+	return s.todos.Search(search)
+}
+
+func (s *Store) All() []*domain.Todo {
+	return s.todos.All()
+}
+
+func (s *Store) GetByCategory(category string) []*domain.Todo {
+	return s.todos.GetByCategory(category)
+}
+
+func (s *Store) GetByTag(tag string) []*domain.Todo {
+	return s.todos.GetByTag(tag)
+}
+
+func (s *Store) GetByPriority(priority domain.Priority) []*domain.Todo {
+	return s.todos.GetByPriority(priority)
+}
+
+func (s *Store) GetByDueDate(start, end time.Time) []*domain.Todo {
+	return s.todos.GetByDueDate(start, end)
+}
+
+func (s *Store) GetByAssignee(userID uuid.UUID) []*domain.Todo {
+	return s.todos.GetByAssignee(userID)
+}
+
+func (s *Store) GetRecurring() []*domain.Todo {
+	return s.todos.GetRecurring()
+}
+
+func (s *Store) GetArchived() []*domain.Todo {
+	return s.todos.GetArchived()
+}
+
+func (s *Store) GetSubtasks(parentID uuid.UUID) []*domain.Todo {
+	return s.todos.GetSubtasks(parentID)
+}
+
+func (s *Store) GetOverdue() []*domain.Todo {
+	return s.todos.GetOverdue()
+}
+
+func (s *Store) GetUpcoming(days int) []*domain.Todo {
+	return s.todos.GetUpcoming(days)
+}
+
+func (s *Store) Find(query domain.Query) []*domain.Todo {
+	return s.todos.Find(query)
+}
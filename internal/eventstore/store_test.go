@@ -0,0 +1,321 @@
+package eventstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func open(t *testing.T, dir string) *Store {
+	t.Helper()
+	s, err := Open(dir, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// kinds returns the kinds of the events in the log of the store, in order
+func kinds(t *testing.T, dir string) []kind {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, logName))
+	if err != nil {
+		t.Fatalf("opening the log: %v", err)
+	}
+	defer f.Close()
+	list := make([]kind, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var e event
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("reading an event: %v", err)
+		}
+		list = append(list, e.Kind)
+	}
+	return list
+}
+
+// sameTodos fails the test unless both lists hold the same todos in the same order
+func sameTodos(t *testing.T, got, want []*domain.Todo) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d todos, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, _ := encodeRecord(got[i])
+		w, _ := encodeRecord(want[i])
+		if string(g) != string(w) {
+			t.Errorf("todo %d = %s, want %s", i, g, w)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+
+	cake := s.Add("Bake a cake")
+	cat := s.Add("Feed the cat")
+	flour, _ := s.AddSubtask(cake.ID, domain.NewTodo("Buy flour"))
+	_, _ = s.Change(cake.ID, domain.SetPriority{Priority: domain.PriorityHigh})
+	s.Update(cat.ID, true, "Feed the cat")
+	_, _ = s.Change(cat.ID, domain.AddComment{Comment: domain.Comment{ID: uuid.New(), Content: "Done twice", UserID: uuid.New()}})
+	s.Reorder([]uuid.UUID{cat.ID, cake.ID})
+	trash := s.Add("Take out the trash")
+	trash, _ = s.Change(trash.ID, domain.SetArchived{Archived: true})
+	s.Remove(flour.ID)
+	cat = s.Update(cat.ID, false, "Feed the cats")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := []kind{
+		todoAdded, todoAdded, subtaskAdded, "PrioritySet", "TodoUpdated", "CommentAdded", reordered, todoAdded,
+		"ArchivedSet", todoRemoved, "TodoUpdated",
+	}
+	if got := kinds(t, dir); len(got) != len(want) {
+		t.Errorf("events = %v, want %v", got, want)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("events = %v, want %v", got, want)
+				break
+			}
+		}
+	}
+
+	// the removed subtask is no longer linked to the cake once the events are played back
+	reopened := open(t, dir)
+	all := reopened.All()
+	sameTodos(t, []*domain.Todo{all[0], all[2]}, []*domain.Todo{cat, trash})
+	if all[1].ID != cake.ID || len(all[1].Subtasks) != 0 || all[1].Priority != domain.PriorityHigh {
+		t.Errorf("All()[1] = %+v, want the cake without its subtask", all[1])
+	}
+	if got := reopened.Get(cat.ID); got.Description != "Feed the cats" || got.Completed || len(got.Comments) != 1 {
+		t.Errorf("Get() = %+v after reopening", got)
+	}
+
+	// nothing is written for playing the events back
+	before := len(kinds(t, dir))
+	if err := reopened.Flush(); err != nil || len(kinds(t, dir)) != before {
+		t.Errorf("Flush() after reopening wrote %d events, %v", len(kinds(t, dir))-before, err)
+	}
+}
+
+func TestStore_subtasks(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	trip := s.Add("Plan vacation")
+	flights, _ := s.AddSubtask(trip.ID, domain.NewTodo("Book flights"))
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := open(t, dir)
+	parent, subtask := reopened.Get(trip.ID), reopened.Get(flights.ID)
+	if len(parent.Subtasks) != 1 || parent.Subtasks[0].ID != subtask.ID || subtask.ParentID == nil || *subtask.ParentID != trip.ID {
+		t.Fatalf("the subtask is not linked to its parent after reopening: %+v", parent.Subtasks)
+	}
+	// a change to the subtask is one event for the subtask alone
+	reopened.Update(flights.ID, true, "Book flights")
+	if got := kinds(t, dir); got[len(got)-1] != "TodoUpdated" || len(got) != 3 {
+		t.Errorf("events = %v, want the subtask completed last", got)
+	}
+}
+
+func TestStore_TodosAt(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	now := monday
+	s.now = func() time.Time { return now }
+
+	cake := s.Add("Bake a cake")
+	s.Add("Feed the cat")
+	mondayTodos := make([]*domain.Todo, 0)
+	for _, todo := range s.All() {
+		copied := *todo
+		mondayTodos = append(mondayTodos, &copied)
+	}
+
+	now = monday.AddDate(0, 0, 1)
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	s.Update(cake.ID, true, "Bake a cake")
+	now = monday.AddDate(0, 0, 2)
+	s.Remove(cake.ID)
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	now = monday.AddDate(0, 0, 3)
+	s.Add("Take out the trash")
+
+	tests := map[string]struct {
+		at   time.Time
+		want []string
+	}{
+		"before anything": {at: monday.Add(-time.Hour), want: []string{}},
+		"monday":          {at: monday.Add(time.Hour), want: []string{"Bake a cake", "Feed the cat"}},
+		"tuesday":         {at: monday.AddDate(0, 0, 1), want: []string{"Bake a cake (done)", "Feed the cat"}},
+		"wednesday":       {at: monday.AddDate(0, 0, 2).Add(time.Hour), want: []string{"Feed the cat"}},
+		"now":             {at: monday.AddDate(0, 1, 0), want: []string{"Feed the cat", "Take out the trash"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			todos, err := s.TodosAt(tc.at)
+			if err != nil {
+				t.Fatalf("TodosAt() error = %v", err)
+			}
+			got := make([]string, len(todos))
+			for i, todo := range todos {
+				got[i] = todo.Description
+				if todo.Completed {
+					got[i] += " (done)"
+				}
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("TodosAt() = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("TodosAt() = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	// the todos of the past are copies
+	past, _ := s.TodosAt(monday.Add(time.Hour))
+	sameTodos(t, past, mondayTodos)
+	past[0].Description = "Eat the cake"
+	if got := s.All()[0].Description; got != "Feed the cat" {
+		t.Errorf("changing a todo of the past changed the store: %q", got)
+	}
+}
+
+func TestStore_Snapshot(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	s.Add("Bake a cake")
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	// nothing changed, so there is nothing to snapshot
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, snapshotDir))
+	if len(entries) != 1 {
+		t.Fatalf("snapshots = %d, want 1", len(entries))
+	}
+	s.Add("Feed the cat")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// the log is started afresh, keeping the events of the snapshot in a segment
+	if got := kinds(t, dir); len(got) != 1 || got[0] != todoAdded {
+		t.Errorf("events = %v, want only those since the snapshot", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, segmentDir)); len(entries) != 1 {
+		t.Errorf("segments = %d, want 1", len(entries))
+	}
+	reopened := open(t, dir)
+	if got := reopened.All(); len(got) != 2 || got[0].Description != "Bake a cake" || got[1].Description != "Feed the cat" {
+		t.Errorf("All() after reopening from the snapshot = %d todos", len(got))
+	}
+}
+
+func TestStore_TodosAt_concurrent(t *testing.T) {
+	s := open(t, t.TempDir())
+	cake := s.Add("Bake a cake")
+
+	// looking back while the todos change and snapshots start the log afresh
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			s.Update(cake.ID, i%2 == 0, "Bake a cake")
+			if i%10 == 0 {
+				_ = s.Snapshot()
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		todos, err := s.TodosAt(time.Now())
+		if err != nil || len(todos) != 1 {
+			t.Fatalf("TodosAt() = %d todos, %v", len(todos), err)
+		}
+	}
+	<-done
+}
+
+func TestOpen_tornEvent(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	s.Add("Bake a cake")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// a crash while the next event was being written leaves half of it behind
+	path := filepath.Join(dir, logName)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"seq":2,"kind":"TodoAdd`)
+	_ = f.Close()
+
+	reopened := open(t, dir)
+	reopened.Add("Feed the cat")
+	if err = reopened.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := kinds(t, dir); len(got) != 2 {
+		t.Errorf("events = %v, want the half-written one cut off", got)
+	}
+	if got := open(t, dir).All(); len(got) != 2 {
+		t.Errorf("All() = %d todos, want 2", len(got))
+	}
+}
+
+func TestStore_Check(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir)
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check() of an open store = %v", err)
+	}
+
+	// changes that cannot be written are reported until they are
+	_ = s.log.Close()
+	s.Add("Feed the cat")
+	if err := s.Check(); err == nil {
+		t.Error("Check() = nil after the changes could not be written")
+	}
+	if s.log, _ = os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_APPEND, 0o644); s.Flush() != nil {
+		t.Fatal("Flush() failed with the log open again")
+	}
+	if err := s.Check(); err != nil {
+		t.Errorf("Check() once the changes were written = %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := s.Check(); err != errClosed {
+		t.Errorf("Check() of a closed store = %v, want %v", err, errClosed)
+	}
+}
@@ -0,0 +1,15 @@
+package attachments
+
+import (
+	"errors"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+var (
+	ErrTodoNotFound       = domain.ErrTodoNotFound
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrEmptyFile          = errors.New("the file is empty")
+	ErrTooLarge           = errors.New("the file is too large")
+	ErrUnsupportedType    = errors.New("the type of file is not allowed")
+)
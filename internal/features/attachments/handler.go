@@ -0,0 +1,190 @@
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+type (
+	Handler interface {
+		// Upload : POST /todos/{todoId}/attachments
+		Upload(w http.ResponseWriter, r *http.Request)
+		// Download : GET /todos/{todoId}/attachments/{attachmentId}
+		Download(w http.ResponseWriter, r *http.Request)
+		// Thumbnail : GET /todos/{todoId}/attachments/{attachmentId}/thumbnail
+		Thumbnail(w http.ResponseWriter, r *http.Request)
+		// Delete : DELETE /todos/{todoId}/attachments/{attachmentId}
+		// Delete : POST /todos/{todoId}/attachments/{attachmentId}/delete
+		Delete(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+// multipartOverhead is room for the parts of an upload that are not the file
+const multipartOverhead = 1 << 20
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/todos/{todoId}/attachments", func(r chi.Router) {
+		r.Post("/", h.Upload)
+		r.Route("/{attachmentId}", func(r chi.Router) {
+			r.Get("/", h.Download)
+			r.Get("/thumbnail", h.Thumbnail)
+			r.Delete("/", h.Delete)
+			r.Post("/delete", h.Delete)
+		})
+	})
+}
+
+func (h handler) Upload(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.Limits().MaxSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			attachmentError(w, ErrTooLarge, h.service.Limits())
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	defer file.Close()
+
+	if _, err = h.service.Upload(r.Context(), todoID, header.Filename, file); err != nil {
+		attachmentError(w, err, h.service.Limits())
+		return
+	}
+
+	h.renderAttachments(w, r, todoID)
+}
+
+func (h handler) Download(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, false)
+}
+
+func (h handler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, true)
+}
+
+func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
+	todoID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), todoID, attachmentID); err != nil {
+		attachmentError(w, err, h.service.Limits())
+		return
+	}
+
+	h.renderAttachments(w, r, todoID)
+}
+
+// serve writes the content of the attachment, or of its thumbnail, for the browser to show or save
+func (h handler) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	todoID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attachment, content, err := h.service.Open(r.Context(), todoID, attachmentID, thumbnail)
+	if err != nil {
+		attachmentError(w, err, h.service.Limits())
+		return
+	}
+	defer content.Close()
+
+	header := w.Header()
+	// the content behind an attachment ID never changes
+	header.Set("Cache-Control", "private, max-age=31536000, immutable")
+	header.Set("X-Content-Type-Options", "nosniff")
+	// uploaded files are shown, never run
+	header.Set("Content-Security-Policy", "sandbox; default-src 'none'; img-src 'self'; style-src 'unsafe-inline'")
+	switch thumbnail {
+	case true:
+		header.Set("Content-Type", "image/png")
+	default:
+		disposition := "attachment"
+		if attachment.IsImage() || attachment.ContentType == "application/pdf" {
+			disposition = "inline"
+		}
+		header.Set("Content-Type", attachment.ContentType)
+		header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	}
+
+	_, _ = io.Copy(w, content)
+}
+
+// renderAttachments responds with the attachments section of the todo, or sends the browser back to the todo
+func (h handler) renderAttachments(w http.ResponseWriter, r *http.Request, todoID uuid.UUID) {
+	if !isHTMX(r) {
+		http.Redirect(w, r, "/todos/"+todoID.String()+"#attachments", http.StatusFound)
+		return
+	}
+
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		attachmentError(w, err, h.service.Limits())
+		return
+	}
+	if err = partials.Attachments(todo).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func attachmentIDs(r *http.Request) (todoID uuid.UUID, attachmentID uuid.UUID, err error) {
+	if todoID, err = uuid.Parse(chi.URLParam(r, "todoId")); err != nil {
+		return
+	}
+	attachmentID, err = uuid.Parse(chi.URLParam(r, "attachmentId"))
+	return
+}
+
+func attachmentError(w http.ResponseWriter, err error, limits Limits) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound), errors.Is(err, ErrAttachmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrTooLarge):
+		http.Error(w, fmt.Sprintf("%s; the limit is %s", err, partials.FormatSize(limits.MaxSize)), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrUnsupportedType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, ErrEmptyFile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
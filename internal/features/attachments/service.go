@@ -0,0 +1,236 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/blob"
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
+)
+
+type (
+	Service interface {
+		// Get returns the todo the attachments belong to
+		Get(ctx context.Context, todoID uuid.UUID) (*domain.Todo, error)
+		// Upload keeps the content and attaches it to the todo under the filename
+		Upload(ctx context.Context, todoID uuid.UUID, filename string, content io.Reader) (*domain.Attachment, error)
+		// Open returns the attachment with its content, or with its thumbnail; the caller closes the content
+		Open(ctx context.Context, todoID, attachmentID uuid.UUID, thumbnail bool) (*domain.Attachment, io.ReadCloser, error)
+		// Delete removes the attachment from the todo along with its content
+		Delete(ctx context.Context, todoID, attachmentID uuid.UUID) error
+		// RemoveAll removes the content of every attachment of a todo that is being deleted
+		RemoveAll(ctx context.Context, todo *domain.Todo) error
+		// Limits returns what may be uploaded
+		Limits() Limits
+	}
+
+	// Limits are what may be uploaded
+	Limits struct {
+		// MaxSize is the largest file in bytes
+		MaxSize int64
+		// ContentTypes are the media types allowed, as sniffed from the content rather than as claimed by the browser
+		ContentTypes []string
+	}
+
+	service struct {
+		todos  domain.TodoRepository
+		blobs  blob.Store
+		limits Limits
+	}
+)
+
+// DefaultLimits allow screenshots, photos, PDFs and text files of up to 10 MiB
+var DefaultLimits = Limits{
+	MaxSize: 10 << 20,
+	ContentTypes: []string{
+		"image/png",
+		"image/jpeg",
+		"image/gif",
+		"image/webp",
+		"application/pdf",
+		"text/plain",
+	},
+}
+
+func NewService(todos domain.TodoRepository, blobs blob.Store, limits Limits) Service {
+	return &service{
+		todos:  todos,
+		blobs:  blobs,
+		limits: limits,
+	}
+}
+
+// Allows returns true when the media type is one that may be uploaded
+func (l Limits) Allows(contentType string) bool {
+	for _, allowed := range l.ContentTypes {
+		if strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s service) Get(_ context.Context, todoID uuid.UUID) (*domain.Todo, error) {
+	todo := s.todos.Get(todoID)
+	if todo == nil {
+		return nil, ErrTodoNotFound
+	}
+
+	return todo, nil
+}
+
+func (s service) Upload(ctx context.Context, todoID uuid.UUID, filename string, content io.Reader) (*domain.Attachment, error) {
+	todo := s.todos.Get(todoID)
+	if todo == nil {
+		return nil, ErrTodoNotFound
+	}
+
+	// read one byte past the limit to tell a file of exactly the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(content, s.limits.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(data) == 0:
+		return nil, ErrEmptyFile
+	case int64(len(data)) > s.limits.MaxSize:
+		return nil, ErrTooLarge
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || !s.limits.Allows(contentType) {
+		return nil, ErrUnsupportedType
+	}
+
+	attachment := domain.Attachment{
+		ID:          uuid.New(),
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		UserID:      identity.UserID(ctx),
+		CreatedAt:   time.Now(),
+	}
+	attachment.BlobKey = blobKey(todo.ID, attachment.ID)
+	if err = s.blobs.Put(ctx, attachment.BlobKey, contentType, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if attachment.IsImage() {
+		if thumb, ok := thumbnail(data); ok {
+			key := attachment.BlobKey + ".thumbnail.png"
+			// an image without a thumbnail is still worth attaching
+			if s.blobs.Put(ctx, key, "image/png", bytes.NewReader(thumb)) == nil {
+				attachment.ThumbnailKey = key
+			}
+		}
+	}
+
+	if todo, err = s.todos.Change(todoID, domain.AddAttachment{Attachment: attachment}); err != nil {
+		// the todo went away while the content was being kept
+		_ = s.removeBlobs(ctx, attachment)
+		return nil, err
+	}
+	return todo.Attachment(attachment.ID), nil
+}
+
+func (s service) Open(ctx context.Context, todoID, attachmentID uuid.UUID, thumbnail bool) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachment(todoID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.BlobKey
+	if thumbnail {
+		if !attachment.HasThumbnail() {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		key = attachment.ThumbnailKey
+	}
+	content, err := s.blobs.Get(ctx, key)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (s service) Delete(ctx context.Context, todoID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachment(todoID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.removeBlobs(ctx, *attachment); err != nil {
+		return err
+	}
+	_, err = s.todos.Change(todoID, domain.RemoveAttachment{AttachmentID: attachmentID})
+	if errors.Is(err, domain.ErrUnchanged) {
+		return ErrAttachmentNotFound
+	}
+	return err
+}
+
+func (s service) RemoveAll(ctx context.Context, todo *domain.Todo) error {
+	for _, attachment := range todo.Attachments {
+		if err := s.removeBlobs(ctx, attachment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s service) Limits() Limits {
+	return s.limits
+}
+
+func (s service) attachment(todoID, attachmentID uuid.UUID) (*domain.Attachment, error) {
+	todo := s.todos.Get(todoID)
+	if todo == nil {
+		return nil, ErrTodoNotFound
+	}
+	attachment := todo.Attachment(attachmentID)
+	if attachment == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+func (s service) removeBlobs(ctx context.Context, attachment domain.Attachment) error {
+	if attachment.HasThumbnail() {
+		if err := s.blobs.Delete(ctx, attachment.ThumbnailKey); err != nil {
+			return err
+		}
+	}
+	return s.blobs.Delete(ctx, attachment.BlobKey)
+}
+
+func blobKey(todoID, attachmentID uuid.UUID) string {
+	return "todos/" + todoID.String() + "/" + attachmentID.String()
+}
+
+// cleanFilename keeps the last element of the name the browser gave, which may be a whole path
+func cleanFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+	if filename == "" || filename == "." || filename == "/" {
+		return "attachment"
+	}
+	return filename
+}
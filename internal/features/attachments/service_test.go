@@ -0,0 +1,175 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/blob"
+	"github.com/stackus/todos/internal/domain"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_service_Upload(t *testing.T) {
+	tests := map[string]struct {
+		filename        string
+		content         []byte
+		maxSize         int64
+		wantErr         error
+		wantType        string
+		wantFilename    string
+		wantThumbnail   bool
+		wantThumbWidth  int
+		wantThumbHeight int
+	}{
+		"Screenshot": {
+			filename:        "C:\\Users\\me\\Desktop\\screen.png",
+			content:         pngImage(t, 640, 320),
+			wantType:        "image/png",
+			wantFilename:    "screen.png",
+			wantThumbnail:   true,
+			wantThumbWidth:  thumbnailSize,
+			wantThumbHeight: thumbnailSize / 2,
+		},
+		"SmallImageKeepsItsSize": {
+			filename:        "icon.png",
+			content:         pngImage(t, 16, 32),
+			wantType:        "image/png",
+			wantFilename:    "icon.png",
+			wantThumbnail:   true,
+			wantThumbWidth:  16,
+			wantThumbHeight: 32,
+		},
+		"PDF": {
+			filename:     "../../invoice.pdf",
+			content:      []byte("%PDF-1.4\n%âãÏÓ\n1 0 obj\n"),
+			wantType:     "application/pdf",
+			wantFilename: "invoice.pdf",
+		},
+		"TypeIsSniffedNotClaimed": {
+			filename: "notes.png",
+			content:  []byte("<html><script>alert(1)</script></html>"),
+			wantErr:  ErrUnsupportedType,
+		},
+		"TooLarge": {
+			filename: "big.txt",
+			content:  []byte(strings.Repeat("x", 1025)),
+			maxSize:  1024,
+			wantErr:  ErrTooLarge,
+		},
+		"Empty": {
+			filename: "empty.txt",
+			wantErr:  ErrEmptyFile,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			list := domain.NewTodos()
+			todo := list.Add("Fix the layout")
+			limits := DefaultLimits
+			if tt.maxSize != 0 {
+				limits.MaxSize = tt.maxSize
+			}
+			s := NewService(list, blob.NewFileStore(t.TempDir()), limits)
+
+			attachment, err := s.Upload(context.Background(), todo.ID, tt.filename, bytes.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(todo.Attachments) != 0 {
+					t.Errorf("Attachments = %v, want none", todo.Attachments)
+				}
+				return
+			}
+			if attachment.ContentType != tt.wantType || attachment.Filename != tt.wantFilename || attachment.Size != int64(len(tt.content)) {
+				t.Errorf("Upload() = %+v, want a %s named %q", attachment, tt.wantType, tt.wantFilename)
+			}
+			if attachment.HasThumbnail() != tt.wantThumbnail {
+				t.Fatalf("HasThumbnail() = %v, want %v", attachment.HasThumbnail(), tt.wantThumbnail)
+			}
+
+			_, content, err := s.Open(context.Background(), todo.ID, attachment.ID, false)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			got, _ := io.ReadAll(content)
+			_ = content.Close()
+			if !bytes.Equal(got, tt.content) {
+				t.Errorf("Open() content differs from the upload")
+			}
+
+			_, content, err = s.Open(context.Background(), todo.ID, attachment.ID, true)
+			if !tt.wantThumbnail {
+				if !errors.Is(err, ErrAttachmentNotFound) {
+					t.Errorf("Open(thumbnail) error = %v, want %v", err, ErrAttachmentNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open(thumbnail) error = %v", err)
+			}
+			defer content.Close()
+			thumb, err := png.DecodeConfig(content)
+			if err != nil || thumb.Width != tt.wantThumbWidth || thumb.Height != tt.wantThumbHeight {
+				t.Errorf("thumbnail = %dx%d (%v), want %dx%d", thumb.Width, thumb.Height, err, tt.wantThumbWidth, tt.wantThumbHeight)
+			}
+		})
+	}
+}
+
+func Test_service_RemoveAll(t *testing.T) {
+	dir := t.TempDir()
+	list := domain.NewTodos()
+	todo := list.Add("Fix the layout")
+	s := NewService(list, blob.NewFileStore(dir), DefaultLimits)
+	ctx := context.Background()
+
+	first, err := s.Upload(ctx, todo.ID, "first.png", bytes.NewReader(pngImage(t, 8, 8)))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if _, err = s.Upload(ctx, todo.ID, "second.txt", strings.NewReader("notes")); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if err = s.Delete(ctx, todo.ID, first.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	todo = list.Get(todo.ID)
+	if len(todo.Attachments) != 1 || todo.Attachments[0].Filename != "second.txt" {
+		t.Errorf("Attachments = %v, want only the second", todo.Attachments)
+	}
+	if err = s.Delete(ctx, todo.ID, uuid.New()); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("Delete() error = %v, want %v", err, ErrAttachmentNotFound)
+	}
+
+	if err = s.RemoveAll(ctx, todo); err != nil {
+		t.Fatalf("RemoveAll() error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("entries = %v, want nothing left", entries)
+	}
+}
@@ -0,0 +1,79 @@
+package attachments
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+)
+
+const (
+	// thumbnailSize is the most pixels a thumbnail has along either side
+	thumbnailSize = 160
+	// maxPixels keeps small files that claim to be huge images from being decoded
+	maxPixels = 50_000_000
+)
+
+// thumbnail returns a PNG that fits within thumbnailSize, or false when the content is not an image that can be decoded
+func thumbnail(content []byte) ([]byte, bool) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil || config.Width*config.Height > maxPixels {
+		return nil, false
+	}
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, false
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, false
+	}
+	if width > thumbnailSize || height > thumbnailSize {
+		if width >= height {
+			width, height = thumbnailSize, max(1, height*thumbnailSize/bounds.Dx())
+		} else {
+			width, height = max(1, width*thumbnailSize/bounds.Dy()), thumbnailSize
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, scale(src, width, height)); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// scale shrinks the image by averaging the pixels that fall within each pixel of the result
+func scale(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
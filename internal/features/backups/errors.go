@@ -0,0 +1,8 @@
+package backups
+
+import "errors"
+
+var (
+	ErrUnauthorized = errors.New("a valid admin token is needed")
+	ErrDisabled     = errors.New("backups are turned off; the server has no admin token")
+)
@@ -0,0 +1,112 @@
+package backups
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/backup"
+)
+
+// maxRestoreSize is the largest compressed backup that can be restored
+const maxRestoreSize = 256 << 20
+
+type (
+	Handler interface {
+		// Backup : GET /admin/backup
+		Backup(w http.ResponseWriter, r *http.Request)
+		// Restore : POST /admin/restore
+		Restore(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+		// token is the bearer token the admin endpoints ask for; they are turned off without one
+		token string
+	}
+)
+
+func NewHandler(svc Service, token string) Handler {
+	return &handler{service: svc, token: token}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/admin", func(r chi.Router) {
+		r.Get("/backup", h.Backup)
+		r.Post("/restore", h.Restore)
+	})
+}
+
+// Backup streams a backup of everything, compressed
+func (h handler) Backup(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorize(r); err != nil {
+		backupError(w, err)
+		return
+	}
+
+	b, err := h.service.Backup(r.Context())
+	if err != nil {
+		backupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+backup.FileName(b.TakenAt)+`"`)
+	if err = backup.Write(w, b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Restore checks the backup in the request body and restores it, returning its manifest
+func (h handler) Restore(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorize(r); err != nil {
+		backupError(w, err)
+		return
+	}
+
+	b, manifest, err := backup.Read(http.MaxBytesReader(w, r.Body, maxRestoreSize))
+	if err != nil {
+		backupError(w, err)
+		return
+	}
+	if err = h.service.Restore(r.Context(), b); err != nil {
+		backupError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(manifest)
+}
+
+// authorize checks the request for the admin token
+func (h handler) authorize(r *http.Request) error {
+	if h.token == "" {
+		return ErrDisabled
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+func backupError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, ErrDisabled):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrUnauthorized):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="todos"`)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.As(err, &tooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, backup.ErrCorrupt), errors.Is(err, backup.ErrUnsupportedFormat):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,72 @@
+package backups
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/backup"
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestHandler(t *testing.T) {
+	todos := domain.NewTodos()
+	todos.Add("Feed the cat")
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(todos, domain.NewUsers(), domain.NewViews()), "secret"))
+
+	// an empty server to restore into
+	restored := domain.NewTodos()
+	empty := chi.NewRouter()
+	Mount(empty, NewHandler(NewService(restored, domain.NewUsers(), domain.NewViews()), "secret"))
+
+	do := func(router http.Handler, method, path, token string, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, token := range []string{"", "guess"} {
+		if rec := do(router, http.MethodGet, "/admin/backup", token, nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET /admin/backup with token %q = %d, want %d", token, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	rec := do(router, http.MethodGet, "/admin/backup", "secret", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/gzip" {
+		t.Fatalf("GET /admin/backup = %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	archive := rec.Body.Bytes()
+
+	if rec = do(empty, http.MethodPost, "/admin/restore", "secret", bytes.NewReader(archive[:len(archive)-8])); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("POST /admin/restore of a broken backup = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if len(restored.All()) != 0 {
+		t.Fatalf("a broken backup restored %d todos", len(restored.All()))
+	}
+
+	rec = do(empty, http.MethodPost, "/admin/restore", "secret", bytes.NewReader(archive))
+	var manifest backup.Manifest
+	if err := json.NewDecoder(rec.Body).Decode(&manifest); err != nil || rec.Code != http.StatusOK || manifest.Todos != 1 {
+		t.Fatalf("POST /admin/restore = %d %+v, %v", rec.Code, manifest, err)
+	}
+	if all := restored.All(); len(all) != 1 || all[0].ID != todos.All()[0].ID {
+		t.Errorf("restored todos = %v, want the cat", all)
+	}
+
+	// without a token the endpoints are off
+	off := chi.NewRouter()
+	Mount(off, NewHandler(NewService(todos, domain.NewUsers(), domain.NewViews()), ""))
+	if rec = do(off, http.MethodGet, "/admin/backup", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /admin/backup without an admin token = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
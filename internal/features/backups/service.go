@@ -0,0 +1,44 @@
+package backups
+
+import (
+	"context"
+	"time"
+
+	"github.com/stackus/todos/internal/backup"
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// Backup takes a backup of the todos, users and saved views as they are now
+		Backup(ctx context.Context) (*backup.Backup, error)
+		// Restore saves what is in the backup, replacing the todos, users and views with the same IDs
+		Restore(ctx context.Context, b *backup.Backup) error
+	}
+
+	service struct {
+		todos domain.TodoRepository
+		users domain.UserRepository
+		views domain.ViewRepository
+		now   func() time.Time
+	}
+)
+
+func NewService(todos domain.TodoRepository, users domain.UserRepository, views domain.ViewRepository) Service {
+	return &service{
+		todos: todos,
+		users: users,
+		views: views,
+		now:   time.Now,
+	}
+}
+
+func (s service) Backup(_ context.Context) (*backup.Backup, error) {
+	return backup.Take(s.todos, s.users, s.views, s.now())
+}
+
+func (s service) Restore(_ context.Context, b *backup.Backup) error {
+	b.Restore(s.todos, s.users, s.views)
+
+	return nil
+}
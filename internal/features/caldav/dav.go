@@ -0,0 +1,236 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var (
+	propResourceType           = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName            = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal   = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL           = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propCurrentUserPrivileges  = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReportSet     = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propSyncToken              = xml.Name{Space: nsDAV, Local: "sync-token"}
+	propGetETag                = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType         = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propGetContentLength       = xml.Name{Space: nsDAV, Local: "getcontentlength"}
+	propGetLastModified        = xml.Name{Space: nsDAV, Local: "getlastmodified"}
+	propCalendarHomeSet        = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propSupportedComponentSet  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData           = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propCalendarServerGetCTag  = xml.Name{Space: nsCS, Local: "getctag"}
+	reportCalendarQuery        = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportCalendarMultiget     = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+	reportSyncCollection       = xml.Name{Space: nsDAV, Local: "sync-collection"}
+	conditionValidCalendarData = xml.Name{Space: nsCalDAV, Local: "valid-calendar-data"}
+	conditionValidSyncToken    = xml.Name{Space: nsDAV, Local: "valid-sync-token"}
+)
+
+type (
+	// propfindRequest is the body of a PROPFIND request; an empty body means allprop
+	propfindRequest struct {
+		XMLName  xml.Name   `xml:"DAV: propfind"`
+		AllProp  *struct{}  `xml:"DAV: allprop"`
+		PropName *struct{}  `xml:"DAV: propname"`
+		Prop     *propNames `xml:"DAV: prop"`
+	}
+
+	calendarQueryRequest struct {
+		Prop   *propNames  `xml:"DAV: prop"`
+		Filter *compFilter `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+	}
+
+	calendarMultigetRequest struct {
+		Prop  *propNames `xml:"DAV: prop"`
+		Hrefs []string   `xml:"DAV: href"`
+	}
+
+	syncCollectionRequest struct {
+		SyncToken string     `xml:"DAV: sync-token"`
+		Prop      *propNames `xml:"DAV: prop"`
+	}
+
+	compFilter struct {
+		Name         string       `xml:"name,attr"`
+		IsNotDefined *struct{}    `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+		TimeRange    *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+		CompFilters  []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+		PropFilters  []propFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+	}
+
+	propFilter struct {
+		Name         string     `xml:"name,attr"`
+		IsNotDefined *struct{}  `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+		TimeRange    *timeRange `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+		TextMatch    *textMatch `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+	}
+
+	timeRange struct {
+		Start string `xml:"start,attr"`
+		End   string `xml:"end,attr"`
+	}
+
+	textMatch struct {
+		Value           string `xml:",chardata"`
+		NegateCondition string `xml:"negate-condition,attr"`
+	}
+
+	// propNames collects the names of the requested properties
+	propNames struct {
+		Names []xml.Name
+	}
+
+	multistatus struct {
+		XMLName   xml.Name   `xml:"DAV: multistatus"`
+		Responses []response `xml:"DAV: response"`
+		SyncToken string     `xml:"DAV: sync-token,omitempty"`
+	}
+
+	response struct {
+		Href      string     `xml:"DAV: href"`
+		Status    string     `xml:"DAV: status,omitempty"`
+		Propstats []propstat `xml:"DAV: propstat"`
+	}
+
+	propstat struct {
+		Prop   prop   `xml:"DAV: prop"`
+		Status string `xml:"DAV: status"`
+	}
+
+	prop struct {
+		Values []property
+	}
+
+	// property is a single DAV property; Inner holds already escaped XML
+	property struct {
+		XMLName xml.Name
+		Inner   string `xml:",innerxml"`
+	}
+
+	davError struct {
+		XMLName   xml.Name `xml:"DAV: error"`
+		Condition property
+	}
+)
+
+func (p *propNames) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			p.Names = append(p.Names, t.Name)
+			if err = d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// rootElement returns the first start element of an XML document
+func rootElement(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+func textProperty(name xml.Name, value string) property {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return property{XMLName: name, Inner: buf.String()}
+}
+
+func hrefProperty(name xml.Name, href string) property {
+	p := textProperty(name, href)
+	p.Inner = `<href xmlns="DAV:">` + p.Inner + `</href>`
+	return p
+}
+
+func timeProperty(name xml.Name, t time.Time) property {
+	return textProperty(name, t.UTC().Format(time.RFC1123))
+}
+
+func element(space, local string) string {
+	return `<` + local + ` xmlns="` + space + `"/>`
+}
+
+func privilegeSet(privileges ...string) string {
+	var sb strings.Builder
+	for _, privilege := range privileges {
+		sb.WriteString(`<privilege xmlns="DAV:">` + element(nsDAV, privilege) + `</privilege>`)
+	}
+	return sb.String()
+}
+
+func reportSet(reports ...xml.Name) string {
+	var sb strings.Builder
+	for _, report := range reports {
+		sb.WriteString(`<supported-report xmlns="DAV:"><report xmlns="DAV:">` + element(report.Space, report.Local) + `</report></supported-report>`)
+	}
+	return sb.String()
+}
+
+// propResponse answers a property request from the properties a resource has
+func propResponse(href string, available []property, requested []xml.Name) response {
+	res := response{Href: href}
+	if requested == nil {
+		res.Propstats = []propstat{{Prop: prop{Values: available}, Status: statusLine(http.StatusOK)}}
+		return res
+	}
+
+	var found, missing []property
+	for _, name := range requested {
+		var ok bool
+		for _, p := range available {
+			if p.XMLName == name {
+				found = append(found, p)
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			missing = append(missing, property{XMLName: name})
+		}
+	}
+	if len(found) != 0 {
+		res.Propstats = append(res.Propstats, propstat{Prop: prop{Values: found}, Status: statusLine(http.StatusOK)})
+	}
+	if len(missing) != 0 {
+		res.Propstats = append(res.Propstats, propstat{Prop: prop{Values: missing}, Status: statusLine(http.StatusNotFound)})
+	}
+	return res
+}
+
+func statusLine(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
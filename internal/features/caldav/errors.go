@@ -0,0 +1,10 @@
+package caldav
+
+import "errors"
+
+var (
+	ErrInvalidCalendarData = errors.New("invalid calendar data")
+	ErrInvalidSyncToken    = errors.New("invalid sync token")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrTodoArchived        = errors.New("todo is archived")
+)
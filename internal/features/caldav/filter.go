@@ -0,0 +1,126 @@
+package caldav
+
+import (
+	"strings"
+	"time"
+)
+
+type icalProperty struct {
+	params map[string]string
+	value  string
+}
+
+// components indexes the properties of each component in an iCalendar object by component name
+func components(data []byte) map[string]map[string][]icalProperty {
+	comps := make(map[string]map[string][]icalProperty)
+	var stack []string
+	for _, l := range unfoldLines(data) {
+		name, params, value, ok := parseLine(l)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(value))
+			if _, ok := comps[strings.ToUpper(value)]; !ok {
+				comps[strings.ToUpper(value)] = make(map[string][]icalProperty)
+			}
+			continue
+		case "END":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		if len(stack) > 0 {
+			comp := comps[stack[len(stack)-1]]
+			comp[name] = append(comp[name], icalProperty{params: params, value: value})
+		}
+	}
+	return comps
+}
+
+// matchFilter evaluates a CALDAV:filter against a calendar resource (RFC 4791 section 9.7)
+func matchFilter(filter *compFilter, resource Resource) bool {
+	if filter == nil {
+		return true
+	}
+	return matchComp(*filter, components(resource.Data))
+}
+
+func matchComp(filter compFilter, comps map[string]map[string][]icalProperty) bool {
+	props, defined := comps[strings.ToUpper(filter.Name)]
+	if filter.IsNotDefined != nil {
+		return !defined
+	}
+	if !defined {
+		return false
+	}
+	if filter.TimeRange != nil && !matchCompTimeRange(*filter.TimeRange, props) {
+		return false
+	}
+	for _, pf := range filter.PropFilters {
+		if !matchProp(pf, props) {
+			return false
+		}
+	}
+	for _, cf := range filter.CompFilters {
+		if !matchComp(cf, comps) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchProp(filter propFilter, props map[string][]icalProperty) bool {
+	values, defined := props[strings.ToUpper(filter.Name)]
+	if filter.IsNotDefined != nil {
+		return !defined
+	}
+	if !defined {
+		return false
+	}
+	for _, v := range values {
+		if filter.TimeRange != nil {
+			t, err := parseDateTime(v.value, v.params)
+			if err != nil || !inTimeRange(*filter.TimeRange, t) {
+				continue
+			}
+		}
+		if filter.TextMatch != nil {
+			contains := strings.Contains(strings.ToLower(unescapeText(v.value)), strings.ToLower(filter.TextMatch.Value))
+			if contains == (filter.TextMatch.NegateCondition == "yes") {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// matchCompTimeRange uses the due date of a todo; todos without one overlap every range
+func matchCompTimeRange(tr timeRange, props map[string][]icalProperty) bool {
+	due, ok := props["DUE"]
+	if !ok || len(due) == 0 {
+		return true
+	}
+	t, err := parseDateTime(due[0].value, due[0].params)
+	if err != nil {
+		return false
+	}
+	return inTimeRange(tr, t)
+}
+
+func inTimeRange(tr timeRange, t time.Time) bool {
+	if tr.Start != "" {
+		if start, err := time.Parse(icalDateTime, tr.Start); err == nil && t.Before(start) {
+			return false
+		}
+	}
+	if tr.End != "" {
+		if end, err := time.Parse(icalDateTime, tr.End); err == nil && !t.Before(end) {
+			return false
+		}
+	}
+	return true
+}
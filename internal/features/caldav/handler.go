@@ -0,0 +1,415 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+const (
+	homePath       = "/caldav/"
+	collectionPath = "/caldav/todos/"
+	resourceExt    = ".ics"
+	contentType    = "text/calendar; charset=utf-8; component=VTODO"
+	// maxBodySize limits the XML and iCalendar documents clients may send
+	maxBodySize = 1 << 20
+)
+
+func init() {
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("REPORT")
+}
+
+type (
+	Handler interface {
+		// WellKnown : ANY /.well-known/caldav
+		WellKnown(w http.ResponseWriter, r *http.Request)
+		// Options : OPTIONS /caldav/*
+		Options(w http.ResponseWriter, r *http.Request)
+		// PropfindHome : PROPFIND /caldav/
+		PropfindHome(w http.ResponseWriter, r *http.Request)
+		// PropfindCollection : PROPFIND /caldav/todos/
+		PropfindCollection(w http.ResponseWriter, r *http.Request)
+		// PropfindResource : PROPFIND /caldav/todos/{todoId}.ics
+		PropfindResource(w http.ResponseWriter, r *http.Request)
+		// Report : REPORT /caldav/todos/
+		Report(w http.ResponseWriter, r *http.Request)
+		// Get : GET /caldav/todos/{todoId}.ics
+		Get(w http.ResponseWriter, r *http.Request)
+		// Put : PUT /caldav/todos/{todoId}.ics
+		Put(w http.ResponseWriter, r *http.Request)
+		// Delete : DELETE /caldav/todos/{todoId}.ics
+		Delete(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.HandleFunc("/.well-known/caldav", h.WellKnown)
+	r.Route("/caldav", func(r chi.Router) {
+		r.Options("/*", h.Options)
+		r.Method("PROPFIND", "/", http.HandlerFunc(h.PropfindHome))
+		r.Route("/todos", func(r chi.Router) {
+			r.Method("PROPFIND", "/", http.HandlerFunc(h.PropfindCollection))
+			r.Method("REPORT", "/", http.HandlerFunc(h.Report))
+			r.Route("/{todoId}"+resourceExt, func(r chi.Router) {
+				r.Method("PROPFIND", "/", http.HandlerFunc(h.PropfindResource))
+				r.Get("/", h.Get)
+				r.Put("/", h.Put)
+				r.Delete("/", h.Delete)
+			})
+		})
+	})
+}
+
+func (h handler) WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, homePath, http.StatusMovedPermanently)
+}
+
+func (h handler) Options(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h handler) PropfindHome(w http.ResponseWriter, r *http.Request) {
+	requested, err := parsePropfind(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ms := multistatus{Responses: []response{propResponse(homePath, homeProperties(), requested)}}
+	if r.Header.Get("Depth") == "1" {
+		_, token, err := h.service.Collection(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ms.Responses = append(ms.Responses, propResponse(collectionPath, collectionProperties(token), requested))
+	}
+
+	writeMultistatus(w, ms)
+}
+
+func (h handler) PropfindCollection(w http.ResponseWriter, r *http.Request) {
+	requested, err := parsePropfind(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resources, token, err := h.service.Collection(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ms := multistatus{Responses: []response{propResponse(collectionPath, collectionProperties(token), requested)}}
+	if r.Header.Get("Depth") == "1" {
+		for _, resource := range resources {
+			ms.Responses = append(ms.Responses, resourceResponse(resource, requested))
+		}
+	}
+
+	writeMultistatus(w, ms)
+}
+
+func (h handler) PropfindResource(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	requested, err := parsePropfind(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resource, err := h.service.Resource(r.Context(), todoID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeMultistatus(w, multistatus{Responses: []response{resourceResponse(resource, requested)}})
+}
+
+func (h handler) Report(w http.ResponseWriter, r *http.Request) {
+	d := xml.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	root, err := rootElement(d)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ms multistatus
+	switch root.Name {
+	case reportCalendarQuery:
+		var req calendarQueryRequest
+		if err = d.DecodeElement(&req, &root); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var resources []Resource
+		if resources, _, err = h.service.Collection(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, resource := range resources {
+			if matchFilter(req.Filter, resource) {
+				ms.Responses = append(ms.Responses, resourceResponse(resource, req.Prop.names()))
+			}
+		}
+	case reportCalendarMultiget:
+		var req calendarMultigetRequest
+		if err = d.DecodeElement(&req, &root); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, href := range req.Hrefs {
+			todoID, ok := parseResourceHref(href)
+			if !ok {
+				ms.Responses = append(ms.Responses, response{Href: href, Status: statusLine(http.StatusNotFound)})
+				continue
+			}
+			resource, err := h.service.Resource(r.Context(), todoID)
+			switch {
+			case errors.Is(err, todos.ErrTodoNotFound):
+				ms.Responses = append(ms.Responses, response{Href: href, Status: statusLine(http.StatusNotFound)})
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			default:
+				ms.Responses = append(ms.Responses, resourceResponse(resource, req.Prop.names()))
+			}
+		}
+	case reportSyncCollection:
+		var req syncCollectionRequest
+		if err = d.DecodeElement(&req, &root); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var changed []Resource
+		var removed []uuid.UUID
+		if req.SyncToken == "" {
+			changed, ms.SyncToken, err = h.service.Collection(r.Context())
+		} else {
+			changed, removed, ms.SyncToken, err = h.service.Changes(r.Context(), req.SyncToken)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, resource := range changed {
+			ms.Responses = append(ms.Responses, resourceResponse(resource, req.Prop.names()))
+		}
+		for _, id := range removed {
+			ms.Responses = append(ms.Responses, response{Href: resourceHref(id), Status: statusLine(http.StatusNotFound)})
+		}
+	default:
+		http.Error(w, "unsupported report "+root.Name.Local, http.StatusForbidden)
+		return
+	}
+
+	writeMultistatus(w, ms)
+}
+
+func (h handler) Get(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	resource, err := h.service.Resource(r.Context(), todoID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", resource.ETag)
+	if r.Header.Get("If-None-Match") == resource.ETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(resource.Data)))
+	_, _ = w.Write(resource.Data)
+}
+
+func (h handler) Put(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, "resource names must be UUIDs", http.StatusForbidden)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resource, created, err := h.service.Put(r.Context(), todoID, data, preconditions(r))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", resource.ETag)
+	switch created {
+	case true:
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), todoID, preconditions(r)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func homeProperties() []property {
+	return []property{
+		{XMLName: propResourceType, Inner: element(nsDAV, "collection") + element(nsDAV, "principal")},
+		textProperty(propDisplayName, "Todos"),
+		hrefProperty(propCurrentUserPrincipal, homePath),
+		hrefProperty(propPrincipalURL, homePath),
+		hrefProperty(propCalendarHomeSet, homePath),
+	}
+}
+
+func collectionProperties(token string) []property {
+	return []property{
+		{XMLName: propResourceType, Inner: element(nsDAV, "collection") + element(nsCalDAV, "calendar")},
+		textProperty(propDisplayName, "Todos"),
+		hrefProperty(propCurrentUserPrincipal, homePath),
+		{XMLName: propSupportedComponentSet, Inner: `<comp xmlns="` + nsCalDAV + `" name="VTODO"/>`},
+		{XMLName: propSupportedReportSet, Inner: reportSet(reportCalendarQuery, reportCalendarMultiget, reportSyncCollection)},
+		{XMLName: propCurrentUserPrivileges, Inner: privilegeSet("read", "write", "write-content", "bind", "unbind")},
+		textProperty(propSyncToken, token),
+		textProperty(propCalendarServerGetCTag, token),
+	}
+}
+
+func resourceResponse(resource Resource, requested []xml.Name) response {
+	available := []property{
+		{XMLName: propResourceType},
+		textProperty(propGetETag, resource.ETag),
+		textProperty(propGetContentType, contentType),
+		textProperty(propGetContentLength, strconv.Itoa(len(resource.Data))),
+		timeProperty(propGetLastModified, resource.Todo.UpdatedAt),
+	}
+	// calendar data is only returned when asked for by name
+	for _, name := range requested {
+		if name == propCalendarData {
+			available = append(available, textProperty(propCalendarData, string(resource.Data)))
+		}
+	}
+	return propResponse(resourceHref(resource.Todo.ID), available, requested)
+}
+
+func resourceHref(id uuid.UUID) string {
+	return collectionPath + id.String() + resourceExt
+}
+
+func parseResourceHref(href string) (uuid.UUID, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	name, ok := strings.CutPrefix(u.Path, collectionPath)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimSuffix(name, resourceExt))
+	return id, err == nil
+}
+
+func parsePropfind(r *http.Request) ([]xml.Name, error) {
+	var req propfindRequest
+	err := xml.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req)
+	switch {
+	case err == io.EOF:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return req.Prop.names(), nil
+}
+
+func (p *propNames) names() []xml.Name {
+	if p == nil {
+		return nil
+	}
+	return p.Names
+}
+
+func preconditions(r *http.Request) Preconditions {
+	return Preconditions{
+		IfMatch:     r.Header.Get("If-Match"),
+		IfNoneMatch: r.Header.Get("If-None-Match"),
+	}
+}
+
+func writeMultistatus(w http.ResponseWriter, ms multistatus) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_ = writeXML(w, ms)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var condition xml.Name
+	switch {
+	case errors.Is(err, todos.ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, ErrPreconditionFailed):
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	case errors.Is(err, ErrTodoArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, ErrInvalidCalendarData):
+		condition = conditionValidCalendarData
+	case errors.Is(err, ErrInvalidSyncToken):
+		condition = conditionValidSyncToken
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusForbidden)
+	_ = writeXML(w, davError{Condition: property{XMLName: condition}})
+}
@@ -0,0 +1,416 @@
+package caldav
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+const (
+	icalDateTime      = "20060102T150405Z"
+	icalLocalDateTime = "20060102T150405"
+	icalDate          = "20060102"
+	icalLineLength    = 75
+)
+
+// VTodo is the iCalendar (RFC 5545) view of a todo
+type VTodo struct {
+	UID         uuid.UUID
+	Summary     string
+	Completed   bool
+	DueDate     *time.Time
+	Priority    domain.Priority
+	Category    *string
+	Tags        []string
+	Recurring   *domain.RecurringConfig
+	ParentID    *uuid.UUID
+	Created     time.Time
+	LastUpdated time.Time
+}
+
+// NewVTodo creates the iCalendar view of a todo
+func NewVTodo(todo *domain.Todo) VTodo {
+	category := todo.Category
+	return VTodo{
+		UID:         todo.ID,
+		Summary:     todo.Description,
+		Completed:   todo.Completed,
+		DueDate:     todo.DueDate,
+		Priority:    todo.Priority,
+		Category:    &category,
+		Tags:        todo.Tags,
+		Recurring:   todo.Recurring,
+		ParentID:    todo.ParentID,
+		Created:     todo.CreatedAt,
+		LastUpdated: todo.UpdatedAt,
+	}
+}
+
+// Apply copies the iCalendar values onto the todo, making the VTodo a change to the todo
+func (v VTodo) Apply(todo *domain.Todo, at time.Time) error {
+	// recurring todos record the occurrence as they are completed
+	if err := (domain.Update{Completed: v.Completed, Description: v.Summary}).Apply(todo, at); err != nil {
+		return err
+	}
+	todo.DueDate = v.DueDate
+	todo.Priority = v.Priority
+	// clients are free to drop our extension property; keep what we had when they do
+	if v.Category != nil {
+		todo.Category = *v.Category
+	}
+	todo.Tags = v.Tags
+	if todo.Tags == nil {
+		todo.Tags = make([]string, 0)
+	}
+	if v.Recurring != nil && todo.Recurring != nil {
+		v.Recurring.LastOccurrence = todo.Recurring.LastOccurrence
+	}
+	todo.Recurring = v.Recurring
+	return nil
+}
+
+// MarshalICal encodes the todo as a VCALENDAR containing a single VTODO
+func (v VTodo) MarshalICal() []byte {
+	var buf bytes.Buffer
+	w := icalWriter{buf: &buf}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//stackus//todos//EN")
+	w.line("BEGIN", "VTODO")
+	w.line("UID", v.UID.String())
+	w.line("DTSTAMP", v.LastUpdated.UTC().Format(icalDateTime))
+	if !v.Created.IsZero() {
+		w.line("CREATED", v.Created.UTC().Format(icalDateTime))
+	}
+	if !v.LastUpdated.IsZero() {
+		w.line("LAST-MODIFIED", v.LastUpdated.UTC().Format(icalDateTime))
+	}
+	w.line("SUMMARY", escapeText(v.Summary))
+	if v.Completed {
+		w.line("STATUS", "COMPLETED")
+		w.line("COMPLETED", v.LastUpdated.UTC().Format(icalDateTime))
+	} else {
+		w.line("STATUS", "NEEDS-ACTION")
+	}
+	if v.DueDate != nil {
+		w.line("DUE", v.DueDate.UTC().Format(icalDateTime))
+	}
+	w.line("PRIORITY", strconv.Itoa(encodePriority(v.Priority)))
+	if v.Category != nil && *v.Category != "" {
+		w.line("X-TODOS-CATEGORY", escapeText(*v.Category))
+	}
+	if len(v.Tags) != 0 {
+		tags := make([]string, len(v.Tags))
+		for i, tag := range v.Tags {
+			tags[i] = escapeText(tag)
+		}
+		w.line("CATEGORIES", strings.Join(tags, ","))
+	}
+	if rule := encodeRecurrence(v.Recurring); rule != "" {
+		w.line("RRULE", rule)
+	}
+	if v.ParentID != nil {
+		w.line("RELATED-TO;RELTYPE=PARENT", v.ParentID.String())
+	}
+	w.line("END", "VTODO")
+	w.line("END", "VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// UnmarshalICal decodes the first VTODO found in an iCalendar object
+func UnmarshalICal(data []byte) (VTodo, error) {
+	var v VTodo
+	var inTodo, found bool
+	var err error
+
+	v.Priority = domain.PriorityMedium
+	v.Tags = make([]string, 0)
+
+	for _, l := range unfoldLines(data) {
+		name, params, value, ok := parseLine(l)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VTODO"):
+			if found {
+				return v, fmt.Errorf("%w: more than one VTODO", ErrInvalidCalendarData)
+			}
+			inTodo = true
+			continue
+		case name == "END" && strings.EqualFold(value, "VTODO"):
+			inTodo = false
+			found = true
+			continue
+		}
+		if !inTodo {
+			continue
+		}
+		switch name {
+		case "UID":
+			if v.UID, err = uuid.Parse(value); err != nil {
+				return v, fmt.Errorf("%w: UID must be a UUID", ErrInvalidCalendarData)
+			}
+		case "SUMMARY":
+			v.Summary = unescapeText(value)
+		case "STATUS":
+			v.Completed = strings.EqualFold(value, "COMPLETED")
+		case "COMPLETED":
+			v.Completed = true
+		case "DUE":
+			var due time.Time
+			if due, err = parseDateTime(value, params); err != nil {
+				return v, err
+			}
+			v.DueDate = &due
+		case "PRIORITY":
+			var priority int
+			if priority, err = strconv.Atoi(value); err != nil {
+				return v, fmt.Errorf("%w: PRIORITY must be an integer", ErrInvalidCalendarData)
+			}
+			v.Priority = decodePriority(priority)
+		case "X-TODOS-CATEGORY":
+			category := unescapeText(value)
+			v.Category = &category
+		case "CATEGORIES":
+			for _, tag := range splitText(value) {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					v.Tags = append(v.Tags, tag)
+				}
+			}
+		case "RRULE":
+			if v.Recurring, err = decodeRecurrence(value); err != nil {
+				return v, err
+			}
+		case "RELATED-TO":
+			if reltype, ok := params["RELTYPE"]; ok && !strings.EqualFold(reltype, "PARENT") {
+				continue
+			}
+			if parentID, err := uuid.Parse(value); err == nil {
+				v.ParentID = &parentID
+			}
+		case "CREATED":
+			if v.Created, err = parseDateTime(value, params); err != nil {
+				return v, err
+			}
+		case "LAST-MODIFIED":
+			if v.LastUpdated, err = parseDateTime(value, params); err != nil {
+				return v, err
+			}
+		}
+	}
+
+	if !found {
+		return v, fmt.Errorf("%w: no VTODO component", ErrInvalidCalendarData)
+	}
+	if v.UID == uuid.Nil {
+		return v, fmt.Errorf("%w: missing UID", ErrInvalidCalendarData)
+	}
+
+	return v, nil
+}
+
+type icalWriter struct {
+	buf *bytes.Buffer
+}
+
+// line writes a content line folded at 75 octets without splitting UTF-8 sequences
+func (w icalWriter) line(name, value string) {
+	l := name + ":" + value
+	for len(l) > icalLineLength {
+		cut := icalLineLength
+		for cut > 0 && !isRuneStart(l[cut]) {
+			cut--
+		}
+		w.buf.WriteString(l[:cut])
+		w.buf.WriteString("\r\n ")
+		l = l[cut:]
+	}
+	w.buf.WriteString(l)
+	w.buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func unfoldLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for scanner.Scan() {
+		l := strings.TrimRight(scanner.Text(), "\r")
+		if len(l) > 0 && (l[0] == ' ' || l[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func parseLine(l string) (name string, params map[string]string, value string, ok bool) {
+	colon := -1
+	quoted := false
+	for i, r := range l {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(l[:colon], ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, found := strings.Cut(p, "="); found {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return strings.ToUpper(parts[0]), params, l[colon+1:], true
+}
+
+func parseDateTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tzid, ok := params["TZID"]; ok {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	var t time.Time
+	var err error
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == len(icalDate):
+		t, err = time.ParseInLocation(icalDate, value, loc)
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icalDateTime, value)
+	default:
+		t, err = time.ParseInLocation(icalLocalDateTime, value, loc)
+	}
+	if err != nil {
+		return t, fmt.Errorf("%w: invalid date %q", ErrInvalidCalendarData, value)
+	}
+
+	return t, nil
+}
+
+// encodePriority maps onto the RFC 5545 high (1), medium (5) and low (9) values
+func encodePriority(priority domain.Priority) int {
+	switch priority {
+	case domain.PriorityHigh:
+		return 1
+	case domain.PriorityLow:
+		return 9
+	default:
+		return 5
+	}
+}
+
+func decodePriority(priority int) domain.Priority {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return domain.PriorityHigh
+	case priority >= 6 && priority <= 9:
+		return domain.PriorityLow
+	default:
+		return domain.PriorityMedium
+	}
+}
+
+var recurrenceFrequencies = map[string]string{
+	"daily":   "DAILY",
+	"weekly":  "WEEKLY",
+	"monthly": "MONTHLY",
+	"yearly":  "YEARLY",
+}
+
+func encodeRecurrence(recurring *domain.RecurringConfig) string {
+	if recurring == nil {
+		return ""
+	}
+	freq, ok := recurrenceFrequencies[strings.ToLower(recurring.Frequency)]
+	if !ok {
+		return ""
+	}
+	rule := "FREQ=" + freq
+	if recurring.EndDate != nil {
+		rule += ";UNTIL=" + recurring.EndDate.UTC().Format(icalDateTime)
+	}
+	return rule
+}
+
+func decodeRecurrence(rule string) (*domain.RecurringConfig, error) {
+	recurring := &domain.RecurringConfig{LastOccurrence: time.Now()}
+	for _, part := range strings.Split(rule, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			for frequency, freq := range recurrenceFrequencies {
+				if strings.EqualFold(v, freq) {
+					recurring.Frequency = frequency
+				}
+			}
+		case "UNTIL":
+			until, err := parseDateTime(v, nil)
+			if err != nil {
+				return nil, err
+			}
+			recurring.EndDate = &until
+		}
+	}
+	if recurring.Frequency == "" {
+		return nil, fmt.Errorf("%w: unsupported RRULE %q", ErrInvalidCalendarData, rule)
+	}
+	return recurring, nil
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
+
+// splitText splits a multi-valued text property on unescaped commas
+func splitText(s string) []string {
+	var values []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			values = append(values, unescapeText(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(values, unescapeText(current.String()))
+}
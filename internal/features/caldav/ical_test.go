@@ -0,0 +1,131 @@
+package caldav
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestVTodo_MarshalICal(t *testing.T) {
+	var due = time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)
+	var until = time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	var parentID = uuid.New()
+	var category = "Cooking"
+	tests := map[string]struct {
+		v VTodo
+	}{
+		"Minimal": {
+			v: VTodo{
+				UID:      uuid.New(),
+				Summary:  "Bake a cake",
+				Priority: domain.PriorityMedium,
+				Tags:     []string{},
+			},
+		},
+		"AllFields": {
+			v: VTodo{
+				UID:       uuid.New(),
+				Summary:   "Bake a cake; with frosting, sprinkles\nand candles",
+				Completed: true,
+				DueDate:   &due,
+				Priority:  domain.PriorityHigh,
+				Category:  &category,
+				Tags:      []string{"baking", "a, b"},
+				Recurring: &domain.RecurringConfig{Frequency: "weekly", EndDate: &until},
+				ParentID:  &parentID,
+			},
+		},
+		"LongSummary": {
+			v: VTodo{
+				UID:      uuid.New(),
+				Summary:  "Ünïcödé summaries are folded at seventy five octets without breaking any of the multibyte runes apart",
+				Priority: domain.PriorityLow,
+				Tags:     []string{},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := tt.v.MarshalICal()
+
+			got, err := UnmarshalICal(data)
+			if err != nil {
+				t.Fatalf("UnmarshalICal() error = %v", err)
+			}
+			if got.UID != tt.v.UID {
+				t.Errorf("UnmarshalICal() UID = %v, want %v", got.UID, tt.v.UID)
+			}
+			if got.Summary != tt.v.Summary {
+				t.Errorf("UnmarshalICal() Summary = %q, want %q", got.Summary, tt.v.Summary)
+			}
+			if got.Completed != tt.v.Completed {
+				t.Errorf("UnmarshalICal() Completed = %v, want %v", got.Completed, tt.v.Completed)
+			}
+			if (got.DueDate == nil) != (tt.v.DueDate == nil) || (got.DueDate != nil && !got.DueDate.Equal(*tt.v.DueDate)) {
+				t.Errorf("UnmarshalICal() DueDate = %v, want %v", got.DueDate, tt.v.DueDate)
+			}
+			if got.Priority != tt.v.Priority {
+				t.Errorf("UnmarshalICal() Priority = %v, want %v", got.Priority, tt.v.Priority)
+			}
+			if !reflect.DeepEqual(got.Category, tt.v.Category) {
+				t.Errorf("UnmarshalICal() Category = %v, want %v", got.Category, tt.v.Category)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.v.Tags) {
+				t.Errorf("UnmarshalICal() Tags = %v, want %v", got.Tags, tt.v.Tags)
+			}
+			if (got.Recurring == nil) != (tt.v.Recurring == nil) || (got.Recurring != nil && got.Recurring.Frequency != tt.v.Recurring.Frequency) {
+				t.Errorf("UnmarshalICal() Recurring = %v, want %v", got.Recurring, tt.v.Recurring)
+			}
+			if !reflect.DeepEqual(got.ParentID, tt.v.ParentID) {
+				t.Errorf("UnmarshalICal() ParentID = %v, want %v", got.ParentID, tt.v.ParentID)
+			}
+		})
+	}
+}
+
+func TestUnmarshalICal(t *testing.T) {
+	var id = uuid.New()
+	tests := map[string]struct {
+		data    string
+		want    VTodo
+		wantErr error
+	}{
+		"ClientTask": {
+			data: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:" + id.String() + "\r\nSUMMARY:Feed\r\n  the cat\r\nSTATUS:COMPLETED\r\nPRIORITY:0\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			want: VTodo{
+				UID:       id,
+				Summary:   "Feed the cat",
+				Completed: true,
+				Priority:  domain.PriorityMedium,
+				Tags:      []string{},
+			},
+		},
+		"NoTodo": {
+			data:    "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:" + id.String() + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			wantErr: ErrInvalidCalendarData,
+		},
+		"NonUUIDUID": {
+			data:    "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:abc@example.com\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			wantErr: ErrInvalidCalendarData,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := UnmarshalICal([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalICal() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalICal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package caldav
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+// maxSyncStates is the number of past collection states kept to answer sync-collection reports
+const maxSyncStates = 100
+
+type (
+	Service interface {
+		// Collection returns every todo as a calendar resource along with the current sync token
+		Collection(ctx context.Context) ([]Resource, string, error)
+		// Resource returns a single todo as a calendar resource
+		Resource(ctx context.Context, id uuid.UUID) (Resource, error)
+		// Put creates or replaces a todo from iCalendar data; created reports whether it is new
+		Put(ctx context.Context, id uuid.UUID, data []byte, conditions Preconditions) (resource Resource, created bool, err error)
+		// Delete removes a todo
+		Delete(ctx context.Context, id uuid.UUID, conditions Preconditions) error
+		// Changes returns the resources changed and removed since the sync token was issued
+		Changes(ctx context.Context, token string) (changed []Resource, removed []uuid.UUID, newToken string, err error)
+	}
+
+	// Resource is a todo rendered as a calendar object resource
+	Resource struct {
+		Todo *domain.Todo
+		ETag string
+		Data []byte
+	}
+
+	// Preconditions are the If-Match and If-None-Match request headers
+	Preconditions struct {
+		IfMatch     string
+		IfNoneMatch string
+	}
+
+	service struct {
+		todos todos.Service
+
+		mu      sync.Mutex
+		epoch   int64
+		version int
+		states  map[int]map[uuid.UUID]string
+	}
+)
+
+func NewService(todos todos.Service) Service {
+	return &service{
+		todos:  todos,
+		epoch:  time.Now().Unix(),
+		states: map[int]map[uuid.UUID]string{0: {}},
+	}
+}
+
+func NewResource(todo *domain.Todo) Resource {
+	data := NewVTodo(todo).MarshalICal()
+	sum := sha1.Sum(data)
+	return Resource{
+		Todo: todo,
+		ETag: `"` + hex.EncodeToString(sum[:]) + `"`,
+		Data: data,
+	}
+}
+
+func (s *service) Collection(ctx context.Context) ([]Resource, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources, err := s.resources(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resources, s.track(resources), nil
+}
+
+func (s *service) Resource(ctx context.Context, id uuid.UUID) (Resource, error) {
+	todo, err := s.todos.Get(ctx, id)
+	if err != nil {
+		return Resource{}, err
+	}
+	if todo == nil || todo.Archived {
+		return Resource{}, todos.ErrTodoNotFound
+	}
+
+	return NewResource(todo), nil
+}
+
+func (s *service) Put(ctx context.Context, id uuid.UUID, data []byte, conditions Preconditions) (Resource, bool, error) {
+	vtodo, err := UnmarshalICal(data)
+	if err != nil {
+		return Resource{}, false, err
+	}
+	if vtodo.Summary == "" {
+		return Resource{}, false, fmt.Errorf("%w: missing SUMMARY", ErrInvalidCalendarData)
+	}
+
+	existing, err := s.Resource(ctx, id)
+	switch {
+	case err == nil:
+		if conditions.IfNoneMatch == "*" || !conditions.matches(existing.ETag) {
+			return Resource{}, false, ErrPreconditionFailed
+		}
+		// the version the ETag was taken from is checked again as the change is made, so that a change made
+		// in between fails the precondition rather than being overwritten
+		todo, err := s.todos.Change(ctx, id, domain.Expect{Version: existing.Todo.Version}, vtodo)
+		if errors.Is(err, todos.ErrVersionConflict) {
+			return Resource{}, false, ErrPreconditionFailed
+		}
+		if err != nil {
+			return Resource{}, false, err
+		}
+		return NewResource(todo), false, nil
+	case err == todos.ErrTodoNotFound:
+		if conditions.IfMatch != "" {
+			return Resource{}, false, ErrPreconditionFailed
+		}
+		// an archived todo is not in the collection, but its name is taken and a new todo must not replace it
+		archived, err := s.todos.Get(ctx, id)
+		if err != nil {
+			return Resource{}, false, err
+		}
+		if archived != nil {
+			return Resource{}, false, ErrTodoArchived
+		}
+	default:
+		return Resource{}, false, err
+	}
+
+	// the client addresses the resource by the name it chose, so the todo takes that identity
+	todo := domain.NewTodo(vtodo.Summary)
+	todo.ID = id
+	if err = vtodo.Apply(todo, todo.CreatedAt); err != nil {
+		return Resource{}, false, err
+	}
+	if vtodo.ParentID != nil {
+		parent, err := s.todos.Get(ctx, *vtodo.ParentID)
+		if err != nil {
+			return Resource{}, false, err
+		}
+		if parent != nil {
+			todo.ParentID = &parent.ID
+		}
+	}
+	if todo, err = s.todos.Save(ctx, todo); err != nil {
+		return Resource{}, false, err
+	}
+
+	return NewResource(todo), true, nil
+}
+
+func (s *service) Delete(ctx context.Context, id uuid.UUID, conditions Preconditions) error {
+	existing, err := s.Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !conditions.matches(existing.ETag) {
+		return ErrPreconditionFailed
+	}
+
+	return s.todos.Remove(ctx, id)
+}
+
+func (s *service) Changes(ctx context.Context, token string) ([]Resource, []uuid.UUID, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var epoch int64
+	var version int
+	if _, err := fmt.Sscanf(token, syncTokenPrefix+"%d-%d", &epoch, &version); err != nil || epoch != s.epoch {
+		return nil, nil, "", ErrInvalidSyncToken
+	}
+	since, ok := s.states[version]
+	if !ok {
+		return nil, nil, "", ErrInvalidSyncToken
+	}
+
+	resources, err := s.resources(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	changed := make([]Resource, 0)
+	current := make(map[uuid.UUID]struct{}, len(resources))
+	for _, resource := range resources {
+		current[resource.Todo.ID] = struct{}{}
+		if etag, ok := since[resource.Todo.ID]; !ok || etag != resource.ETag {
+			changed = append(changed, resource)
+		}
+	}
+	removed := make([]uuid.UUID, 0)
+	for id := range since {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	return changed, removed, s.track(resources), nil
+}
+
+// resources returns every todo that is not archived as a calendar resource, snoozed ones included, as a
+// calendar client keeps its own copy of each todo and would drop one that left the collection
+func (s *service) resources(ctx context.Context) ([]Resource, error) {
+	list, err := s.todos.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0, len(list))
+	for _, todo := range list {
+		if todo.Archived {
+			continue
+		}
+		resources = append(resources, NewResource(todo))
+	}
+
+	return resources, nil
+}
+
+const syncTokenPrefix = "urn:x-todos:sync:"
+
+// track records the collection state, bumping the version when it differs from the last one seen
+func (s *service) track(resources []Resource) string {
+	state := make(map[uuid.UUID]string, len(resources))
+	for _, resource := range resources {
+		state[resource.Todo.ID] = resource.ETag
+	}
+
+	if !sameState(s.states[s.version], state) {
+		s.version++
+		s.states[s.version] = state
+		delete(s.states, s.version-maxSyncStates)
+	}
+
+	return fmt.Sprintf("%s%d-%d", syncTokenPrefix, s.epoch, s.version)
+}
+
+func sameState(a, b map[uuid.UUID]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, etag := range a {
+		if b[id] != etag {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether an If-Match precondition, when present, is satisfied by the etag
+func (p Preconditions) matches(etag string) bool {
+	return p.IfMatch == "" || p.IfMatch == "*" || p.IfMatch == etag
+}
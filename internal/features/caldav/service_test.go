@@ -0,0 +1,232 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+func Test_service_Changes(t *testing.T) {
+	tests := map[string]struct {
+		mutate      func(t *testing.T, s Service, list *domain.Todos)
+		wantChanged int
+		wantRemoved int
+	}{
+		"NoChanges": {
+			mutate: func(*testing.T, Service, *domain.Todos) {},
+		},
+		"Added": {
+			mutate: func(_ *testing.T, _ Service, list *domain.Todos) {
+				list.Add("third")
+			},
+			wantChanged: 1,
+		},
+		"Updated": {
+			mutate: func(_ *testing.T, _ Service, list *domain.Todos) {
+				todo := list.All()[0]
+				list.Update(todo.ID, true, todo.Description)
+			},
+			wantChanged: 1,
+		},
+		"RemovedOutsideCalDAV": {
+			mutate: func(_ *testing.T, _ Service, list *domain.Todos) {
+				list.Remove(list.All()[0].ID)
+			},
+			wantRemoved: 1,
+		},
+		"PutAndDelete": {
+			mutate: func(t *testing.T, s Service, list *domain.Todos) {
+				v := VTodo{UID: uuid.New(), Summary: "from phone", Tags: []string{}}
+				if _, _, err := s.Put(context.Background(), v.UID, v.MarshalICal(), Preconditions{}); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+				if err := s.Delete(context.Background(), list.All()[0].ID, Preconditions{}); err != nil {
+					t.Fatalf("Delete() error = %v", err)
+				}
+			},
+			wantChanged: 1,
+			wantRemoved: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			list := domain.NewTodos()
+			list.Add("first")
+			list.Add("second")
+			s := NewService(todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore()))
+			_, token, err := s.Collection(context.Background())
+			if err != nil {
+				t.Fatalf("Collection() error = %v", err)
+			}
+
+			tt.mutate(t, s, list)
+
+			changed, removed, newToken, err := s.Changes(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Changes() error = %v", err)
+			}
+			if len(changed) != tt.wantChanged {
+				t.Errorf("Changes() changed = %v, want %v", len(changed), tt.wantChanged)
+			}
+			if len(removed) != tt.wantRemoved {
+				t.Errorf("Changes() removed = %v, want %v", len(removed), tt.wantRemoved)
+			}
+			if (newToken == token) != (tt.wantChanged == 0 && tt.wantRemoved == 0) {
+				t.Errorf("Changes() token = %v, previous %v", newToken, token)
+			}
+		})
+	}
+}
+
+func Test_service_Put(t *testing.T) {
+	list := domain.NewTodos()
+	existing := list.Add("existing")
+	archived := list.Add("archived")
+	if _, err := list.Change(archived.ID, domain.SetArchived{Archived: true}); err != nil {
+		t.Fatalf("Change() error = %v", err)
+	}
+	s := NewService(todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore()))
+	resource, err := s.Resource(context.Background(), existing.ID)
+	if err != nil {
+		t.Fatalf("Resource() error = %v", err)
+	}
+	completed := VTodo{UID: existing.ID, Summary: "existing", Completed: true, Tags: []string{}}
+
+	tests := map[string]struct {
+		id          uuid.UUID
+		conditions  Preconditions
+		wantCreated bool
+		wantErr     error
+	}{
+		"CreateOnlyConflict": {
+			id:         existing.ID,
+			conditions: Preconditions{IfNoneMatch: "*"},
+			wantErr:    ErrPreconditionFailed,
+		},
+		"StaleETag": {
+			id:         existing.ID,
+			conditions: Preconditions{IfMatch: `"stale"`},
+			wantErr:    ErrPreconditionFailed,
+		},
+		"MatchingETag": {
+			id:         existing.ID,
+			conditions: Preconditions{IfMatch: resource.ETag},
+		},
+		"MissingWithIfMatch": {
+			id:         uuid.New(),
+			conditions: Preconditions{IfMatch: "*"},
+			wantErr:    ErrPreconditionFailed,
+		},
+		"Create": {
+			id:          uuid.New(),
+			wantCreated: true,
+		},
+		"Archived": {
+			id:      archived.ID,
+			wantErr: ErrTodoArchived,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, created, err := s.Put(context.Background(), tt.id, completed.MarshalICal(), tt.conditions)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Put() error = %v, want %v", err, tt.wantErr)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("Put() created = %v, want %v", created, tt.wantCreated)
+			}
+			if err == nil && !list.Get(tt.id).Completed {
+				t.Errorf("Put() did not complete todo %v", tt.id)
+			}
+		})
+	}
+}
+
+// changing wraps the todos service and changes a todo after it is read, as another request could
+type changing struct {
+	todos.Service
+	list *domain.Todos
+}
+
+func (c changing) Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error) {
+	todo, err := c.Service.Get(ctx, id)
+	if todo != nil {
+		c.list.Change(id, domain.Rename{Description: "renamed elsewhere"})
+	}
+	return todo, err
+}
+
+func Test_service_Put_changedAfterMatch(t *testing.T) {
+	list := domain.NewTodos()
+	existing := list.Add("existing")
+	s := NewService(changing{
+		Service: todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore()),
+		list:    list,
+	})
+
+	// the ETag matched the todo as it was read, but it was changed before the write
+	completed := VTodo{UID: existing.ID, Summary: "existing", Completed: true, Tags: []string{}}
+	if _, _, err := s.Put(context.Background(), existing.ID, completed.MarshalICal(), Preconditions{IfMatch: "*"}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Put() error = %v, want %v", err, ErrPreconditionFailed)
+	}
+	if todo := list.Get(existing.ID); todo.Completed || todo.Description != "renamed elsewhere" {
+		t.Errorf("Get() = %+v, want the other change kept", todo)
+	}
+}
+
+func Test_service_Put_subtask(t *testing.T) {
+	list := domain.NewTodos()
+	trip := list.Add("Plan vacation")
+	s := NewService(todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore()))
+
+	// the client names the resource and its parent; the todo keeps that name and goes under the parent
+	id := uuid.New()
+	flights := VTodo{UID: id, Summary: "Book flights", ParentID: &trip.ID, Tags: []string{}}
+	if _, created, err := s.Put(context.Background(), id, flights.MarshalICal(), Preconditions{}); err != nil || !created {
+		t.Fatalf("Put() created = %v, error = %v", created, err)
+	}
+
+	subtask := list.Get(id)
+	if subtask == nil || subtask.ParentID == nil || *subtask.ParentID != trip.ID {
+		t.Fatalf("Get() = %+v, want the subtask under the trip", subtask)
+	}
+	if parent := list.Get(trip.ID); len(parent.Subtasks) != 1 || parent.Subtasks[0].ID != id {
+		t.Errorf("Subtasks = %v, want the subtask with the client's ID", parent.Subtasks)
+	}
+}
+
+func Test_service_Collection_snoozed(t *testing.T) {
+	list := domain.NewTodos()
+	first := list.Add("first")
+	snoozed := domain.NewTodo("snoozed")
+	snoozed.Snooze(time.Now().Add(time.Hour), uuid.New(), false)
+	list.Save(snoozed)
+	s := NewService(todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore()))
+	ctx := context.Background()
+
+	// a snoozed todo is hidden from the web pages but stays on the calendar
+	resources, token, err := s.Collection(ctx)
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	if len(resources) != 2 || resources[1].Todo.ID != snoozed.ID {
+		t.Errorf("Collection() = %d resources, want both todos", len(resources))
+	}
+	if _, err = s.Resource(ctx, snoozed.ID); err != nil {
+		t.Errorf("Resource() error = %v, want the snoozed todo", err)
+	}
+
+	// nor is a todo snoozed since the last sync removed from the calendar
+	if _, err = list.Change(first.ID, domain.SetSnooze{Snooze: domain.Snooze{Until: time.Now().Add(time.Hour)}}); err != nil {
+		t.Fatalf("Change() error = %v", err)
+	}
+	if _, removed, _, err := s.Changes(ctx, token); err != nil || len(removed) != 0 {
+		t.Errorf("Changes() removed = %v, %v, want nothing removed", removed, err)
+	}
+}
@@ -0,0 +1,10 @@
+package focus
+
+import "errors"
+
+var (
+	ErrTodoNotFound      = errors.New("todo not found")
+	ErrNoSession         = errors.New("no focus session is running")
+	ErrInvalidSettings   = errors.New("pomodoros last 1 to 180 minutes, breaks 1 to 60 minutes, and a long break comes after 1 to 12 pomodoros")
+	ErrAnonymousSessions = errors.New("focus sessions need a user ID")
+)
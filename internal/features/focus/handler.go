@@ -0,0 +1,227 @@
+package focus
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+type (
+	Handler interface {
+		// Focus : GET /focus
+		Focus(w http.ResponseWriter, r *http.Request)
+		// Panel : GET /focus/panel
+		Panel(w http.ResponseWriter, r *http.Request)
+		// Start : POST /focus/start
+		Start(w http.ResponseWriter, r *http.Request)
+		// StartPhase : POST /focus/phase
+		StartPhase(w http.ResponseWriter, r *http.Request)
+		// Skip : POST /focus/skip
+		Skip(w http.ResponseWriter, r *http.Request)
+		// Stop : POST /focus/stop
+		Stop(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+const dateLayout = "2006-01-02"
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/focus", func(r chi.Router) {
+		r.Get("/", h.Focus)
+		r.Get("/panel", h.Panel)
+		r.Post("/start", h.Start)
+		r.Post("/phase", h.StartPhase)
+		r.Post("/skip", h.Skip)
+		r.Post("/stop", h.Stop)
+	})
+}
+
+func (h handler) Focus(w http.ResponseWriter, r *http.Request) {
+	view, err := h.view(r)
+	if err != nil {
+		focusError(w, err)
+		return
+	}
+
+	if err = pages.FocusPage(view).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) Panel(w http.ResponseWriter, r *http.Request) {
+	h.render(w, r)
+}
+
+func (h handler) Start(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	todoID, err := uuid.Parse(r.Form.Get("todo_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	settings, err := formSettings(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.Start(r.Context(), identity.UserID(r.Context()), todoID, settings); err != nil {
+		focusError(w, err)
+		return
+	}
+
+	h.render(w, r)
+}
+
+func (h handler) StartPhase(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.StartPhase(r.Context(), identity.UserID(r.Context())); err != nil {
+		focusError(w, err)
+		return
+	}
+
+	h.render(w, r)
+}
+
+func (h handler) Skip(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Skip(r.Context(), identity.UserID(r.Context())); err != nil {
+		focusError(w, err)
+		return
+	}
+
+	h.render(w, r)
+}
+
+func (h handler) Stop(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Stop(r.Context(), identity.UserID(r.Context())); err != nil {
+		focusError(w, err)
+		return
+	}
+
+	h.render(w, r)
+}
+
+// render responds with the focus panel, or sends the browser back to the focus page
+func (h handler) render(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && !isHTMX(r) {
+		http.Redirect(w, r, "/focus", http.StatusFound)
+		return
+	}
+
+	view, err := h.view(r)
+	if err != nil {
+		focusError(w, err)
+		return
+	}
+	if err = partials.Focus(view).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// view gathers what the focus page shows for the user making the request
+func (h handler) view(r *http.Request) (partials.FocusView, error) {
+	var userID = identity.UserID(r.Context())
+	var view = partials.FocusView{
+		Selected: r.URL.Query().Get("todo"),
+		Settings: domain.DefaultFocusSettings,
+		Now:      time.Now(),
+	}
+
+	var err error
+	if view.Session, view.Todo, err = h.service.Session(r.Context(), userID); err != nil {
+		return view, err
+	}
+	if view.Session != nil {
+		view.Settings = view.Session.Settings
+	}
+	if view.Todos, err = h.service.Todos(r.Context()); err != nil {
+		return view, err
+	}
+
+	day := view.Now
+	if v := r.URL.Query().Get("date"); v != "" {
+		if day, err = time.ParseInLocation(dateLayout, v, time.Local); err != nil {
+			return view, err
+		}
+	}
+	summary, err := h.service.Summary(r.Context(), userID, day)
+	if err != nil {
+		return view, err
+	}
+	view.Day, view.Pomodoros, view.Focused = summary.Day, summary.Pomodoros, summary.Focused
+	for _, line := range summary.ByTodo {
+		view.Summary = append(view.Summary, partials.FocusSummaryLine{Todo: line.Todo, Pomodoros: line.Pomodoros, Focused: line.Focused})
+	}
+	return view, nil
+}
+
+// formSettings reads the lengths of the phases in minutes
+func formSettings(r *http.Request) (domain.FocusSettings, error) {
+	var settings domain.FocusSettings
+	minutes := map[string]*time.Duration{
+		"work":        &settings.Work,
+		"short_break": &settings.ShortBreak,
+		"long_break":  &settings.LongBreak,
+	}
+	for field, length := range minutes {
+		n, err := strconv.Atoi(r.Form.Get(field))
+		if err != nil {
+			return settings, ErrInvalidSettings
+		}
+		*length = time.Duration(n) * time.Minute
+	}
+
+	var err error
+	if settings.LongBreakEvery, err = strconv.Atoi(r.Form.Get("long_break_every")); err != nil {
+		return settings, ErrInvalidSettings
+	}
+	return settings, nil
+}
+
+func focusError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNoSession):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrAnonymousSessions):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrInvalidSettings):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		var parseError *time.ParseError
+		if errors.As(err, &parseError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
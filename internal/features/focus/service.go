@@ -0,0 +1,198 @@
+package focus
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// Session returns the latest session of the user with its todo, ending the phase when its time is up
+		//
+		// The session is nil when the user has never focused; it may have ended, keeping the settings used last.
+		Session(ctx context.Context, userID uuid.UUID) (*domain.FocusSession, *domain.Todo, error)
+		// Start starts a session on the todo with its first pomodoro running, stopping any session already active
+		Start(ctx context.Context, userID, todoID uuid.UUID, settings domain.FocusSettings) error
+		// StartPhase starts the time of the phase the session waits on
+		StartPhase(ctx context.Context, userID uuid.UUID) error
+		// Skip moves the session on to the next phase without completing the current one
+		Skip(ctx context.Context, userID uuid.UUID) error
+		// Stop ends the session
+		Stop(ctx context.Context, userID uuid.UUID) error
+		// Summary totals the pomodoros the user completed on the day
+		Summary(ctx context.Context, userID uuid.UUID, day time.Time) (*Summary, error)
+		// Todos returns the todos that can be focused on
+		Todos(ctx context.Context) ([]*domain.Todo, error)
+	}
+
+	// Summary is the focus of a user on one day
+	Summary struct {
+		Day       time.Time
+		Pomodoros int
+		Focused   time.Duration
+		ByTodo    []SummaryLine
+	}
+
+	// SummaryLine is the focus given to one todo
+	SummaryLine struct {
+		Todo      *domain.Todo
+		Pomodoros int
+		Focused   time.Duration
+	}
+
+	service struct {
+		todos    domain.TodoRepository
+		sessions domain.FocusSessionRepository
+		now      func() time.Time
+		// mu keeps a session from being advanced twice at once, which would record its pomodoro twice
+		mu sync.Mutex
+	}
+)
+
+func NewService(todos domain.TodoRepository, sessions domain.FocusSessionRepository) Service {
+	return &service{
+		todos:    todos,
+		sessions: sessions,
+		now:      time.Now,
+	}
+}
+
+func (s *service) Session(_ context.Context, userID uuid.UUID) (*domain.FocusSession, *domain.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.advance(userID)
+	if session == nil {
+		return nil, nil, nil
+	}
+	return session, s.todos.Get(session.TodoID), nil
+}
+
+func (s *service) Start(_ context.Context, userID, todoID uuid.UUID, settings domain.FocusSettings) error {
+	if userID == uuid.Nil {
+		return ErrAnonymousSessions
+	}
+	if !validSettings(settings) {
+		return ErrInvalidSettings
+	}
+	if s.todos.Get(todoID) == nil {
+		return ErrTodoNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	// a pomodoro finished in the old session still counts
+	if previous := s.advance(userID); previous != nil && previous.Active() {
+		previous.Stop(now)
+	}
+	session := domain.NewFocusSession(userID, todoID, settings, now)
+	session.StartPhase(now)
+	s.sessions.Save(session)
+	return nil
+}
+
+func (s *service) StartPhase(_ context.Context, userID uuid.UUID) error {
+	return s.update(userID, func(session *domain.FocusSession, now time.Time) {
+		session.StartPhase(now)
+	})
+}
+
+func (s *service) Skip(_ context.Context, userID uuid.UUID) error {
+	return s.update(userID, func(session *domain.FocusSession, _ time.Time) {
+		session.Skip()
+	})
+}
+
+func (s *service) Stop(_ context.Context, userID uuid.UUID) error {
+	return s.update(userID, func(session *domain.FocusSession, now time.Time) {
+		session.Stop(now)
+	})
+}
+
+func (s *service) Summary(_ context.Context, userID uuid.UUID, day time.Time) (*Summary, error) {
+	s.mu.Lock()
+	s.advance(userID)
+	s.mu.Unlock()
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+	summary := &Summary{Day: start}
+	for _, todo := range s.todos.All() {
+		line := SummaryLine{Todo: todo}
+		for _, pomodoro := range todo.Pomodoros {
+			if pomodoro.UserID != userID || pomodoro.End.Before(start) || !pomodoro.End.Before(end) {
+				continue
+			}
+			line.Pomodoros++
+			line.Focused += pomodoro.End.Sub(pomodoro.Start)
+		}
+		if line.Pomodoros == 0 {
+			continue
+		}
+		summary.Pomodoros += line.Pomodoros
+		summary.Focused += line.Focused
+		summary.ByTodo = append(summary.ByTodo, line)
+	}
+	sort.SliceStable(summary.ByTodo, func(i, j int) bool {
+		return summary.ByTodo[i].Focused > summary.ByTodo[j].Focused
+	})
+	return summary, nil
+}
+
+func (s *service) Todos(_ context.Context) ([]*domain.Todo, error) {
+	var open []*domain.Todo
+	for _, todo := range s.todos.All() {
+		if !todo.Completed && !todo.Archived {
+			open = append(open, todo)
+		}
+	}
+	return open, nil
+}
+
+// update changes the active session of the user once it has caught up with the time
+func (s *service) update(userID uuid.UUID, change func(session *domain.FocusSession, now time.Time)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.advance(userID)
+	if session == nil || !session.Active() {
+		return ErrNoSession
+	}
+	change(session, s.now())
+	s.sessions.Save(session)
+	return nil
+}
+
+// advance ends the phase of the session of the user when its time is up, recording a finished pomodoro on the todo
+func (s *service) advance(userID uuid.UUID) *domain.FocusSession {
+	session := s.sessions.Get(userID)
+	if session == nil || !session.Active() {
+		return session
+	}
+	pomodoro, ok := session.Advance(s.now())
+	if ok {
+		// the todo may have been deleted while the user focused on it
+		_, _ = s.todos.Change(session.TodoID, domain.AddPomodoro{Pomodoro: pomodoro})
+	}
+	s.sessions.Save(session)
+	return session
+}
+
+func validSettings(settings domain.FocusSettings) bool {
+	return between(settings.Work, time.Minute, 180*time.Minute) &&
+		between(settings.ShortBreak, time.Minute, time.Hour) &&
+		between(settings.LongBreak, time.Minute, time.Hour) &&
+		settings.LongBreakEvery >= 1 && settings.LongBreakEvery <= 12
+}
+
+func between(d, min, max time.Duration) bool {
+	return d >= min && d <= max
+}
@@ -0,0 +1,83 @@
+package focus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service(t *testing.T) {
+	list := domain.NewTodos()
+	report, review := list.Add("Write the report"), list.Add("Review the report")
+	s := NewService(list, domain.NewFocusSessions()).(*service)
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+	userID := uuid.New()
+	settings := domain.FocusSettings{Work: 25 * time.Minute, ShortBreak: 5 * time.Minute, LongBreak: 15 * time.Minute, LongBreakEvery: 4}
+
+	if err := s.StartPhase(ctx, userID); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("StartPhase() error = %v, want %v", err, ErrNoSession)
+	}
+	if err := s.Start(ctx, userID, report.ID, domain.FocusSettings{Work: time.Hour}); !errors.Is(err, ErrInvalidSettings) {
+		t.Fatalf("Start() error = %v, want %v", err, ErrInvalidSettings)
+	}
+	if err := s.Start(ctx, userID, report.ID, settings); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// a refresh half way through finds the pomodoro still running
+	now = now.Add(10 * time.Minute)
+	session, todo, _ := s.Session(ctx, userID)
+	if todo == nil || todo.ID != report.ID || !session.Running || session.Remaining(now) != 15*time.Minute {
+		t.Fatalf("Session() = %+v on %v, want 15 minutes left on the report", session, todo)
+	}
+
+	// looking again long after the pomodoro ended records it once
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 2; i++ {
+		if session, _, _ = s.Session(ctx, userID); session.Running || session.Phase != domain.FocusShortBreak {
+			t.Fatalf("Session() = %+v, want a short break waiting", session)
+		}
+	}
+	if report = list.Get(report.ID); len(report.Pomodoros) != 1 {
+		t.Fatalf("Pomodoros = %v, want one", report.Pomodoros)
+	}
+
+	// switching todos stops the session on the first
+	if err := s.Skip(ctx, userID); err != nil {
+		t.Fatalf("Skip() error = %v", err)
+	}
+	if err := s.Start(ctx, userID, review.ID, settings); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	now = now.Add(25 * time.Minute)
+	if err := s.Stop(ctx, userID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if review = list.Get(review.ID); len(review.Pomodoros) != 1 {
+		t.Errorf("Pomodoros = %v, want the one finished before stopping", review.Pomodoros)
+	}
+	if err := s.Skip(ctx, userID); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Skip() error = %v, want %v", err, ErrNoSession)
+	}
+
+	summary, err := s.Summary(ctx, userID, now)
+	if err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if summary.Pomodoros != 2 || summary.Focused != 50*time.Minute || len(summary.ByTodo) != 2 {
+		t.Errorf("Summary() = %+v, want two pomodoros on two todos", summary)
+	}
+	if summary, _ = s.Summary(ctx, userID, now.AddDate(0, 0, 1)); summary.Pomodoros != 0 {
+		t.Errorf("Summary() of the next day = %+v, want nothing", summary)
+	}
+	if summary, _ = s.Summary(ctx, uuid.New(), now); summary.Pomodoros != 0 {
+		t.Errorf("Summary() of another user = %+v, want nothing", summary)
+	}
+}
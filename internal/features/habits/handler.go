@@ -0,0 +1,60 @@
+package habits
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+type (
+	Handler interface {
+		// Habits : GET /habits
+		Habits(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Get("/habits", h.Habits)
+}
+
+func (h handler) Habits(w http.ResponseWriter, r *http.Request) {
+	habits, err := h.service.Habits(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	lines := make([]partials.HabitLine, len(habits))
+	for i, habit := range habits {
+		lines[i] = partials.HabitLine{Todo: habit.Todo, Current: habit.Current, Longest: habit.Longest, Missed: habit.Missed}
+	}
+
+	if isHTMX(r) {
+		err = partials.Habits(lines).Render(r.Context(), w)
+	} else {
+		err = pages.HabitsPage(lines).Render(r.Context(), w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
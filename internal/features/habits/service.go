@@ -0,0 +1,87 @@
+package habits
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// Habits returns how well each recurring todo has been kept up, those on the longest streak first
+		Habits(ctx context.Context) ([]Habit, error)
+		// Rollover opens the completed recurring todos again once the period they were completed in has passed
+		//
+		// It returns the todos it opened.
+		Rollover(ctx context.Context) ([]*domain.Todo, error)
+	}
+
+	// Habit is how well a recurring todo has been kept up
+	Habit struct {
+		Todo    *domain.Todo
+		Current int
+		Longest int
+		// Missed is how many periods passed without the todo being completed
+		Missed int
+	}
+
+	service struct {
+		todos domain.TodoRepository
+		now   func() time.Time
+		// mu keeps a rollover from running twice at once
+		mu sync.Mutex
+	}
+)
+
+func NewService(todos domain.TodoRepository) Service {
+	return &service{
+		todos: todos,
+		now:   time.Now,
+	}
+}
+
+func (s *service) Habits(_ context.Context) ([]Habit, error) {
+	now := s.now()
+
+	var habits []Habit
+	for _, todo := range s.todos.GetRecurring() {
+		if todo.Archived {
+			continue
+		}
+		current, longest := todo.Streak(now)
+		habits = append(habits, Habit{
+			Todo:    todo,
+			Current: current,
+			Longest: longest,
+			Missed:  len(todo.MissedOccurrences(now)),
+		})
+	}
+	sort.SliceStable(habits, func(i, j int) bool {
+		if habits[i].Current != habits[j].Current {
+			return habits[i].Current > habits[j].Current
+		}
+		return habits[i].Longest > habits[j].Longest
+	})
+	return habits, nil
+}
+
+func (s *service) Rollover(_ context.Context) ([]*domain.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var reopened []*domain.Todo
+	for _, todo := range s.todos.GetRecurring() {
+		if todo.Archived {
+			continue
+		}
+		// the repository reopens the todo as it is when the change is made, which may not be as it was read
+		if todo, err := s.todos.Change(todo.ID, domain.Reopen{Now: now}); err == nil {
+			reopened = append(reopened, todo)
+		}
+	}
+	return reopened, nil
+}
@@ -0,0 +1,99 @@
+package habits
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service(t *testing.T) {
+	list := domain.NewTodos()
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 8, 0, 0, 0, time.Local) }
+
+	cat := domain.NewTodo("Feed the cat")
+	cat.CreatedAt = day(4)
+	cat.Recurring = &domain.RecurringConfig{Frequency: "daily"}
+	cat.Occurrences = []time.Time{day(4), day(5), day(6)}
+	cat.Completed = true
+
+	plants := domain.NewTodo("Water the plants")
+	plants.CreatedAt = day(4)
+	plants.Recurring = &domain.RecurringConfig{Frequency: "weekly"}
+	plants.Occurrences = []time.Time{day(4)}
+	plants.Completed = true
+
+	list.Save(cat, plants, domain.NewTodo("Bake a cake"))
+
+	s := NewService(list).(*service)
+	// the 4th is a Monday
+	now := day(6)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	habits, err := s.Habits(ctx)
+	if err != nil {
+		t.Fatalf("Habits() error = %v", err)
+	}
+	if len(habits) != 2 || habits[0].Todo.ID != cat.ID || habits[0].Current != 3 || habits[1].Current != 1 {
+		t.Fatalf("Habits() = %+v, want the cat on 3 days then the plants on 1 week", habits)
+	}
+
+	// nothing reopens in the period it was completed
+	if reopened, _ := s.Rollover(ctx); len(reopened) != 0 {
+		t.Fatalf("Rollover() = %v, want nothing", reopened)
+	}
+
+	// the next day only the daily todo reopens
+	now = day(7)
+	reopened, err := s.Rollover(ctx)
+	if err != nil {
+		t.Fatalf("Rollover() error = %v", err)
+	}
+	if len(reopened) != 1 || reopened[0].ID != cat.ID || list.Get(cat.ID).Completed || !list.Get(plants.ID).Completed {
+		t.Fatalf("Rollover() = %v, want the cat reopened", reopened)
+	}
+
+	// a week on the cat has missed days and the plants reopen
+	now = day(12)
+	if reopened, _ = s.Rollover(ctx); len(reopened) != 1 || reopened[0].ID != plants.ID {
+		t.Fatalf("Rollover() = %v, want the plants reopened", reopened)
+	}
+	habits, _ = s.Habits(ctx)
+	for _, habit := range habits {
+		if habit.Todo.ID == cat.ID && (habit.Current != 0 || habit.Longest != 3 || habit.Missed != 5) {
+			t.Errorf("Habits() cat = %+v, want no streak, 3 longest and 5 missed", habit)
+		}
+	}
+}
+
+func Test_service_Rollover_concurrent(t *testing.T) {
+	list := domain.NewTodos()
+	cat := domain.NewTodo("Feed the cat")
+	cat.SetRecurring("daily", nil)
+	cat.Update(true, cat.Description)
+	list.Save(cat)
+	s := NewService(list).(*service)
+	s.now = func() time.Time { return time.Now().AddDate(0, 0, 1) }
+
+	// the rollover runs on the scheduler while people change the todo through the handlers
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = s.Rollover(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = list.Change(cat.ID, domain.Rename{Description: "Feed the cats"})
+		}()
+	}
+	wg.Wait()
+
+	if got := list.Get(cat.ID); got.Completed || got.Description != "Feed the cats" || got.Version != cat.Version+20 || got.Revision != cat.Revision+21 {
+		t.Errorf("the todo is %+v, want it reopened once and renamed 20 times", got)
+	}
+}
@@ -0,0 +1,9 @@
+package home
+
+import "errors"
+
+var (
+	ErrListNotFound  = errors.New("smart list not found")
+	ErrInvalidSort   = errors.New("invalid sort order")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
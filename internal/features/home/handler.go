@@ -1,17 +1,23 @@
 package home
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
 	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
 )
 
 type (
 	Handler interface {
-		// Home : GET /
+		// Home : GET /?sort=&cursor=
 		Home(w http.ResponseWriter, r *http.Request)
+		// SmartList : GET /lists/{list}?sort=&cursor=
+		SmartList(w http.ResponseWriter, r *http.Request)
 	}
 
 	handler struct {
@@ -25,16 +31,113 @@ func NewHandler(svc Service) Handler {
 
 func Mount(r chi.Router, h Handler) {
 	r.Get("/", h.Home)
+	r.Get("/lists/{list}", h.SmartList)
 }
 
 func (h handler) Home(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.service.List(r.Context())
+	req, err := pageRequest(r)
+	if err != nil {
+		listError(w, err)
+		return
+	}
+	page, err := h.service.List(r.Context(), req)
+	if err != nil {
+		listError(w, err)
+		return
+	}
+	next := nextURL(r, page.Next)
+
+	// scrolling down loads the todos of the next page to add to the list
+	if isHTMX(r) && req.Cursor != "" {
+		if err = partials.TodoRows(page.Todos, next).Render(r.Context(), w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	sidebar, err := Sidebar(r.Context(), h.service, identity.UserID(r.Context()), r.URL.Path)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := pages.HomePage(todos).Render(r.Context(), w); err != nil {
+	if err := pages.HomePage(page.Todos, next, req.Sort, sidebar).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) SmartList(w http.ResponseWriter, r *http.Request) {
+	list, ok := ParseSmartList(chi.URLParam(r, "list"))
+	if !ok {
+		http.Error(w, ErrListNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	req, err := pageRequest(r)
+	if err != nil {
+		listError(w, err)
+		return
+	}
+	page, err := h.service.SmartList(r.Context(), list, identity.UserID(r.Context()), req)
+	if err != nil {
+		listError(w, err)
+		return
+	}
+	next := nextURL(r, page.Next)
+
+	switch {
+	case isHTMX(r) && req.Cursor != "":
+		err = partials.TodoRows(page.Todos, next).Render(r.Context(), w)
+	case isHTMX(r):
+		err = partials.RenderTodos(page.Todos, next).Render(r.Context(), w)
+	default:
+		var sidebar partials.Sidebar
+		if sidebar, err = Sidebar(r.Context(), h.service, identity.UserID(r.Context()), r.URL.Path); err != nil {
+			listError(w, err)
+			return
+		}
+		err = pages.SmartListPage(list.Title(), r.URL.Path, page.Todos, next, req.Sort, sidebar).Render(r.Context(), w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func listError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrListNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidSort), errors.Is(err, ErrInvalidCursor):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
+
+// pageRequest reads the sort order and cursor of the page asked for
+func pageRequest(r *http.Request) (domain.PageRequest, error) {
+	order, ok := domain.ParseSortOrder(r.URL.Query().Get("sort"))
+	if !ok {
+		return domain.PageRequest{}, ErrInvalidSort
+	}
+	return domain.PageRequest{Sort: order, Cursor: r.URL.Query().Get("cursor")}, nil
+}
+
+// nextURL is where the page after the one asked for is loaded from, or empty on the last page
+func nextURL(r *http.Request, cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	values := r.URL.Query()
+	values.Set("cursor", cursor)
+	return r.URL.Path + "?" + values.Encode()
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
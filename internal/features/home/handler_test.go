@@ -3,6 +3,7 @@ package home
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -10,10 +11,13 @@ import (
 	"time"
 
 	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/stackus/todos/internal/domain"
 	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
 )
 
 func Test_handler_Home(t *testing.T) {
@@ -35,6 +39,14 @@ func Test_handler_Home(t *testing.T) {
 		Completed:   true,
 		CreatedAt:   time.Now(),
 	}
+	var counts = []ListCount{{List: ListToday, Count: 1}, {List: ListOverdue}}
+	var sidebar = partials.Sidebar{
+		Lists: []partials.SidebarLink{
+			{URL: "/lists/today", Title: "Today", Count: 1},
+			{URL: "/lists/overdue", Title: "Overdue"},
+		},
+		All: true,
+	}
 	type fields struct {
 		service *MockService
 	}
@@ -55,23 +67,55 @@ func Test_handler_Home(t *testing.T) {
 				r: httptest.NewRequest(http.MethodGet, "/", nil),
 			},
 			mock: func(f fields) {
-				f.service.EXPECT().List(context.Background()).Return([]*domain.Todo{}, nil)
+				f.service.EXPECT().List(context.Background(), domain.PageRequest{}).Return(domain.Page{Todos: []*domain.Todo{}}, nil)
+				f.service.EXPECT().Counts(context.Background(), uuid.Nil).Return(counts, nil)
+				f.service.EXPECT().Pinned(context.Background(), uuid.Nil).Return([]ViewCount{}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
-			wantView:       pages.HomePage([]*domain.Todo{}),
+			wantView:       pages.HomePage([]*domain.Todo{}, "", domain.SortManual, sidebar),
 		},
 		"NonEmptyList": {
 			args: args{
 				w: httptest.NewRecorder(),
-				r: httptest.NewRequest(http.MethodGet, "/", nil),
+				r: httptest.NewRequest(http.MethodGet, "/?sort=due", nil),
+			},
+			mock: func(f fields) {
+				f.service.EXPECT().List(context.Background(), domain.PageRequest{Sort: domain.SortDueDate}).Return(domain.Page{Todos: []*domain.Todo{firstTodo, secondTodo, thirdTodo}, Next: "abc"}, nil)
+				f.service.EXPECT().Counts(context.Background(), uuid.Nil).Return(counts, nil)
+				f.service.EXPECT().Pinned(context.Background(), uuid.Nil).Return([]ViewCount{}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+			wantView:       pages.HomePage([]*domain.Todo{firstTodo, secondTodo, thirdTodo}, "/?cursor=abc&sort=due", domain.SortDueDate, sidebar),
+		},
+		"NextPage": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest(http.MethodGet, "/?cursor=abc", nil)
+					r.Header.Set("HX-Request", "true")
+					return r
+				}(),
 			},
 			mock: func(f fields) {
-				f.service.EXPECT().List(context.Background()).Return([]*domain.Todo{firstTodo, secondTodo, thirdTodo}, nil)
+				f.service.EXPECT().List(context.Background(), domain.PageRequest{Cursor: "abc"}).Return(domain.Page{Todos: []*domain.Todo{thirdTodo}}, nil)
 			},
 			wantStatusCode: http.StatusOK,
 			wantHeader:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
-			wantView:       pages.HomePage([]*domain.Todo{firstTodo, secondTodo, thirdTodo}),
+			wantView:       partials.TodoRows([]*domain.Todo{thirdTodo}, ""),
+		},
+		"InvalidSort": {
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/?sort=shuffle", nil),
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantHeader:     http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}, "X-Content-Type-Options": []string{"nosniff"}},
+			wantView: templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, ErrInvalidSort.Error()+"\n")
+				return err
+			}),
 		},
 	}
 	for name, tt := range tests {
@@ -105,3 +149,78 @@ func Test_handler_Home(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_SmartList(t *testing.T) {
+	var todo = &domain.Todo{ID: uuid.New(), Description: "Pay the rent"}
+	var counts = []ListCount{{List: ListToday}, {List: ListOverdue, Count: 1}}
+	var pets = domain.NewView(uuid.Nil, "Pets", domain.Query{Tags: []string{"pets"}}, domain.SortManual, true)
+	var sidebar = partials.Sidebar{
+		Lists: []partials.SidebarLink{
+			{URL: "/lists/today", Title: "Today"},
+			{URL: "/lists/overdue", Title: "Overdue", Count: 1, Selected: true},
+		},
+		Views: []partials.SidebarLink{
+			{URL: "/views/" + pets.ID.String(), Title: "Pets", Count: 2},
+		},
+	}
+	tests := map[string]struct {
+		path           string
+		htmx           bool
+		mock           func(service *MockService)
+		wantStatusCode int
+		wantView       templ.Component
+	}{
+		"Page": {
+			path: "/lists/overdue",
+			mock: func(service *MockService) {
+				service.EXPECT().SmartList(mock.Anything, ListOverdue, uuid.Nil, domain.PageRequest{}).Return(domain.Page{Todos: []*domain.Todo{todo}}, nil)
+				service.EXPECT().Counts(mock.Anything, uuid.Nil).Return(counts, nil)
+				service.EXPECT().Pinned(mock.Anything, uuid.Nil).Return([]ViewCount{{View: pets, Count: 2}}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantView:       pages.SmartListPage("Overdue", "/lists/overdue", []*domain.Todo{todo}, "", domain.SortManual, sidebar),
+		},
+		"Partial": {
+			path: "/lists/overdue?sort=priority",
+			htmx: true,
+			mock: func(service *MockService) {
+				service.EXPECT().SmartList(mock.Anything, ListOverdue, uuid.Nil, domain.PageRequest{Sort: domain.SortPriority}).Return(domain.Page{Todos: []*domain.Todo{todo}, Next: "abc"}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantView:       partials.RenderTodos([]*domain.Todo{todo}, "/lists/overdue?cursor=abc&sort=priority"),
+		},
+		"UnknownList": {
+			path:           "/lists/someday-maybe",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service := NewMockService(t)
+			if tt.mock != nil {
+				tt.mock(service)
+			}
+			router := chi.NewRouter()
+			Mount(router, NewHandler(service))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("handler.SmartList() StatusCode = %v, want %v", rec.Code, tt.wantStatusCode)
+			}
+			if tt.wantView == nil {
+				return
+			}
+			want := new(bytes.Buffer)
+			_ = tt.wantView.Render(context.Background(), want)
+			if rec.Body.String() != want.String() {
+				t.Errorf("handler.SmartList() Body = %v, want %v", rec.Body.String(), want.String())
+			}
+		})
+	}
+}
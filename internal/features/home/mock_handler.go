@@ -55,6 +55,40 @@ func (_c *MockHandler_Home_Call) RunAndReturn(run func(http.ResponseWriter, *htt
 	return _c
 }
 
+// SmartList provides a mock function with given fields: w, r
+func (_m *MockHandler) SmartList(w http.ResponseWriter, r *http.Request) {
+	_m.Called(w, r)
+}
+
+// MockHandler_SmartList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SmartList'
+type MockHandler_SmartList_Call struct {
+	*mock.Call
+}
+
+// SmartList is a helper method to define mock.On call
+//   - w http.ResponseWriter
+//   - r *http.Request
+func (_e *MockHandler_Expecter) SmartList(w interface{}, r interface{}) *MockHandler_SmartList_Call {
+	return &MockHandler_SmartList_Call{Call: _e.mock.On("SmartList", w, r)}
+}
+
+func (_c *MockHandler_SmartList_Call) Run(run func(w http.ResponseWriter, r *http.Request)) *MockHandler_SmartList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(http.ResponseWriter), args[1].(*http.Request))
+	})
+	return _c
+}
+
+func (_c *MockHandler_SmartList_Call) Return() *MockHandler_SmartList_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockHandler_SmartList_Call) RunAndReturn(run func(http.ResponseWriter, *http.Request)) *MockHandler_SmartList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 type mockConstructorTestingTNewMockHandler interface {
 	mock.TestingT
 	Cleanup(func())
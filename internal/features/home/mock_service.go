@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/stackus/todos/internal/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -22,25 +24,78 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *MockService) List(ctx context.Context) ([]*domain.Todo, error) {
-	ret := _m.Called(ctx)
+// Counts provides a mock function with given fields: ctx, userID
+func (_m *MockService) Counts(ctx context.Context, userID uuid.UUID) ([]ListCount, error) {
+	ret := _m.Called(ctx, userID)
 
-	var r0 []*domain.Todo
+	var r0 []ListCount
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.Todo, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]ListCount, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*domain.Todo); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []ListCount); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Todo)
+			r0 = ret.Get(0).([]ListCount)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_Counts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Counts'
+type MockService_Counts_Call struct {
+	*mock.Call
+}
+
+// Counts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockService_Expecter) Counts(ctx interface{}, userID interface{}) *MockService_Counts_Call {
+	return &MockService_Counts_Call{Call: _e.mock.On("Counts", ctx, userID)}
+}
+
+func (_c *MockService_Counts_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockService_Counts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockService_Counts_Call) Return(_a0 []ListCount, _a1 error) *MockService_Counts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_Counts_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]ListCount, error)) *MockService_Counts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, req
+func (_m *MockService) List(ctx context.Context, req domain.PageRequest) (domain.Page, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 domain.Page
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) (domain.Page, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) domain.Page); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(domain.Page)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -55,23 +110,134 @@ type MockService_List_Call struct {
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *MockService_Expecter) List(ctx interface{}) *MockService_List_Call {
-	return &MockService_List_Call{Call: _e.mock.On("List", ctx)}
+//   - req domain.PageRequest
+func (_e *MockService_Expecter) List(ctx interface{}, req interface{}) *MockService_List_Call {
+	return &MockService_List_Call{Call: _e.mock.On("List", ctx, req)}
+}
+
+func (_c *MockService_List_Call) Run(run func(ctx context.Context, req domain.PageRequest)) *MockService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.PageRequest))
+	})
+	return _c
+}
+
+func (_c *MockService_List_Call) Return(_a0 domain.Page, _a1 error) *MockService_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_List_Call) RunAndReturn(run func(context.Context, domain.PageRequest) (domain.Page, error)) *MockService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pinned provides a mock function with given fields: ctx, userID
+func (_m *MockService) Pinned(ctx context.Context, userID uuid.UUID) ([]ViewCount, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []ViewCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]ViewCount, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []ViewCount); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ViewCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_Pinned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pinned'
+type MockService_Pinned_Call struct {
+	*mock.Call
+}
+
+// Pinned is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockService_Expecter) Pinned(ctx interface{}, userID interface{}) *MockService_Pinned_Call {
+	return &MockService_Pinned_Call{Call: _e.mock.On("Pinned", ctx, userID)}
+}
+
+func (_c *MockService_Pinned_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockService_Pinned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockService_Pinned_Call) Return(_a0 []ViewCount, _a1 error) *MockService_Pinned_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_Pinned_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]ViewCount, error)) *MockService_Pinned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SmartList provides a mock function with given fields: ctx, list, userID, req
+func (_m *MockService) SmartList(ctx context.Context, list SmartList, userID uuid.UUID, req domain.PageRequest) (domain.Page, error) {
+	ret := _m.Called(ctx, list, userID, req)
+
+	var r0 domain.Page
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, SmartList, uuid.UUID, domain.PageRequest) (domain.Page, error)); ok {
+		return rf(ctx, list, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, SmartList, uuid.UUID, domain.PageRequest) domain.Page); ok {
+		r0 = rf(ctx, list, userID, req)
+	} else {
+		r0 = ret.Get(0).(domain.Page)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, SmartList, uuid.UUID, domain.PageRequest) error); ok {
+		r1 = rf(ctx, list, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_SmartList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SmartList'
+type MockService_SmartList_Call struct {
+	*mock.Call
+}
+
+// SmartList is a helper method to define mock.On call
+//   - ctx context.Context
+//   - list SmartList
+//   - userID uuid.UUID
+//   - req domain.PageRequest
+func (_e *MockService_Expecter) SmartList(ctx interface{}, list interface{}, userID interface{}, req interface{}) *MockService_SmartList_Call {
+	return &MockService_SmartList_Call{Call: _e.mock.On("SmartList", ctx, list, userID, req)}
 }
 
-func (_c *MockService_List_Call) Run(run func(ctx context.Context)) *MockService_List_Call {
+func (_c *MockService_SmartList_Call) Run(run func(ctx context.Context, list SmartList, userID uuid.UUID, req domain.PageRequest)) *MockService_SmartList_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(SmartList), args[2].(uuid.UUID), args[3].(domain.PageRequest))
 	})
 	return _c
 }
 
-func (_c *MockService_List_Call) Return(_a0 []*domain.Todo, _a1 error) *MockService_List_Call {
+func (_c *MockService_SmartList_Call) Return(_a0 domain.Page, _a1 error) *MockService_SmartList_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockService_List_Call) RunAndReturn(run func(context.Context) ([]*domain.Todo, error)) *MockService_List_Call {
+func (_c *MockService_SmartList_Call) RunAndReturn(run func(context.Context, SmartList, uuid.UUID, domain.PageRequest) (domain.Page, error)) *MockService_SmartList_Call {
 	_c.Call.Return(run)
 	return _c
 }
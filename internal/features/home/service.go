@@ -2,27 +2,98 @@ package home
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/stackus/todos/internal/domain"
 )
 
 type (
 	Service interface {
-		// List returns a copy of the todos list
-		List(ctx context.Context) ([]*domain.Todo, error)
+		// List returns a page of the todos list, leaving out the todos snoozed until later
+		List(ctx context.Context, req domain.PageRequest) (domain.Page, error)
+		// SmartList returns a page of the todos on a smart list for the user
+		SmartList(ctx context.Context, list SmartList, userID uuid.UUID, req domain.PageRequest) (domain.Page, error)
+		// Counts returns how many todos are on each smart list for the user, in the order of SmartLists
+		Counts(ctx context.Context, userID uuid.UUID) ([]ListCount, error)
+		// Pinned returns the views the user pinned to the sidebar with how many todos each holds
+		Pinned(ctx context.Context, userID uuid.UUID) ([]ViewCount, error)
+	}
+
+	// ListCount is how many todos are on a smart list
+	ListCount struct {
+		List  SmartList
+		Count int
+	}
+
+	// ViewCount is how many todos are in a saved view
+	ViewCount struct {
+		View  *domain.View
+		Count int
 	}
 
 	service struct {
 		todos domain.TodoRepository
+		views domain.ViewRepository
+		now   func() time.Time
 	}
 )
 
-func NewService(todos domain.TodoRepository) Service {
+func NewService(todos domain.TodoRepository, views domain.ViewRepository) Service {
 	return &service{
 		todos: todos,
+		views: views,
+		now:   time.Now,
+	}
+}
+
+func (s service) List(_ context.Context, req domain.PageRequest) (domain.Page, error) {
+	now := s.now()
+
+	list := make([]*domain.Todo, 0)
+	for _, todo := range s.todos.All() {
+		if !todo.IsSnoozed(now) {
+			list = append(list, todo)
+		}
+	}
+	return page(list, req)
+}
+
+func (s service) SmartList(_ context.Context, list SmartList, userID uuid.UUID, req domain.PageRequest) (domain.Page, error) {
+	if _, ok := ParseSmartList(string(list)); !ok {
+		return domain.Page{}, ErrListNotFound
+	}
+
+	return page(list.todos(s.todos, userID, s.now()), req)
+}
+
+func (s service) Counts(_ context.Context, userID uuid.UUID) ([]ListCount, error) {
+	now := s.now()
+
+	counts := make([]ListCount, len(SmartLists))
+	for i, list := range SmartLists {
+		counts[i] = ListCount{List: list, Count: len(list.todos(s.todos, userID, now))}
 	}
+	return counts, nil
 }
 
-func (s service) List(context.Context) ([]*domain.Todo, error) {
-	return s.todos.All(), nil
+func (s service) Pinned(_ context.Context, userID uuid.UUID) ([]ViewCount, error) {
+	now := s.now()
+
+	counts := make([]ViewCount, 0)
+	for _, view := range s.views.ForUser(userID) {
+		if view.Pinned {
+			counts = append(counts, ViewCount{View: view, Count: len(view.Todos(s.todos, now))})
+		}
+	}
+	return counts, nil
+}
+
+func page(todos []*domain.Todo, req domain.PageRequest) (domain.Page, error) {
+	page, ok := req.Page(todos)
+	if !ok {
+		return page, ErrInvalidCursor
+	}
+	return page, nil
 }
@@ -0,0 +1,121 @@
+package home
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service_SmartList(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.Local)
+	at := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+	userID := uuid.New()
+
+	rent := domain.NewTodo("Pay the rent")
+	rent.DueDate = at(-26 * time.Hour)
+	rent.Priority = domain.PriorityHigh
+	lunch := domain.NewTodo("Book lunch")
+	lunch.DueDate = at(-time.Hour)
+	dinner := domain.NewTodo("Book dinner")
+	dinner.DueDate = at(7 * time.Hour)
+	dinner.AssignedTo = &userID
+	trip := domain.NewTodo("Plan the trip")
+	trip.DueDate = at(72 * time.Hour)
+	someday := domain.NewTodo("Learn the banjo")
+	done := domain.NewTodo("Feed the cat")
+	done.Completed = true
+	snoozed := domain.NewTodo("Renew the passport")
+	snoozed.Snooze(now.Add(time.Hour), userID, false)
+	archived := domain.NewTodo("Old news")
+	archived.DueDate = at(time.Hour)
+	archived.Archive()
+	list := domain.NewTodos()
+	list.Save(rent, lunch, dinner, trip, someday, done, snoozed, archived)
+
+	s := NewService(list, domain.NewViews()).(*service)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// the repository compares due dates with the real time, so the lists due by a date are left to it
+	tests := map[SmartList][]*domain.Todo{
+		ListToday:        {lunch, dinner},
+		ListSomeday:      {someday},
+		ListMine:         {dinner},
+		ListHighPriority: {rent},
+	}
+	for list, want := range tests {
+		page, err := s.SmartList(ctx, list, userID, domain.PageRequest{})
+		if err != nil {
+			t.Fatalf("SmartList(%s) error = %v", list, err)
+		}
+		got := page.Todos
+		if len(got) != len(want) {
+			t.Errorf("SmartList(%s) = %d todos, want %d", list, len(got), len(want))
+			continue
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID {
+				t.Errorf("SmartList(%s)[%d] = %q, want %q", list, i, got[i].Description, want[i].Description)
+			}
+		}
+	}
+
+	// nobody is assigned anything without an ID
+	if got, _ := s.SmartList(ctx, ListMine, uuid.Nil, domain.PageRequest{}); len(got.Todos) != 0 {
+		t.Errorf("SmartList(mine) = %v for a visitor without an ID, want nothing", got.Todos)
+	}
+	if _, err := s.SmartList(ctx, "someday-maybe", userID, domain.PageRequest{}); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("SmartList() error = %v, want %v", err, ErrListNotFound)
+	}
+	if _, err := s.SmartList(ctx, ListToday, userID, domain.PageRequest{Cursor: "nowhere"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("SmartList() error = %v, want %v", err, ErrInvalidCursor)
+	}
+
+	// the todos due today come a page at a time, the latest due first
+	page, err := s.SmartList(ctx, ListToday, userID, domain.PageRequest{Sort: "-due", Limit: 1})
+	if err != nil || len(page.Todos) != 1 || page.Todos[0].ID != dinner.ID || page.Next == "" {
+		t.Fatalf("SmartList(today) first page = %v, %v", page, err)
+	}
+	page, err = s.SmartList(ctx, ListToday, userID, domain.PageRequest{Sort: "-due", Cursor: page.Next, Limit: 1})
+	if err != nil || len(page.Todos) != 1 || page.Todos[0].ID != lunch.ID || page.Next != "" {
+		t.Errorf("SmartList(today) last page = %v, %v", page, err)
+	}
+
+	counts, err := s.Counts(ctx, userID)
+	if err != nil {
+		t.Fatalf("Counts() error = %v", err)
+	}
+	if len(counts) != len(SmartLists) || counts[0].List != ListToday || counts[0].Count != 2 {
+		t.Errorf("Counts() = %v, want every list with 2 for today first", counts)
+	}
+}
+
+func Test_service_Pinned(t *testing.T) {
+	userID := uuid.New()
+	list := domain.NewTodos()
+	list.Add("Book dinner")
+	list.Add("Cook dinner")
+	list.Add("Bake a cake")
+	views := domain.NewViews()
+	dinners := domain.NewView(userID, "Dinners", domain.Query{Search: "dinner"}, domain.SortManual, true)
+	views.Save(dinners)
+	views.Save(domain.NewView(userID, "Everything", domain.Query{}, domain.SortManual, false))
+	views.Save(domain.NewView(uuid.New(), "Someone else's", domain.Query{}, domain.SortManual, true))
+	s := NewService(list, views)
+
+	pinned, err := s.Pinned(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("Pinned() error = %v", err)
+	}
+	if len(pinned) != 1 || pinned[0].View.ID != dinners.ID || pinned[0].Count != 2 {
+		t.Errorf("Pinned() = %v, want the dinners with 2 todos", pinned)
+	}
+}
@@ -0,0 +1,45 @@
+package home
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+// Sidebar returns the smart lists and pinned views of the user with their counts, marking the one at the path as selected
+func Sidebar(ctx context.Context, svc Service, userID uuid.UUID, path string) (partials.Sidebar, error) {
+	var sidebar partials.Sidebar
+
+	counts, err := svc.Counts(ctx, userID)
+	if err != nil {
+		return sidebar, err
+	}
+	for _, count := range counts {
+		url := "/lists/" + string(count.List)
+		sidebar.Lists = append(sidebar.Lists, partials.SidebarLink{
+			URL:      url,
+			Title:    count.List.Title(),
+			Count:    count.Count,
+			Selected: url == path,
+		})
+	}
+
+	pinned, err := svc.Pinned(ctx, userID)
+	if err != nil {
+		return sidebar, err
+	}
+	for _, count := range pinned {
+		url := "/views/" + count.View.ID.String()
+		sidebar.Views = append(sidebar.Views, partials.SidebarLink{
+			URL:      url,
+			Title:    count.View.Name,
+			Count:    count.Count,
+			Selected: url == path,
+		})
+	}
+
+	sidebar.All = path == "/"
+	return sidebar, nil
+}
@@ -0,0 +1,90 @@
+package home
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// SmartList is a built-in view of the todos that still need doing
+type SmartList string
+
+const (
+	ListToday        SmartList = "today"
+	ListUpcoming     SmartList = "upcoming"
+	ListOverdue      SmartList = "overdue"
+	ListSomeday      SmartList = "someday"
+	ListMine         SmartList = "mine"
+	ListHighPriority SmartList = "high-priority"
+)
+
+// SmartLists are the smart lists in the order they are shown
+var SmartLists = []SmartList{ListToday, ListUpcoming, ListOverdue, ListSomeday, ListMine, ListHighPriority}
+
+// ParseSmartList returns the smart list with the name used in its URL
+func ParseSmartList(name string) (SmartList, bool) {
+	for _, list := range SmartLists {
+		if string(list) == name {
+			return list, true
+		}
+	}
+	return "", false
+}
+
+// Title returns the name the list is shown with
+func (l SmartList) Title() string {
+	switch l {
+	case ListToday:
+		return "Today"
+	case ListUpcoming:
+		return "Next 7 days"
+	case ListOverdue:
+		return "Overdue"
+	case ListSomeday:
+		return "No due date"
+	case ListMine:
+		return "Assigned to me"
+	case ListHighPriority:
+		return "High priority"
+	default:
+		return string(l)
+	}
+}
+
+// todos returns the todos on the list for the user
+//
+// Only open todos are listed: those completed, archived or snoozed until later are left out.
+func (l SmartList) todos(repo domain.TodoRepository, userID uuid.UUID, now time.Time) []*domain.Todo {
+	var candidates []*domain.Todo
+	switch l {
+	case ListToday:
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		candidates = repo.GetByDueDate(start, start.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	case ListUpcoming:
+		candidates = repo.GetUpcoming(7)
+	case ListOverdue:
+		candidates = repo.GetOverdue()
+	case ListSomeday:
+		for _, todo := range repo.All() {
+			if todo.DueDate == nil {
+				candidates = append(candidates, todo)
+			}
+		}
+	case ListMine:
+		if userID != uuid.Nil {
+			candidates = repo.GetByAssignee(userID)
+		}
+	case ListHighPriority:
+		candidates = repo.GetByPriority(domain.PriorityHigh)
+	}
+
+	list := make([]*domain.Todo, 0, len(candidates))
+	for _, todo := range candidates {
+		if !todo.Completed && !todo.Archived && !todo.IsSnoozed(now) {
+			list = append(list, todo)
+		}
+	}
+	return list
+}
@@ -0,0 +1,7 @@
+package inbox
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
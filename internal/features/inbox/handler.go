@@ -0,0 +1,107 @@
+package inbox
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/templates/pages"
+)
+
+type (
+	Handler interface {
+		// Inbox : GET /inbox
+		Inbox(w http.ResponseWriter, r *http.Request)
+		// MarkRead : POST /inbox/{notificationId}/read
+		MarkRead(w http.ResponseWriter, r *http.Request)
+		// MarkAllRead : POST /inbox/read
+		MarkAllRead(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/inbox", func(r chi.Router) {
+		r.Get("/", h.Inbox)
+		r.Post("/read", h.MarkAllRead)
+		r.Post("/{notificationId}/read", h.MarkRead)
+	})
+}
+
+func (h handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	var all = r.URL.Query().Get("all") == "true"
+	notifications, err := h.service.List(r.Context(), identity.UserID(r.Context()), all)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err = pages.InboxPage(notifications, all).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	var id = chi.URLParam(r, "notificationId")
+	var notificationID uuid.UUID
+	var err error
+	if notificationID, err = uuid.Parse(id); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.MarkRead(r.Context(), identity.UserID(r.Context()), notificationID); err != nil {
+		switch {
+		case errors.Is(err, ErrNotificationNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	switch isHTMX(r) {
+	case true:
+		// the notification leaves the list of unread ones
+		_, err = w.Write([]byte(""))
+	default:
+		http.Redirect(w, r, "/inbox", http.StatusFound)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.MarkAllRead(r.Context(), identity.UserID(r.Context())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch isHTMX(r) {
+	case true:
+		w.Header().Set("HX-Redirect", "/inbox")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Redirect(w, r, "/inbox", http.StatusFound)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// List returns the notifications of the user, newest first; read ones only when all is true
+		List(ctx context.Context, userID uuid.UUID, all bool) ([]*domain.Notification, error)
+		// UnreadCount returns the number of notifications the user has not read
+		UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+		// MarkRead marks one of the notifications of the user as read
+		MarkRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error
+		// MarkAllRead marks every notification of the user as read
+		MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	}
+
+	service struct {
+		notifications domain.NotificationRepository
+		now           func() time.Time
+	}
+)
+
+func NewService(notifications domain.NotificationRepository) Service {
+	return &service{
+		notifications: notifications,
+		now:           time.Now,
+	}
+}
+
+func (s service) List(_ context.Context, userID uuid.UUID, all bool) ([]*domain.Notification, error) {
+	list := make([]*domain.Notification, 0)
+	for _, notification := range s.notifications.ForUser(userID) {
+		if all || !notification.Read() {
+			list = append(list, notification)
+		}
+	}
+
+	return list, nil
+}
+
+func (s service) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	unread, err := s.List(ctx, userID, false)
+
+	return len(unread), err
+}
+
+func (s service) MarkRead(_ context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	notification := s.notifications.Get(notificationID)
+	// other users' notifications are as good as missing
+	if notification == nil || notification.UserID != userID {
+		return ErrNotificationNotFound
+	}
+
+	s.notifications.MarkRead(notificationID, s.now())
+	return nil
+}
+
+func (s service) MarkAllRead(_ context.Context, userID uuid.UUID) error {
+	now := s.now()
+	for _, notification := range s.notifications.ForUser(userID) {
+		s.notifications.MarkRead(notification.ID, now)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+	notifications := domain.NewNotifications()
+	first := domain.NewNotification(userID, domain.NotificationMention, uuid.New(), "first")
+	second := domain.NewNotification(userID, domain.NotificationAssignment, uuid.New(), "second")
+	other := domain.NewNotification(otherID, domain.NotificationMention, uuid.New(), "other")
+	for _, n := range []*domain.Notification{first, second, other} {
+		notifications.Add(n)
+	}
+	s := NewService(notifications)
+	ctx := context.Background()
+
+	if count, _ := s.UnreadCount(ctx, userID); count != 2 {
+		t.Fatalf("UnreadCount() = %d, want 2", count)
+	}
+
+	// nobody marks the notifications of others
+	if err := s.MarkRead(ctx, userID, other.ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Fatalf("MarkRead() error = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := s.MarkRead(ctx, userID, first.ID); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+	unread, _ := s.List(ctx, userID, false)
+	if len(unread) != 1 || unread[0].ID != second.ID {
+		t.Errorf("List() = %v, want only the second notification", unread)
+	}
+	all, _ := s.List(ctx, userID, true)
+	if len(all) != 2 {
+		t.Errorf("List(all) = %v, want both notifications", all)
+	}
+
+	if err := s.MarkAllRead(ctx, userID); err != nil {
+		t.Fatalf("MarkAllRead() error = %v", err)
+	}
+	if count, _ := s.UnreadCount(ctx, userID); count != 0 {
+		t.Errorf("UnreadCount() = %d, want 0", count)
+	}
+	if notifications.Get(other.ID).Read() {
+		t.Errorf("the notification of another user was marked read")
+	}
+}
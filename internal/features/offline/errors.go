@@ -0,0 +1,9 @@
+package offline
+
+import "errors"
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrTodoNotFound = errors.New("todo not found")
+	ErrInvalidField = errors.New("unknown field or invalid value")
+)
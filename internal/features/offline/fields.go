@@ -0,0 +1,129 @@
+package offline
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// field is a field of a todo kept in sync, changed on its own by clients
+type field struct {
+	name string
+	// get returns the value of the field as it is sent to clients
+	get func(todo *domain.Todo) any
+	// change returns the change setting the field to the value a client sent, or false when the value cannot be used
+	change func(value json.RawMessage) (domain.Change, bool)
+}
+
+// fields are the fields kept in sync; the rest of a todo is sent to clients but only changed on the server
+var fields = []field{
+	{
+		name: "description",
+		get:  func(todo *domain.Todo) any { return todo.Description },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var description string
+			if json.Unmarshal(value, &description) != nil || strings.TrimSpace(description) == "" {
+				return nil, false
+			}
+			return domain.Rename{Description: description}, true
+		},
+	},
+	{
+		name: "completed",
+		get:  func(todo *domain.Todo) any { return todo.Completed },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			// recurring todos record the occurrence as they are completed
+			var completed bool
+			if json.Unmarshal(value, &completed) != nil {
+				return nil, false
+			}
+			return domain.Complete{Completed: completed}, true
+		},
+	},
+	{
+		name: "priority",
+		get:  func(todo *domain.Todo) any { return todo.Priority.String() },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var name string
+			if json.Unmarshal(value, &name) != nil {
+				return nil, false
+			}
+			priority, ok := domain.ParsePriority(name)
+			if !ok {
+				return nil, false
+			}
+			return domain.SetPriority{Priority: priority}, true
+		},
+	},
+	{
+		name: "category",
+		get:  func(todo *domain.Todo) any { return todo.Category },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var category string
+			if json.Unmarshal(value, &category) != nil {
+				return nil, false
+			}
+			return domain.SetCategory{Category: category}, true
+		},
+	},
+	{
+		name: "tags",
+		get: func(todo *domain.Todo) any {
+			if todo.Tags == nil {
+				return []string{}
+			}
+			return todo.Tags
+		},
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var tags []string
+			if json.Unmarshal(value, &tags) != nil {
+				return nil, false
+			}
+			return domain.SetTags{Tags: tags}, true
+		},
+	},
+	{
+		name: "dueDate",
+		get:  func(todo *domain.Todo) any { return todo.DueDate },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var due *time.Time
+			if json.Unmarshal(value, &due) != nil {
+				return nil, false
+			}
+			return domain.SetDueDate{DueDate: due}, true
+		},
+	},
+	{
+		name: "archived",
+		get:  func(todo *domain.Todo) any { return todo.Archived },
+		change: func(value json.RawMessage) (domain.Change, bool) {
+			var archived bool
+			if json.Unmarshal(value, &archived) != nil {
+				return nil, false
+			}
+			return domain.SetArchived{Archived: archived}, true
+		},
+	},
+}
+
+// fieldNamed returns the field kept in sync with the name, and false when there is none
+func fieldNamed(name string) (field, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// values returns the fields of the todo kept in sync, encoded to compare them
+func values(todo *domain.Todo) map[string]string {
+	encoded := make(map[string]string, len(fields))
+	for _, f := range fields {
+		data, _ := json.Marshal(f.get(todo))
+		encoded[f.name] = string(data)
+	}
+	return encoded
+}
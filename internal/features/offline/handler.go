@@ -0,0 +1,63 @@
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxSyncSize is the largest sync request read, enough for a long spell offline
+const maxSyncSize = 4 << 20
+
+type (
+	Handler interface {
+		// Sync : POST /api/sync
+		Sync(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Post("/api/sync", h.Sync)
+}
+
+// Sync applies the mutations queued by a client and returns what changed since its sync token
+func (h handler) Sync(w http.ResponseWriter, r *http.Request) {
+	var req SyncRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSyncSize)).Decode(&req); err != nil {
+		syncError(w, fmt.Errorf("%w: %w", ErrInvalidInput, err))
+		return
+	}
+
+	res, err := h.service.Sync(r.Context(), req)
+	if err != nil {
+		syncError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func syncError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
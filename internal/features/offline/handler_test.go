@@ -0,0 +1,42 @@
+package offline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestHandler_Sync(t *testing.T) {
+	s, list, _ := newTestService(t)
+	todo := list.Add("Book the flights")
+	router := chi.NewRouter()
+	Mount(router, NewHandler(s))
+
+	do := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(body)))
+		return rec
+	}
+
+	for _, body := range []string{`{`, `{"mutations":[]}`} {
+		if rec := do(body); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST /api/sync %s = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	rec := do(`{"clientId":"a","mutations":[{"id":"1","op":"update","todoId":"` + todo.ID.String() + `","fields":{"completed":true}}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/sync = %d: %s", rec.Code, rec.Body)
+	}
+	var res SyncResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Token == "" || !res.Reset || len(res.Todos) != 1 || !res.Todos[0].Completed || res.Results[0].Status != StatusApplied {
+		t.Errorf("POST /api/sync = %s", rec.Body)
+	}
+}
@@ -0,0 +1,195 @@
+package offline
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	// journal remembers when each todo and each of its fields kept in sync last changed
+	//
+	// Changes are numbered as they are seen; a sync token is the number of the last change a client has seen.
+	// Changes made on the server, such as through the web pages, are found by comparing the todos with the
+	// journal each time a client syncs.
+	journal struct {
+		// epoch tells this journal from one kept before the server restarted, whose change numbers mean nothing now
+		epoch   string
+		seq     int64
+		entries map[uuid.UUID]*entry
+		// horizon is the last change whose tombstone has been forgotten; clients that have not seen it start over
+		horizon int64
+	}
+
+	// entry is what the journal knows of a todo
+	entry struct {
+		// seq is the last change made to the todo
+		seq int64
+		// values are the fields kept in sync as they were last seen, encoded
+		values map[string]string
+		// clocks are when each field last changed by the server's clock, and writers the clients that changed
+		// them, empty for changes made on the server
+		clocks  map[string]time.Time
+		writers map[string]string
+		// deletedAt is set once the todo is deleted, leaving the entry as its tombstone
+		deletedAt *time.Time
+	}
+
+	// token is a sync token as it is handed to clients, written out as base64 JSON
+	token struct {
+		Epoch string `json:"e"`
+		Seq   int64  `json:"s"`
+	}
+)
+
+func newJournal() *journal {
+	return &journal{epoch: uuid.NewString(), entries: make(map[uuid.UUID]*entry)}
+}
+
+// scan brings the journal up to date with the todos, numbering a change for each todo that changed since
+// the last scan and leaving tombstones for those that are gone
+//
+// A field found changed is taken to have changed when the todo was last updated, so that a change made on
+// the server before a client's is not taken for a later one just because it was found later.
+func (j *journal) scan(todos []*domain.Todo, now time.Time) {
+	seen := make(map[uuid.UUID]bool, len(todos))
+	for _, todo := range todos {
+		seen[todo.ID] = true
+		e, ok := j.entries[todo.ID]
+		if !ok || e.deletedAt != nil {
+			j.track(todo, todo.UpdatedAt)
+			continue
+		}
+
+		changed := false
+		for name, value := range values(todo) {
+			if e.values[name] == value {
+				continue
+			}
+			changed = true
+			e.values[name] = value
+			at := todo.UpdatedAt
+			if !at.After(e.clocks[name]) {
+				at = now
+			}
+			e.clocks[name], e.writers[name] = at, ""
+		}
+		if changed {
+			e.seq = j.next()
+		}
+	}
+
+	for id, e := range j.entries {
+		if !seen[id] && e.deletedAt == nil {
+			j.delete(id, now)
+		}
+	}
+}
+
+// track starts an entry for a todo that is new to the journal, with every field changed at the time
+func (j *journal) track(todo *domain.Todo, at time.Time) *entry {
+	e := &entry{
+		seq:     j.next(),
+		values:  values(todo),
+		clocks:  make(map[string]time.Time, len(fields)),
+		writers: make(map[string]string, len(fields)),
+	}
+	for _, f := range fields {
+		e.clocks[f.name] = at
+	}
+	j.entries[todo.ID] = e
+	return e
+}
+
+// write records that a client changed a field of the todo, returning false when a later write, by the
+// server's clock, already changed it
+//
+// Writes made at the same time are ordered by the IDs of the clients that made them, so that every server
+// settles the same conflict the same way.
+func (j *journal) write(e *entry, name string, at time.Time, clientID string) bool {
+	clock, writer := e.clocks[name], e.writers[name]
+	if at.Before(clock) || (at.Equal(clock) && clientID <= writer) {
+		return false
+	}
+	e.clocks[name], e.writers[name] = at, clientID
+	return true
+}
+
+// changed numbers a change to the todo after clients wrote to it, remembering its fields as they are now
+func (j *journal) changed(e *entry, todo *domain.Todo) {
+	e.values = values(todo)
+	e.seq = j.next()
+}
+
+func (j *journal) delete(id uuid.UUID, now time.Time) {
+	e, ok := j.entries[id]
+	if !ok {
+		e = &entry{}
+		j.entries[id] = e
+	}
+	e.deletedAt = &now
+	e.seq = j.next()
+}
+
+// forget drops the tombstones of todos deleted before the time; clients that have not seen them start over
+func (j *journal) forget(before time.Time) {
+	for id, e := range j.entries {
+		if e.deletedAt != nil && e.deletedAt.Before(before) {
+			if e.seq > j.horizon {
+				j.horizon = e.seq
+			}
+			delete(j.entries, id)
+		}
+	}
+}
+
+// since returns the todos changed and deleted after the change the token names
+//
+// All of the todos are returned when the token is empty, from another journal or older than the forgotten
+// tombstones, with reset set to tell the client to drop what it has.
+func (j *journal) since(encoded string) (changed, deleted []uuid.UUID, reset bool) {
+	after, ok := j.decode(encoded)
+	reset = !ok || after < j.horizon
+	changed, deleted = make([]uuid.UUID, 0), make([]uuid.UUID, 0)
+	for id, e := range j.entries {
+		switch {
+		case e.deletedAt != nil:
+			if !reset && e.seq > after {
+				deleted = append(deleted, id)
+			}
+		case reset || e.seq > after:
+			changed = append(changed, id)
+		}
+	}
+	return changed, deleted, reset
+}
+
+// token returns the sync token of the last change
+func (j *journal) token() string {
+	data, _ := json.Marshal(token{Epoch: j.epoch, Seq: j.seq})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (j *journal) decode(encoded string) (int64, bool) {
+	if encoded == "" {
+		return 0, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, false
+	}
+	var t token
+	if json.Unmarshal(data, &t) != nil || t.Epoch != j.epoch || t.Seq > j.seq {
+		return 0, false
+	}
+	return t.Seq, true
+}
+
+func (j *journal) next() int64 {
+	j.seq++
+	return j.seq
+}
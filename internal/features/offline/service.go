@@ -0,0 +1,278 @@
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+// tombstoneTTL is how long deletions are remembered; clients that have not synced for longer start over
+const tombstoneTTL = 30 * 24 * time.Hour
+
+// maxSkew is how far a client's clock may be off the server's before the times of its changes are not
+// believed, and its changes are taken as made when they reach the server
+const maxSkew = 24 * time.Hour
+
+// The kinds of mutation a client queues
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// What became of a mutation
+const (
+	// StatusApplied is a mutation that changed the todo, though fields listed as ignored lost to later writes
+	StatusApplied = "applied"
+	// StatusStale is a mutation whose every field lost to later writes
+	StatusStale = "stale"
+	// StatusDeleted is a mutation of a todo that has been deleted; deletions win over every other change
+	StatusDeleted = "deleted"
+	// StatusRejected is a mutation that could not be applied, as told by its error
+	StatusRejected = "rejected"
+)
+
+type (
+	Service interface {
+		// Sync applies the mutations a client queued while it was offline and returns what changed since its
+		// sync token
+		//
+		// Conflicting writes to a field are settled by the last writer winning, going by the server's clock;
+		// of two writes at the same time, the one from the client whose ID sorts last wins. The times of a
+		// client's mutations are moved onto the server's clock by how far the client's clock is off, as told
+		// by the request's now, and kept between tombstoneTTL ago and the time the request arrived. A client
+		// that sends no now, or whose clock is off by more than maxSkew, has its mutations stamped with the
+		// time the request arrived.
+		Sync(ctx context.Context, req SyncRequest) (SyncResponse, error)
+	}
+
+	// SyncRequest is what a client sends to sync
+	SyncRequest struct {
+		// Token is the token of the last sync, or empty for the first
+		Token string `json:"token"`
+		// ClientID tells the client apart from the others, to order writes made at the same time
+		ClientID string `json:"clientId"`
+		// Now is the time by the client's clock as the request is sent
+		Now       time.Time  `json:"now"`
+		Mutations []Mutation `json:"mutations"`
+	}
+
+	// Mutation is a change a client made, queued until it synced
+	Mutation struct {
+		// ID is the client's ID of the mutation, returned with its result
+		ID     string    `json:"id"`
+		Op     string    `json:"op"`
+		TodoID uuid.UUID `json:"todoId"`
+		// At is when the change was made by the client's clock
+		At time.Time `json:"at"`
+		// Fields are the fields changed, by name; create needs a description
+		Fields map[string]json.RawMessage `json:"fields,omitempty"`
+	}
+
+	// SyncResponse is what changed since the client's last sync
+	SyncResponse struct {
+		// Token is the token to send with the next sync
+		Token string `json:"token"`
+		// Reset tells the client that Todos holds every todo and it should drop any it has that are not there
+		Reset bool                 `json:"reset"`
+		Todos []todos.TodoResponse `json:"todos"`
+		// Deleted are the IDs of the todos deleted since the last sync
+		Deleted []uuid.UUID      `json:"deleted"`
+		Results []MutationResult `json:"results"`
+	}
+
+	// MutationResult is what became of a mutation
+	MutationResult struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		// Ignored are the fields that lost to later writes
+		Ignored []string `json:"ignored,omitempty"`
+		Error   string   `json:"error,omitempty"`
+	}
+
+	service struct {
+		mu      sync.Mutex
+		todos   domain.TodoRepository
+		tasks   todos.Service
+		journal *journal
+		now     func() time.Time
+	}
+)
+
+// NewService creates the sync service; todos are deleted through the todo service so that their files go too
+func NewService(repo domain.TodoRepository, tasks todos.Service) Service {
+	return &service{
+		todos:   repo,
+		tasks:   tasks,
+		journal: newJournal(),
+		now:     time.Now,
+	}
+}
+
+func (s *service) Sync(ctx context.Context, req SyncRequest) (SyncResponse, error) {
+	if req.ClientID == "" {
+		return SyncResponse{}, ErrInvalidInput
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.journal.forget(now.Add(-tombstoneTTL))
+	s.journal.scan(s.todos.All(), now)
+
+	res := SyncResponse{Results: make([]MutationResult, len(req.Mutations))}
+	for i, m := range req.Mutations {
+		res.Results[i] = s.apply(ctx, m, req.ClientID, stamp(m.At, req.Now, now), now)
+	}
+
+	changed, deleted, reset := s.journal.since(req.Token)
+	res.Token, res.Reset, res.Deleted = s.journal.token(), reset, deleted
+	sort.Slice(res.Deleted, func(i, j int) bool { return res.Deleted[i].String() < res.Deleted[j].String() })
+
+	// the todos go in the order they were dragged into
+	wanted := make(map[uuid.UUID]bool, len(changed))
+	for _, id := range changed {
+		wanted[id] = true
+	}
+	res.Todos = make([]todos.TodoResponse, 0, len(changed))
+	for _, todo := range s.todos.All() {
+		if wanted[todo.ID] {
+			res.Todos = append(res.Todos, todos.NewTodoResponse(todo))
+		}
+	}
+
+	return res, nil
+}
+
+// stamp returns when a mutation made at the time by the client's clock was made by the server's, for a client
+// whose clock said clientNow as the server's said now
+//
+// The times are the client's to give, so they are never believed further than maxSkew off, and never put a
+// write later than now, where it would win over writes yet to arrive, or earlier than the journal remembers.
+func stamp(at, clientNow, now time.Time) time.Time {
+	if at.IsZero() || clientNow.IsZero() {
+		return now
+	}
+	skew := now.Sub(clientNow)
+	if skew > maxSkew || skew < -maxSkew {
+		return now
+	}
+	at = at.Add(skew)
+	if at.After(now) {
+		return now
+	}
+	if oldest := now.Add(-tombstoneTTL); at.Before(oldest) {
+		return oldest
+	}
+	return at
+}
+
+// apply applies one mutation of the client, made at the time by the server's clock
+func (s *service) apply(ctx context.Context, m Mutation, clientID string, at, now time.Time) MutationResult {
+	result := MutationResult{ID: m.ID}
+	reject := func(err error) MutationResult {
+		result.Status, result.Error = StatusRejected, err.Error()
+		return result
+	}
+
+	if m.TodoID == uuid.Nil {
+		return reject(ErrInvalidInput)
+	}
+	e := s.journal.entries[m.TodoID]
+	if e != nil && e.deletedAt != nil {
+		result.Status = StatusDeleted
+		return result
+	}
+
+	switch m.Op {
+	case OpDelete:
+		if err := s.tasks.Remove(ctx, m.TodoID); err != nil {
+			return reject(err)
+		}
+		s.journal.delete(m.TodoID, now)
+		result.Status = StatusApplied
+		return result
+	case OpCreate, OpUpdate:
+	default:
+		return reject(ErrInvalidInput)
+	}
+
+	// every field is checked before any is applied, so that a mutation is applied whole or not at all
+	names := make([]string, 0, len(m.Fields))
+	changes := make(map[string]domain.Change, len(m.Fields))
+	for name, value := range m.Fields {
+		f, ok := fieldNamed(name)
+		if !ok {
+			return reject(ErrInvalidField)
+		}
+		if changes[name], ok = f.change(value); !ok {
+			return reject(ErrInvalidField)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	todo := s.todos.Get(m.TodoID)
+	if todo == nil {
+		if m.Op == OpUpdate {
+			return reject(ErrTodoNotFound)
+		}
+		return s.create(m, names, changes, clientID, at)
+	}
+
+	applied := make([]domain.Change, 0, len(names))
+	for _, name := range names {
+		if !s.journal.write(e, name, at, clientID) {
+			result.Ignored = append(result.Ignored, name)
+			continue
+		}
+		applied = append(applied, changes[name])
+	}
+
+	result.Status = StatusApplied
+	if len(applied) == 0 && len(names) > 0 {
+		result.Status = StatusStale
+		return result
+	}
+	todo, err := s.todos.Change(todo.ID, applied...)
+	if err != nil {
+		return reject(err)
+	}
+	s.journal.changed(e, todo)
+	return result
+}
+
+// create adds the todo a client made while offline, with every field the client gave it, all at once
+func (s *service) create(m Mutation, names []string, changes map[string]domain.Change, clientID string, at time.Time) MutationResult {
+	result := MutationResult{ID: m.ID}
+	if _, ok := m.Fields["description"]; !ok {
+		result.Status, result.Error = StatusRejected, ErrInvalidInput.Error()
+		return result
+	}
+
+	// the todo keeps the ID the client gave it, so the client's copy and the server's are the same todo
+	todo := domain.NewTodo("")
+	todo.ID = m.TodoID
+	for _, name := range names {
+		if err := changes[name].Apply(todo, at); err != nil {
+			result.Status, result.Error = StatusRejected, err.Error()
+			return result
+		}
+	}
+	s.todos.Save(todo)
+
+	e := s.journal.track(todo, at)
+	for _, name := range names {
+		s.journal.write(e, name, at, clientID)
+	}
+	result.Status = StatusApplied
+	return result
+}
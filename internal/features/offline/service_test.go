@@ -0,0 +1,336 @@
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+// clock is the server's clock in the tests, moved on by hand
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestService(t *testing.T) (*service, *domain.Todos, *clock) {
+	t.Helper()
+	list := domain.NewTodos()
+	tasks := todos.NewService(list, domain.NewUsers(), domain.NewNotifications(), todos.NewNoopNotificationService(), todos.NewNoopAttachmentStore())
+	c := &clock{now: time.Now().Add(time.Hour).Truncate(time.Second)}
+	s := NewService(list, tasks).(*service)
+	s.now = c.Now
+	return s, list, c
+}
+
+func raw(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+func update(id uuid.UUID, at time.Time, fields map[string]any) Mutation {
+	m := Mutation{ID: uuid.NewString(), Op: OpUpdate, TodoID: id, At: at, Fields: make(map[string]json.RawMessage)}
+	for name, value := range fields {
+		m.Fields[name] = raw(value)
+	}
+	return m
+}
+
+func syncAs(t *testing.T, s *service, req SyncRequest) SyncResponse {
+	t.Helper()
+	if req.Now.IsZero() {
+		req.Now = s.now()
+	}
+	res, err := s.Sync(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	return res
+}
+
+func statuses(res SyncResponse) []string {
+	out := make([]string, len(res.Results))
+	for i, result := range res.Results {
+		out[i] = result.Status
+	}
+	return out
+}
+
+func Test_service_Sync_concurrentEdits(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Buy milk")
+	syncAs(t, s, SyncRequest{ClientID: "a"})
+	editedAt := c.now.Add(time.Minute)
+	c.now = c.now.Add(time.Hour)
+
+	// both clients edited offline, the phone a minute before the laptop
+	laptop := syncAs(t, s, SyncRequest{ClientID: "laptop", Mutations: []Mutation{
+		update(todo.ID, editedAt.Add(time.Minute), map[string]any{"description": "Buy oat milk"}),
+	}})
+	phone := syncAs(t, s, SyncRequest{ClientID: "phone", Mutations: []Mutation{
+		update(todo.ID, editedAt, map[string]any{"description": "Buy milk and eggs", "completed": true}),
+	}})
+
+	if got := statuses(laptop); got[0] != StatusApplied {
+		t.Errorf("laptop mutation = %v, want applied", got)
+	}
+	if got := phone.Results[0]; got.Status != StatusApplied || len(got.Ignored) != 1 || got.Ignored[0] != "description" {
+		t.Errorf("phone mutation = %+v, want applied with the description ignored", got)
+	}
+	// the laptop's later description wins though it arrived first, and the phone's completion is kept
+	if todo = list.Get(todo.ID); todo.Description != "Buy oat milk" || !todo.Completed {
+		t.Errorf("todo = %q completed %v, want %q completed", todo.Description, todo.Completed, "Buy oat milk")
+	}
+}
+
+func Test_service_Sync_clockSkew(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Call the plumber")
+	syncAs(t, s, SyncRequest{ClientID: "a"})
+	c.now = c.now.Add(time.Hour)
+
+	// the slow client's clock is two hours behind, so its edit made ten minutes ago looks older than it is
+	slow := c.now.Add(-2 * time.Hour)
+	syncAs(t, s, SyncRequest{ClientID: "fast", Mutations: []Mutation{
+		update(todo.ID, c.now.Add(-20*time.Minute), map[string]any{"priority": "high"}),
+	}})
+	res := syncAs(t, s, SyncRequest{ClientID: "slow", Now: slow, Mutations: []Mutation{
+		update(todo.ID, slow.Add(-10*time.Minute), map[string]any{"priority": "low"}),
+	}})
+
+	if got := statuses(res); got[0] != StatusApplied {
+		t.Errorf("slow mutation = %v, want applied", got)
+	}
+	if todo = list.Get(todo.ID); todo.Priority != domain.PriorityLow {
+		t.Errorf("priority = %v, want low", todo.Priority)
+	}
+
+	// a clock running ahead cannot put a write past the server's now
+	ahead := c.now.Add(24 * time.Hour)
+	syncAs(t, s, SyncRequest{ClientID: "ahead", Now: c.now, Mutations: []Mutation{
+		update(todo.ID, ahead, map[string]any{"category": "home"}),
+	}})
+	c.now = c.now.Add(time.Second)
+	syncAs(t, s, SyncRequest{ClientID: "a", Mutations: []Mutation{
+		update(todo.ID, c.now, map[string]any{"category": "work"}),
+	}})
+	if todo = list.Get(todo.ID); todo.Category != "work" {
+		t.Errorf("category = %q, want work", todo.Category)
+	}
+}
+
+func Test_stamp(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		at, clientNow time.Time
+		want          time.Time
+	}{
+		"on time":             {at: now.Add(-time.Minute), clientNow: now, want: now.Add(-time.Minute)},
+		"clock behind":        {at: now.Add(-2*time.Hour - time.Minute), clientNow: now.Add(-2 * time.Hour), want: now.Add(-time.Minute)},
+		"made later than now": {at: now.Add(time.Hour), clientNow: now, want: now},
+		"no time":             {clientNow: now, want: now},
+		"no now":              {at: now.Add(-time.Minute), want: now},
+		"clock too far off":   {at: now.Add(-2*maxSkew - time.Minute), clientNow: now.Add(-2 * maxSkew), want: now},
+		"older than the journal remembers": {
+			at: now.Add(-2 * tombstoneTTL), clientNow: now, want: now.Add(-tombstoneTTL),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := stamp(tc.at, tc.clientNow, now); !got.Equal(tc.want) {
+				t.Errorf("stamp() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_service_Sync_ties(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Water the plants")
+	syncAs(t, s, SyncRequest{ClientID: "a"})
+	at := c.now.Add(time.Minute)
+	c.now = c.now.Add(time.Hour)
+
+	// writes made at the same time go to the greater client ID whichever arrives first
+	syncAs(t, s, SyncRequest{ClientID: "b", Mutations: []Mutation{update(todo.ID, at, map[string]any{"category": "b"})}})
+	res := syncAs(t, s, SyncRequest{ClientID: "a", Mutations: []Mutation{update(todo.ID, at, map[string]any{"category": "a"})}})
+	if got := statuses(res); got[0] != StatusStale {
+		t.Errorf("mutation = %v, want stale", got)
+	}
+	if todo = list.Get(todo.ID); todo.Category != "b" {
+		t.Errorf("category = %q, want b", todo.Category)
+	}
+}
+
+func Test_service_Sync_serverEdits(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Renew the passport")
+	syncAs(t, s, SyncRequest{ClientID: "a"})
+	offlineAt := c.now.Add(time.Minute)
+
+	// the todo is completed on the web pages after the client made its edit offline
+	todo = list.Update(todo.ID, true, "Renew the passport today")
+	todo.UpdatedAt = offlineAt.Add(time.Minute)
+	list.Save(todo)
+	c.now = c.now.Add(time.Hour)
+
+	res := syncAs(t, s, SyncRequest{ClientID: "a", Mutations: []Mutation{
+		update(todo.ID, offlineAt, map[string]any{"description": "Renew the passport soon", "tags": []string{"errands"}}),
+	}})
+	if got := res.Results[0]; got.Status != StatusApplied || len(got.Ignored) != 1 || got.Ignored[0] != "description" {
+		t.Errorf("mutation = %+v, want applied with the description ignored", got)
+	}
+	if todo = list.Get(todo.ID); todo.Description != "Renew the passport today" || len(todo.Tags) != 1 {
+		t.Errorf("todo = %q tags %v, want the server's description with the client's tags", todo.Description, todo.Tags)
+	}
+}
+
+func Test_service_Sync_deltas(t *testing.T) {
+	s, list, c := newTestService(t)
+	first := list.Add("First")
+	second := list.Add("Second")
+
+	all := syncAs(t, s, SyncRequest{ClientID: "a"})
+	if !all.Reset || len(all.Todos) != 2 || all.Todos[0].ID != first.ID {
+		t.Fatalf("first sync = reset %v with %d todos, want reset with both in order", all.Reset, len(all.Todos))
+	}
+
+	none := syncAs(t, s, SyncRequest{ClientID: "a", Token: all.Token})
+	if none.Reset || len(none.Todos) != 0 || len(none.Deleted) != 0 {
+		t.Errorf("sync with nothing changed = %+v, want nothing", none)
+	}
+
+	c.now = c.now.Add(time.Minute)
+	list.Update(second.ID, true, "Second")
+	created := uuid.New()
+	res := syncAs(t, s, SyncRequest{ClientID: "a", Token: none.Token, Mutations: []Mutation{
+		{ID: "1", Op: OpCreate, TodoID: created, At: c.now, Fields: map[string]json.RawMessage{"description": raw("Third")}},
+	}})
+	if got := statuses(res); got[0] != StatusApplied {
+		t.Fatalf("create = %v, want applied", got)
+	}
+	if res.Reset || len(res.Todos) != 2 || res.Todos[0].ID != second.ID || res.Todos[1].ID != created {
+		t.Errorf("delta = %+v, want the second todo and the one created", res.Todos)
+	}
+	if todo := list.Get(created); todo == nil || todo.Description != "Third" {
+		t.Errorf("created todo = %+v, want it kept with the client's ID", todo)
+	}
+
+	// a token from before the server restarted starts the client over
+	other := NewService(list, nil).(*service)
+	if res := syncAs(t, other, SyncRequest{ClientID: "a", Token: res.Token}); !res.Reset || len(res.Todos) != 3 {
+		t.Errorf("sync with another journal's token = reset %v with %d todos, want reset with all", res.Reset, len(res.Todos))
+	}
+}
+
+func Test_service_Sync_tombstones(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Cancel the gym")
+	kept := list.Add("Keep this")
+	laptop := syncAs(t, s, SyncRequest{ClientID: "laptop"})
+	phone := syncAs(t, s, SyncRequest{ClientID: "phone"})
+	c.now = c.now.Add(time.Minute)
+
+	laptop = syncAs(t, s, SyncRequest{ClientID: "laptop", Token: laptop.Token, Mutations: []Mutation{
+		{ID: "1", Op: OpDelete, TodoID: todo.ID, At: c.now},
+	}})
+	if list.Get(todo.ID) != nil {
+		t.Fatalf("the todo was not deleted")
+	}
+
+	// the phone edited the todo later, but deletions win
+	res := syncAs(t, s, SyncRequest{ClientID: "phone", Token: phone.Token, Mutations: []Mutation{
+		update(todo.ID, c.now.Add(time.Second), map[string]any{"completed": true}),
+	}})
+	if got := statuses(res); got[0] != StatusDeleted {
+		t.Errorf("update of a deleted todo = %v, want deleted", got)
+	}
+	if len(res.Deleted) != 1 || res.Deleted[0] != todo.ID {
+		t.Errorf("deleted = %v, want %v", res.Deleted, todo.ID)
+	}
+
+	// todos removed on the server leave tombstones too
+	list.Remove(kept.ID)
+	res = syncAs(t, s, SyncRequest{ClientID: "laptop", Token: laptop.Token})
+	if len(res.Deleted) != 1 || res.Deleted[0] != kept.ID {
+		t.Errorf("deleted = %v, want %v", res.Deleted, kept.ID)
+	}
+
+	// once the tombstones are forgotten, clients that have not seen them start over
+	c.now = c.now.Add(tombstoneTTL + time.Hour)
+	res = syncAs(t, s, SyncRequest{ClientID: "phone", Token: phone.Token})
+	if !res.Reset || len(res.Todos) != 0 || len(res.Deleted) != 0 {
+		t.Errorf("sync past the forgotten tombstones = %+v, want a reset with no todos", res)
+	}
+}
+
+func Test_service_Sync_rejects(t *testing.T) {
+	s, list, c := newTestService(t)
+	todo := list.Add("Pay rent")
+	if _, err := s.Sync(context.Background(), SyncRequest{}); err != ErrInvalidInput {
+		t.Errorf("Sync() without a client ID error = %v, want %v", err, ErrInvalidInput)
+	}
+
+	res := syncAs(t, s, SyncRequest{ClientID: "a", Mutations: []Mutation{
+		update(todo.ID, c.now, map[string]any{"description": "Pay the rent", "priority": "whenever"}),
+		update(todo.ID, c.now, map[string]any{"owner": "me"}),
+		update(uuid.New(), c.now, map[string]any{"completed": true}),
+		{ID: "4", Op: OpCreate, TodoID: uuid.New(), At: c.now},
+	}})
+	for i, result := range res.Results {
+		if result.Status != StatusRejected || result.Error == "" {
+			t.Errorf("mutation %d = %+v, want rejected", i, result)
+		}
+	}
+	if todo.Description != "Pay rent" {
+		t.Errorf("description = %q, want the mutation with a bad priority left out whole", todo.Description)
+	}
+}
+
+// saves records the todos saved to the repository it wraps, as they were saved
+type saves struct {
+	*domain.Todos
+	saved []domain.Todo
+}
+
+func (r *saves) Save(todos ...*domain.Todo) {
+	for _, todo := range todos {
+		r.saved = append(r.saved, *todo)
+	}
+	r.Todos.Save(todos...)
+}
+
+func Test_service_Sync_create(t *testing.T) {
+	s, list, c := newTestService(t)
+	repo := &saves{Todos: list}
+	s.todos = repo
+
+	created := uuid.New()
+	res := syncAs(t, s, SyncRequest{ClientID: "a", Mutations: []Mutation{{
+		ID: "1", Op: OpCreate, TodoID: created, At: c.now,
+		Fields: map[string]json.RawMessage{"description": raw("Buy milk"), "priority": raw("high"), "tags": raw([]string{"errands"})},
+	}}})
+	if got := statuses(res); got[0] != StatusApplied {
+		t.Fatalf("create = %v, want applied", got)
+	}
+
+	// the todo is saved once, whole, so no one sees it without its description
+	if len(repo.saved) != 1 || repo.saved[0].Description != "Buy milk" || repo.saved[0].Priority != domain.PriorityHigh {
+		t.Fatalf("saved = %+v, want the whole todo saved once", repo.saved)
+	}
+	if todo := list.Get(created); todo == nil || len(todo.Tags) != 1 {
+		t.Errorf("Get() = %+v, want the todo with its tags", todo)
+	}
+
+	// an edit another client dates before the create loses to it
+	res = syncAs(t, s, SyncRequest{ClientID: "b", Mutations: []Mutation{
+		update(created, c.now.Add(-time.Minute), map[string]any{"description": "Buy oat milk"}),
+	}})
+	if got := statuses(res); got[0] != StatusStale {
+		t.Errorf("older edit = %v, want stale", got)
+	}
+}
@@ -0,0 +1,45 @@
+package timetracking
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader is the header written by EncodeCSV
+var csvHeader = []string{
+	"entry_id", "todo_id", "todo", "category", "user_id", "user", "start", "end", "minutes", "note",
+}
+
+// EncodeCSV writes the time entries as CSV rows, counting running timers up to now and leaving their end blank
+func EncodeCSV(w io.Writer, entries []Entry, now time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var end string
+		if entry.End != nil {
+			end = entry.End.Format(time.RFC3339)
+		}
+		minutes := strconv.FormatFloat(entry.Duration(now).Minutes(), 'f', 2, 64)
+		row := []string{
+			entry.ID.String(),
+			entry.Todo.ID.String(),
+			entry.Todo.PlainDescription(),
+			entry.Todo.Category,
+			entry.UserID.String(),
+			UserName(entry.User, entry.UserID),
+			entry.Start.Format(time.RFC3339),
+			end,
+			minutes,
+			entry.Note,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
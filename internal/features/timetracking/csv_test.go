@@ -0,0 +1,41 @@
+package timetracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	todo := domain.NewTodo("Bake a **cake**")
+	todo.ID = uuid.MustParse("6f9619ff-8b86-d011-b42d-00c04fc964ff")
+	todo.Category = "Cooking"
+	user := &domain.User{ID: uuid.MustParse("0e8400e2-9b29-41d4-a716-446655440000"), Username: "alice"}
+
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	finished := domain.TimeEntry{ID: uuid.MustParse("a50e8400-e29b-41d4-a716-446655440000"), UserID: user.ID, Start: start, End: &end, Note: "icing, mostly"}
+	running := domain.TimeEntry{ID: uuid.MustParse("b50e8400-e29b-41d4-a716-446655440000"), UserID: user.ID, Start: end}
+
+	var b strings.Builder
+	err := EncodeCSV(&b, []Entry{
+		{TimeEntry: finished, Todo: todo, User: user},
+		{TimeEntry: running, Todo: todo},
+	}, end.Add(15*time.Minute))
+	if err != nil {
+		t.Fatalf("EncodeCSV() error = %v", err)
+	}
+
+	want := "entry_id,todo_id,todo,category,user_id,user,start,end,minutes,note\n" +
+		"a50e8400-e29b-41d4-a716-446655440000,6f9619ff-8b86-d011-b42d-00c04fc964ff,Bake a cake,Cooking," +
+		"0e8400e2-9b29-41d4-a716-446655440000,alice,2024-03-04T09:00:00Z,2024-03-04T10:30:00Z,90.00,\"icing, mostly\"\n" +
+		"b50e8400-e29b-41d4-a716-446655440000,6f9619ff-8b86-d011-b42d-00c04fc964ff,Bake a cake,Cooking," +
+		"0e8400e2-9b29-41d4-a716-446655440000,User 0e8400e2,2024-03-04T10:30:00Z,,15.00,\n"
+	if got := b.String(); got != want {
+		t.Errorf("EncodeCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
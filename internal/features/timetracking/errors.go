@@ -0,0 +1,18 @@
+package timetracking
+
+import (
+	"errors"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+var (
+	ErrTodoNotFound      = domain.ErrTodoNotFound
+	ErrEntryNotFound     = domain.ErrEntryNotFound
+	ErrTimerNotRunning   = errors.New("no timer is running on the todo")
+	ErrInvalidEntry      = errors.New("a time entry must end after it starts")
+	ErrInvalidEstimate   = errors.New("the estimate is not a duration, such as 90m or 1h30m")
+	ErrInvalidDateRange  = errors.New("the date range must end after it starts")
+	ErrPermissionDenied  = domain.ErrPermissionDenied
+	ErrAnonymousTracking = errors.New("time can only be tracked with a user ID")
+)
@@ -0,0 +1,303 @@
+package timetracking
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+type (
+	Handler interface {
+		// StartTimer : POST /todos/{todoId}/timer/start
+		StartTimer(w http.ResponseWriter, r *http.Request)
+		// StopTimer : POST /todos/{todoId}/timer/stop
+		StopTimer(w http.ResponseWriter, r *http.Request)
+		// AddEntry : POST /todos/{todoId}/time
+		AddEntry(w http.ResponseWriter, r *http.Request)
+		// DeleteEntry : DELETE /todos/{todoId}/time/{entryId}
+		// DeleteEntry : POST /todos/{todoId}/time/{entryId}/delete
+		DeleteEntry(w http.ResponseWriter, r *http.Request)
+		// SetEstimate : POST /todos/{todoId}/estimate
+		SetEstimate(w http.ResponseWriter, r *http.Request)
+		// Report : GET /time
+		Report(w http.ResponseWriter, r *http.Request)
+		// Export : GET /time/export.csv
+		Export(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// viewTodo is sent by the forms on the todo page, which want the time section back rather than the todo in the list
+	viewTodo = "todo"
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/todos/{todoId}/timer", func(r chi.Router) {
+		r.Post("/start", h.StartTimer)
+		r.Post("/stop", h.StopTimer)
+	})
+	r.Route("/todos/{todoId}/time", func(r chi.Router) {
+		r.Post("/", h.AddEntry)
+		r.Delete("/{entryId}", h.DeleteEntry)
+		r.Post("/{entryId}/delete", h.DeleteEntry)
+	})
+	r.Post("/todos/{todoId}/estimate", h.SetEstimate)
+	r.Route("/time", func(r chi.Router) {
+		r.Get("/", h.Report)
+		r.Get("/export.csv", h.Export)
+	})
+}
+
+func (h handler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stopped, err := h.service.StartTimer(r.Context(), todoID, identity.UserID(r.Context()))
+	if err != nil {
+		timeError(w, err)
+		return
+	}
+
+	// the todo whose timer was stopped is shown elsewhere on the page, so all of it is out of date
+	if stopped != nil && isHTMX(r) {
+		w.Header().Set("HX-Refresh", "true")
+	}
+	h.render(w, r, todoID)
+}
+
+func (h handler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.StopTimer(r.Context(), todoID, identity.UserID(r.Context())); err != nil {
+		timeError(w, err)
+		return
+	}
+
+	h.render(w, r, todoID)
+}
+
+func (h handler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start, err := time.ParseInLocation(dateLayout+" 15:04", r.Form.Get("date")+" "+r.Form.Get("start"), time.Local)
+	if err != nil {
+		http.Error(w, "the start must be a date and a time of day", http.StatusBadRequest)
+		return
+	}
+	duration, err := ParseDuration(r.Form.Get("duration"))
+	if err != nil {
+		timeError(w, err)
+		return
+	}
+
+	if err = h.service.AddEntry(r.Context(), todoID, identity.UserID(r.Context()), start, start.Add(duration), r.Form.Get("note")); err != nil {
+		timeError(w, err)
+		return
+	}
+
+	h.render(w, r, todoID)
+}
+
+func (h handler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entryID, err := uuid.Parse(chi.URLParam(r, "entryId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.DeleteEntry(r.Context(), todoID, entryID, identity.UserID(r.Context())); err != nil {
+		timeError(w, err)
+		return
+	}
+
+	h.render(w, r, todoID)
+}
+
+func (h handler) SetEstimate(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.SetEstimate(r.Context(), todoID, r.Form.Get("estimate")); err != nil {
+		timeError(w, err)
+		return
+	}
+
+	h.render(w, r, todoID)
+}
+
+func (h handler) Report(w http.ResponseWriter, r *http.Request) {
+	from, to, err := dateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), from, to)
+	if err != nil {
+		timeError(w, err)
+		return
+	}
+
+	page := pages.TimeReportPage(report.From, report.To.AddDate(0, 0, -1), report.Total,
+		timeTotals(report.ByTodo), timeTotals(report.ByCategory), timeTotals(report.ByUser))
+	if err = page.Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) Export(w http.ResponseWriter, r *http.Request) {
+	from, to, err := dateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.Entries(r.Context(), from, to)
+	if err != nil {
+		timeError(w, err)
+		return
+	}
+
+	filename := "time-" + from.Format(dateLayout) + "-to-" + to.AddDate(0, 0, -1).Format(dateLayout) + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err = EncodeCSV(w, entries, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// render responds with the todo as it appears where the request came from, or sends the browser back there
+func (h handler) render(w http.ResponseWriter, r *http.Request, todoID uuid.UUID) {
+	fromTodoPage := r.FormValue("view") == viewTodo
+	if !isHTMX(r) {
+		switch fromTodoPage {
+		case true:
+			http.Redirect(w, r, "/todos/"+todoID.String()+"#time", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/", http.StatusFound)
+		}
+		return
+	}
+
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		timeError(w, err)
+		return
+	}
+
+	switch fromTodoPage {
+	case true:
+		var users []*domain.User
+		if users, err = h.service.Users(r.Context()); err != nil {
+			break
+		}
+		viewer := partials.Viewer{UserID: identity.UserID(r.Context()), Users: users}
+		err = partials.TimeTracking(todo, viewer).Render(r.Context(), w)
+	default:
+		err = partials.RenderTodo(todo).Render(r.Context(), w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func timeTotals(totals []Total) []partials.TimeTotal {
+	lines := make([]partials.TimeTotal, len(totals))
+	for i, total := range totals {
+		lines[i] = partials.TimeTotal{Label: total.Label, Todo: total.Todo, Duration: total.Duration}
+	}
+	return lines
+}
+
+// dateRange reads the days from and to, both included, defaulting to the last seven days
+//
+// The range returned ends at the start of the day after the last one.
+func dateRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from, to := today.AddDate(0, 0, -6), today
+
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.ParseInLocation(dateLayout, v, time.Local); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = time.ParseInLocation(dateLayout, v, time.Local); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func timeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound), errors.Is(err, ErrEntryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrAnonymousTracking):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrTimerNotRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrInvalidEntry), errors.Is(err, ErrInvalidEstimate), errors.Is(err, ErrInvalidDateRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,261 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// Get returns the todo the time is tracked against
+		Get(ctx context.Context, todoID uuid.UUID) (*domain.Todo, error)
+		// StartTimer starts a timer for the user on the todo, stopping the one they had running on any other todo
+		//
+		// The todo whose timer was stopped is returned, or nil when no other timer was running.
+		StartTimer(ctx context.Context, todoID, userID uuid.UUID) (stopped *domain.Todo, err error)
+		// StopTimer stops the timer the user has running on the todo
+		StopTimer(ctx context.Context, todoID, userID uuid.UUID) error
+		// AddEntry records time the user spent on the todo
+		AddEntry(ctx context.Context, todoID, userID uuid.UUID, start, end time.Time, note string) error
+		// DeleteEntry removes a time entry the user made
+		DeleteEntry(ctx context.Context, todoID, entryID, userID uuid.UUID) error
+		// SetEstimate sets how long the todo is expected to take from text such as 90m, 1h30m or 2h
+		SetEstimate(ctx context.Context, todoID uuid.UUID, estimate string) error
+		// Entries returns the time entries started within the range, oldest first
+		Entries(ctx context.Context, from, to time.Time) ([]Entry, error)
+		// Report totals the time entries started within the range
+		Report(ctx context.Context, from, to time.Time) (*Report, error)
+		// Users returns the users who may have tracked time
+		Users(ctx context.Context) ([]*domain.User, error)
+	}
+
+	// Entry is a time entry along with the todo and user it belongs to
+	Entry struct {
+		domain.TimeEntry
+		Todo *domain.Todo
+		// User is nil when the user has no profile
+		User *domain.User
+	}
+
+	// Total is the time spent on one todo, in one category or by one user
+	Total struct {
+		Label string
+		// Todo is set for the totals of todos
+		Todo     *domain.Todo
+		Duration time.Duration
+	}
+
+	Report struct {
+		From, To   time.Time
+		Total      time.Duration
+		ByTodo     []Total
+		ByCategory []Total
+		ByUser     []Total
+	}
+
+	service struct {
+		todos domain.TodoRepository
+		users domain.UserRepository
+		now   func() time.Time
+		// timers makes stopping one timer and starting another a single step
+		timers sync.Mutex
+	}
+)
+
+func NewService(todos domain.TodoRepository, users domain.UserRepository) Service {
+	return &service{
+		todos: todos,
+		users: users,
+		now:   time.Now,
+	}
+}
+
+func (s *service) Get(_ context.Context, todoID uuid.UUID) (*domain.Todo, error) {
+	todo := s.todos.Get(todoID)
+	if todo == nil {
+		return nil, ErrTodoNotFound
+	}
+
+	return todo, nil
+}
+
+func (s *service) StartTimer(_ context.Context, todoID, userID uuid.UUID) (*domain.Todo, error) {
+	if userID == uuid.Nil {
+		return nil, ErrAnonymousTracking
+	}
+	if s.todos.Get(todoID) == nil {
+		return nil, ErrTodoNotFound
+	}
+
+	s.timers.Lock()
+	defer s.timers.Unlock()
+
+	var stopped *domain.Todo
+	for _, other := range s.todos.All() {
+		if other.ID == todoID || other.RunningTimer(userID) == nil {
+			continue
+		}
+		if todo, err := s.todos.Change(other.ID, domain.StopTimer{UserID: userID}); err == nil {
+			stopped = todo
+		}
+	}
+	if _, err := s.todos.Change(todoID, domain.StartTimer{EntryID: uuid.New(), UserID: userID}); err != nil {
+		return nil, err
+	}
+	return stopped, nil
+}
+
+func (s *service) StopTimer(_ context.Context, todoID, userID uuid.UUID) error {
+	s.timers.Lock()
+	defer s.timers.Unlock()
+
+	_, err := s.todos.Change(todoID, domain.StopTimer{UserID: userID})
+	if errors.Is(err, domain.ErrUnchanged) {
+		return ErrTimerNotRunning
+	}
+	return err
+}
+
+func (s *service) AddEntry(_ context.Context, todoID, userID uuid.UUID, start, end time.Time, note string) error {
+	if userID == uuid.Nil {
+		return ErrAnonymousTracking
+	}
+	if !end.After(start) {
+		return ErrInvalidEntry
+	}
+
+	entry := domain.TimeEntry{ID: uuid.New(), UserID: userID, Start: start, End: &end, Note: strings.TrimSpace(note)}
+	_, err := s.todos.Change(todoID, domain.AddTimeEntry{Entry: entry})
+	return err
+}
+
+func (s *service) DeleteEntry(_ context.Context, todoID, entryID, userID uuid.UUID) error {
+	_, err := s.todos.Change(todoID, domain.RemoveTimeEntry{EntryID: entryID, UserID: userID})
+	return err
+}
+
+func (s *service) SetEstimate(_ context.Context, todoID uuid.UUID, estimate string) error {
+	if s.todos.Get(todoID) == nil {
+		return ErrTodoNotFound
+	}
+	duration, err := ParseDuration(estimate)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.todos.Change(todoID, domain.SetEstimate{Estimate: duration})
+	return err
+}
+
+func (s *service) Entries(_ context.Context, from, to time.Time) ([]Entry, error) {
+	if !to.After(from) {
+		return nil, ErrInvalidDateRange
+	}
+
+	var entries []Entry
+	for _, todo := range s.todos.All() {
+		for _, entry := range todo.TimeEntries {
+			if entry.Start.Before(from) || !entry.Start.Before(to) {
+				continue
+			}
+			entries = append(entries, Entry{TimeEntry: entry, Todo: todo, User: s.users.Get(entry.UserID)})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Start.Before(entries[j].Start)
+	})
+	return entries, nil
+}
+
+func (s *service) Report(ctx context.Context, from, to time.Time) (*Report, error) {
+	entries, err := s.Entries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report := &Report{From: from, To: to}
+	byTodo := make(map[uuid.UUID]*Total)
+	byCategory := make(map[string]*Total)
+	byUser := make(map[uuid.UUID]*Total)
+	for _, entry := range entries {
+		duration := entry.Duration(now)
+		report.Total += duration
+
+		if byTodo[entry.Todo.ID] == nil {
+			byTodo[entry.Todo.ID] = &Total{Label: entry.Todo.PlainDescription(), Todo: entry.Todo}
+		}
+		byTodo[entry.Todo.ID].Duration += duration
+
+		category := entry.Todo.Category
+		if category == "" {
+			category = "No category"
+		}
+		if byCategory[category] == nil {
+			byCategory[category] = &Total{Label: category}
+		}
+		byCategory[category].Duration += duration
+
+		if byUser[entry.UserID] == nil {
+			byUser[entry.UserID] = &Total{Label: UserName(entry.User, entry.UserID)}
+		}
+		byUser[entry.UserID].Duration += duration
+	}
+
+	report.ByTodo = sortedTotals(byTodo)
+	report.ByCategory = sortedTotals(byCategory)
+	report.ByUser = sortedTotals(byUser)
+	return report, nil
+}
+
+func (s *service) Users(_ context.Context) ([]*domain.User, error) {
+	return s.users.All(), nil
+}
+
+// UserName returns how a user is named in reports, whether or not they have a profile
+func UserName(user *domain.User, userID uuid.UUID) string {
+	if user != nil {
+		return user.DisplayName()
+	}
+	return "User " + userID.String()[:8]
+}
+
+// sortedTotals orders the totals with the most time first
+func sortedTotals[K comparable](totals map[K]*Total) []Total {
+	list := make([]Total, 0, len(totals))
+	for _, total := range totals {
+		list = append(list, *total)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Duration != list[j].Duration {
+			return list[i].Duration > list[j].Duration
+		}
+		return list[i].Label < list[j].Label
+	})
+	return list
+}
+
+// ParseDuration reads a duration such as 90m, 1h30m or 1.5h; a bare number is minutes and blank is no time at all
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "")
+	if s == "" {
+		return 0, nil
+	}
+	if minutes, err := strconv.ParseFloat(s, 64); err == nil {
+		s = strconv.FormatFloat(minutes, 'f', -1, 64) + "m"
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil || duration < 0 {
+		return 0, ErrInvalidEstimate
+	}
+	return duration.Round(time.Minute), nil
+}
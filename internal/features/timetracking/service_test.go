@@ -0,0 +1,154 @@
+package timetracking
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service_StartTimer(t *testing.T) {
+	list := domain.NewTodos()
+	report, review := list.Add("Write the report"), list.Add("Review the report")
+	s := NewService(list, domain.NewUsers())
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	if stopped, err := s.StartTimer(ctx, report.ID, alice); err != nil || stopped != nil {
+		t.Fatalf("StartTimer() = %v, %v, want nothing stopped", stopped, err)
+	}
+	if _, err := s.StartTimer(ctx, review.ID, bob); err != nil {
+		t.Fatalf("StartTimer() error = %v", err)
+	}
+
+	// starting another timer stops the one alice had running, but not the one bob has
+	stopped, err := s.StartTimer(ctx, review.ID, alice)
+	if err != nil || stopped == nil || stopped.ID != report.ID {
+		t.Fatalf("StartTimer() = %v, %v, want the report stopped", stopped, err)
+	}
+	report, review = list.Get(report.ID), list.Get(review.ID)
+	if report.RunningTimer(alice) != nil || review.RunningTimer(alice) == nil || review.RunningTimer(bob) == nil {
+		t.Errorf("running timers are wrong after alice switched todos")
+	}
+	if len(report.TimeEntries) != 1 || report.TimeEntries[0].End == nil {
+		t.Errorf("TimeEntries = %v, want the stopped timer", report.TimeEntries)
+	}
+
+	if err = s.StopTimer(ctx, report.ID, alice); !errors.Is(err, ErrTimerNotRunning) {
+		t.Errorf("StopTimer() error = %v, want %v", err, ErrTimerNotRunning)
+	}
+	if err = s.StopTimer(ctx, uuid.New(), alice); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("StopTimer() error = %v, want %v", err, ErrTodoNotFound)
+	}
+	if _, err = s.StartTimer(ctx, report.ID, uuid.Nil); !errors.Is(err, ErrAnonymousTracking) {
+		t.Errorf("StartTimer() error = %v, want %v", err, ErrAnonymousTracking)
+	}
+}
+
+func Test_service_DeleteEntry(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Write the report")
+	s := NewService(list, domain.NewUsers())
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+	start := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	if err := s.AddEntry(ctx, todo.ID, alice, start, start.Add(time.Hour), " drafting "); err != nil {
+		t.Fatalf("AddEntry() error = %v", err)
+	}
+	entry := list.Get(todo.ID).TimeEntries[0]
+	if entry.Note != "drafting" || entry.Duration(start) != time.Hour {
+		t.Errorf("AddEntry() recorded %+v", entry)
+	}
+
+	if err := s.DeleteEntry(ctx, todo.ID, entry.ID, bob); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("DeleteEntry() error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if err := s.DeleteEntry(ctx, todo.ID, entry.ID, alice); err != nil {
+		t.Fatalf("DeleteEntry() error = %v", err)
+	}
+	if err := s.DeleteEntry(ctx, todo.ID, entry.ID, alice); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("DeleteEntry() error = %v, want %v", err, ErrEntryNotFound)
+	}
+}
+
+func Test_service_Report(t *testing.T) {
+	alice := &domain.User{ID: uuid.New(), Username: "alice", Name: "Alice"}
+	users := domain.NewUsers()
+	users.Save(alice)
+	bob := uuid.New()
+
+	cake := domain.NewTodo("Bake a **cake**")
+	cake.Category = "Cooking"
+	trash := domain.NewTodo("Take out the trash")
+
+	day := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	cake.AddTimeEntry(alice.ID, day.Add(9*time.Hour), day.Add(10*time.Hour), "")
+	cake.AddTimeEntry(bob, day.Add(11*time.Hour), day.Add(11*time.Hour+30*time.Minute), "")
+	trash.AddTimeEntry(alice.ID, day.Add(12*time.Hour), day.Add(12*time.Hour+15*time.Minute), "")
+	// outside the range
+	trash.AddTimeEntry(alice.ID, day.AddDate(0, 0, 1), day.AddDate(0, 0, 1).Add(time.Hour), "")
+	list := domain.NewTodos()
+	list.Save(cake, trash)
+
+	s := NewService(list, users)
+	report, err := s.Report(context.Background(), day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	if report.Total != 105*time.Minute {
+		t.Errorf("Total = %v, want 1h45m", report.Total)
+	}
+	assertTotals(t, "ByTodo", report.ByTodo, []Total{{Label: "Bake a cake", Duration: 90 * time.Minute}, {Label: "Take out the trash", Duration: 15 * time.Minute}})
+	assertTotals(t, "ByCategory", report.ByCategory, []Total{{Label: "Cooking", Duration: 90 * time.Minute}, {Label: "No category", Duration: 15 * time.Minute}})
+	assertTotals(t, "ByUser", report.ByUser, []Total{{Label: "Alice", Duration: 75 * time.Minute}, {Label: "User " + bob.String()[:8], Duration: 30 * time.Minute}})
+	if report.ByTodo[0].Todo.ID != cake.ID {
+		t.Errorf("ByTodo[0].Todo = %v, want the cake", report.ByTodo[0].Todo)
+	}
+
+	if _, err = s.Report(context.Background(), day, day); !errors.Is(err, ErrInvalidDateRange) {
+		t.Errorf("Report() error = %v, want %v", err, ErrInvalidDateRange)
+	}
+}
+
+func assertTotals(t *testing.T, name string, got, want []Total) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+	for i := range want {
+		if got[i].Label != want[i].Label || got[i].Duration != want[i].Duration {
+			t.Errorf("%s[%d] = %s %v, want %s %v", name, i, got[i].Label, got[i].Duration, want[i].Label, want[i].Duration)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]struct {
+		s       string
+		want    time.Duration
+		wantErr bool
+	}{
+		"Blank":          {s: " ", want: 0},
+		"Minutes":        {s: "90", want: 90 * time.Minute},
+		"Units":          {s: "1h 30m", want: 90 * time.Minute},
+		"Fraction":       {s: "1.5h", want: 90 * time.Minute},
+		"UpperCase":      {s: "2H", want: 2 * time.Hour},
+		"RoundsToMinute": {s: "10m40s", want: 11 * time.Minute},
+		"Negative":       {s: "-5m", wantErr: true},
+		"Words":          {s: "soon", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseDuration(tt.s)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseDuration(%q) = %v, %v, want %v (error %v)", tt.s, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package todos
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	// TodoResponse is a todo as the API returns it
+	TodoResponse struct {
+		ID          uuid.UUID  `json:"id"`
+		Description string     `json:"description"`
+		Completed   bool       `json:"completed"`
+		Priority    string     `json:"priority"`
+		Category    string     `json:"category,omitempty"`
+		Tags        []string   `json:"tags"`
+		DueDate     *time.Time `json:"dueDate,omitempty"`
+		AssignedTo  *uuid.UUID `json:"assignedTo,omitempty"`
+		Archived    bool       `json:"archived"`
+		CreatedAt   time.Time  `json:"createdAt"`
+		UpdatedAt   time.Time  `json:"updatedAt"`
+		Version     int        `json:"version"`
+		URL         string     `json:"url"`
+	}
+
+	// UpdateTodoRequest is the change PUT makes to a todo
+	UpdateTodoRequest struct {
+		Description string `json:"description"`
+		Completed   bool   `json:"completed"`
+	}
+
+	// PageResponse is one page of todos; Next is the cursor of the page after it
+	PageResponse struct {
+		Todos []TodoResponse `json:"todos"`
+		Next  string         `json:"next,omitempty"`
+	}
+)
+
+// ListJSON lists the todos matching the query parameters read by ParseQuery a page at a time
+//
+// The sort parameter takes sort keys such as "priority,-due"; cursor is the next cursor of the page
+// before, and limit is how many todos a page holds. The next page is also linked in the Link header.
+// The as_of parameter lists the todos as they were at an RFC 3339 time, or at the end of a day.
+func (h handler) ListJSON(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query, err := ParseQuery(values)
+	if err != nil {
+		listError(w, err)
+		return
+	}
+	order, ok := domain.ParseSortOrder(values.Get("sort"))
+	if !ok {
+		listError(w, ErrInvalidSort)
+		return
+	}
+	req := domain.PageRequest{Sort: order, Cursor: values.Get("cursor")}
+	if v := values.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil || req.Limit < 1 {
+			listError(w, ErrInvalidInput)
+			return
+		}
+	}
+
+	var page domain.Page
+	switch v := values.Get("as_of"); v {
+	case "":
+		page, err = h.service.List(r.Context(), query, req)
+	default:
+		var at time.Time
+		if at, err = parseAsOf(v); err != nil {
+			listError(w, err)
+			return
+		}
+		page, err = h.service.ListAt(r.Context(), at, query, req)
+	}
+	if err != nil {
+		listError(w, err)
+		return
+	}
+
+	res := PageResponse{Todos: make([]TodoResponse, len(page.Todos)), Next: page.Next}
+	for i, todo := range page.Todos {
+		res.Todos[i] = NewTodoResponse(todo)
+	}
+	if page.Next != "" {
+		values.Set("cursor", page.Next)
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+values.Encode()+`>; rel="next"`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// GetJSON returns a todo with its version as the ETag, or 304 Not Modified when If-None-Match already holds it
+func (h handler) GetJSON(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	if todo == nil {
+		updateError(w, ErrTodoNotFound)
+		return
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), todo) {
+		w.Header().Set("ETag", etag(todo))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeTodo(w, http.StatusOK, todo)
+}
+
+// UpdateJSON changes the description and completion of a todo
+//
+// When the If-Match header is sent and does not hold the ETag of the todo as it is now, nothing is changed
+// and 412 Precondition Failed is returned along with the todo as it is now, so the client can merge the two.
+func (h handler) UpdateJSON(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req UpdateTodoRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Description) == "" {
+		http.Error(w, ErrInvalidInput.Error(), http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	if todo == nil {
+		updateError(w, ErrTodoNotFound)
+		return
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !etagMatches(ifMatch, todo) {
+		writeTodo(w, http.StatusPreconditionFailed, todo)
+		return
+	}
+
+	// the version read above is checked again as the change is saved, in case the todo changed in between
+	updated, err := h.service.UpdateVersion(r.Context(), todoID, todo.Version, req.Completed, req.Description)
+	if errors.Is(err, ErrVersionConflict) {
+		current, _ := h.service.Get(r.Context(), todoID)
+		if current != nil {
+			writeTodo(w, http.StatusPreconditionFailed, current)
+			return
+		}
+	}
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	writeTodo(w, http.StatusOK, updated)
+}
+
+// parseAsOf reads a time as RFC 3339, or a day as the end of it
+func parseAsOf(v string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, v); err == nil {
+		return at, nil
+	}
+	day, err := time.ParseInLocation(queryDate, v, time.Local)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// NewTodoResponse returns the todo as the API returns it
+func NewTodoResponse(todo *domain.Todo) TodoResponse {
+	res := TodoResponse{
+		ID:          todo.ID,
+		Description: todo.Description,
+		Completed:   todo.Completed,
+		Priority:    todo.Priority.String(),
+		Category:    todo.Category,
+		Tags:        todo.Tags,
+		DueDate:     todo.DueDate,
+		AssignedTo:  todo.AssignedTo,
+		Archived:    todo.Archived,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		Version:     todo.Version,
+		URL:         "/todos/" + todo.ID.String(),
+	}
+	if res.Tags == nil {
+		res.Tags = make([]string, 0)
+	}
+	return res
+}
+
+func listError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidInput), errors.Is(err, ErrInvalidDate), errors.Is(err, ErrInvalidPriority),
+		errors.Is(err, ErrInvalidSort), errors.Is(err, ErrInvalidCursor):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrNoHistory):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeTodo(w http.ResponseWriter, status int, todo *domain.Todo) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(todo))
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(NewTodoResponse(todo))
+}
+
+// etag is the entity tag of a todo, which is its version, so that comments, timers and the like added since
+// do not turn an edit away
+func etag(todo *domain.Todo) string {
+	return `"` + strconv.Itoa(todo.Version) + `"`
+}
+
+// etagMatches reports whether the If-Match or If-None-Match header holds the ETag of the todo
+//
+// The header is a list of entity tags or "*" for any; weak tags are compared as their strong ones.
+func etagMatches(header string, todo *domain.Todo) bool {
+	want := etag(todo)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == want {
+			return true
+		}
+	}
+	return false
+}
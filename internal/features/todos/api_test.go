@@ -0,0 +1,168 @@
+package todos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/eventstore"
+)
+
+func TestHandler_ListJSON(t *testing.T) {
+	list := domain.NewTodos()
+	for i := 0; i < 5; i++ {
+		todo := domain.NewTodo(fmt.Sprintf("Chore %d", i))
+		todo.Priority = domain.Priority(i % 3)
+		todo.Tags = []string{"chores"}
+		list.Save(todo)
+	}
+	list.Add("Bake a cake")
+	nap := domain.NewTodo("Nap")
+	nap.Snooze(time.Now().Add(time.Hour), domain.NewTodo("").ID, false)
+	list.Save(nap)
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	var got []string
+	path := "/api/todos?tag=chores&sort=priority,-manual&limit=2"
+	for pages := 0; path != ""; pages++ {
+		if pages == 3 {
+			t.Fatalf("ListJSON() keeps going after 3 pages")
+		}
+		rec := get(path)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d %s", path, rec.Code, rec.Body)
+		}
+		var page PageResponse
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatalf("decoding the page: %v", err)
+		}
+		for _, todo := range page.Todos {
+			got = append(got, todo.Description[6:])
+		}
+		path = ""
+		if page.Next != "" {
+			path = "/api/todos?tag=chores&sort=priority,-manual&limit=2&cursor=" + page.Next
+			if link := rec.Header().Get("Link"); link == "" {
+				t.Errorf("GET %s has no Link to the next page", path)
+			}
+		}
+	}
+	if fmt.Sprint(got) != "[2 4 1 3 0]" {
+		t.Errorf("ListJSON() = %v, want [2 4 1 3 0]", got)
+	}
+
+	for _, path := range []string{
+		"/api/todos?sort=shuffle",
+		"/api/todos?limit=none",
+		"/api/todos?cursor=nowhere",
+		"/api/todos?priority=urgent",
+	} {
+		if rec := get(path); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandler_UpdateJSON(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Feed the cat")
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+	do := func(method, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/todos/"+todo.ID.String(), strings.NewReader(body))
+		for name := range header {
+			req.Header.Set(name, header.Get(name))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"1"` {
+		t.Fatalf("GET = %d with ETag %q, want %d with \"1\"", rec.Code, rec.Header().Get("ETag"), http.StatusOK)
+	}
+	if rec = do(http.MethodGet, "", http.Header{"If-None-Match": {`"1"`}}); rec.Code != http.StatusNotModified {
+		t.Errorf("GET with the ETag = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	rec = do(http.MethodPut, `{"description":"Feed the cats"}`, http.Header{"If-Match": {`"1"`}})
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("PUT = %d %s with ETag %q, want %d with \"2\"", rec.Code, rec.Body, rec.Header().Get("ETag"), http.StatusOK)
+	}
+
+	// a second client still holding the first version is told of the change instead of overwriting it
+	rec = do(http.MethodPut, `{"description":"Feed the dog","completed":true}`, http.Header{"If-Match": {`"1"`}})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT with a stale ETag = %d, want %d", rec.Code, http.StatusPreconditionFailed)
+	}
+	var current TodoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&current); err != nil || current.Description != "Feed the cats" || current.Version != 2 {
+		t.Errorf("PUT with a stale ETag returned %+v, %v, want the todo as it is now", current, err)
+	}
+	if rec.Header().Get("ETag") != `"2"` {
+		t.Errorf("PUT with a stale ETag has ETag %q, want \"2\"", rec.Header().Get("ETag"))
+	}
+
+	// without If-Match the change is saved regardless
+	if rec = do(http.MethodPut, `{"description":"Feed the dog","completed":true}`, nil); rec.Code != http.StatusOK {
+		t.Errorf("PUT without If-Match = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec = do(http.MethodPut, `{"description":""}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT without a description = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_ListJSON_asOf(t *testing.T) {
+	store, err := eventstore.Open(t.TempDir(), log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer store.Close()
+	cake := store.Add("Bake a cake")
+	before := time.Now()
+	store.Remove(cake.ID)
+	store.Add("Feed the cat")
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(store, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+	get := func(router http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get(router, "/api/todos?as_of="+url.QueryEscape(before.Format(time.RFC3339Nano)))
+	var page PageResponse
+	if err = json.NewDecoder(rec.Body).Decode(&page); err != nil || len(page.Todos) != 1 || page.Todos[0].ID != cake.ID {
+		t.Errorf("GET as of before the cake was removed = %d %+v, %v, want the cake", rec.Code, page.Todos, err)
+	}
+	if rec = get(router, "/api/todos?as_of=monday"); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET as of a bad time = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	// todos kept in memory keep no history
+	memory := chi.NewRouter()
+	Mount(memory, NewHandler(NewService(domain.NewTodos(), domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+	if rec = get(memory, "/api/todos?as_of=2026-10-12"); rec.Code != http.StatusNotImplemented {
+		t.Errorf("GET as of a day without history = %d, want %d", rec.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,25 @@
+package todos
+
+import (
+	"context"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// AttachmentStore keeps the files attached to todos
+type AttachmentStore interface {
+	// RemoveAll removes the files attached to a todo that is being deleted
+	RemoveAll(ctx context.Context, todo *domain.Todo) error
+}
+
+// noopAttachmentStore is a no-operation implementation of AttachmentStore
+type noopAttachmentStore struct{}
+
+func NewNoopAttachmentStore() AttachmentStore {
+	return &noopAttachmentStore{}
+}
+
+func (s *noopAttachmentStore) RemoveAll(ctx context.Context, todo *domain.Todo) error {
+	// No-op implementation
+	return nil
+}
@@ -1,11 +1,20 @@
 package todos
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/stackus/todos/internal/domain"
+)
 
 var (
-	ErrTodoNotFound     = errors.New("todo not found")
+	ErrTodoNotFound     = domain.ErrTodoNotFound
 	ErrInvalidInput     = errors.New("invalid input")
-	ErrPermissionDenied = errors.New("permission denied")
+	ErrPermissionDenied = domain.ErrPermissionDenied
 	ErrInvalidDate      = errors.New("invalid date")
 	ErrInvalidPriority  = errors.New("invalid priority")
+	ErrCommentNotFound  = domain.ErrCommentNotFound
+	ErrInvalidSort      = errors.New("invalid sort order")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrVersionConflict  = domain.ErrVersionConflict
+	ErrNoHistory        = errors.New("todos keep no history")
 )
@@ -2,13 +2,16 @@ package todos
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
 	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
 	"github.com/stackus/todos/internal/templates/pages"
 	"github.com/stackus/todos/internal/templates/partials"
 )
@@ -35,6 +38,26 @@ type (
 		AddSubtask(w http.ResponseWriter, r *http.Request)
 		// AddComment : POST /todos/add-comment
 		AddComment(w http.ResponseWriter, r *http.Request)
+		// PostComment : POST /todos/{todoId}/comments
+		PostComment(w http.ResponseWriter, r *http.Request)
+		// EditComment : PATCH /todos/{todoId}/comments/{commentId}
+		// EditComment : POST /todos/{todoId}/comments/{commentId}/edit
+		EditComment(w http.ResponseWriter, r *http.Request)
+		// DeleteComment : DELETE /todos/{todoId}/comments/{commentId}
+		// DeleteComment : POST /todos/{todoId}/comments/{commentId}/delete
+		DeleteComment(w http.ResponseWriter, r *http.Request)
+		// Snooze : POST /todos/{todoId}/snooze
+		Snooze(w http.ResponseWriter, r *http.Request)
+		// Unsnooze : POST /todos/{todoId}/unsnooze
+		Unsnooze(w http.ResponseWriter, r *http.Request)
+		// Snoozed : GET /todos/snoozed
+		Snoozed(w http.ResponseWriter, r *http.Request)
+		// ListJSON : GET /api/todos
+		ListJSON(w http.ResponseWriter, r *http.Request)
+		// GetJSON : GET /api/todos/{todoId}
+		GetJSON(w http.ResponseWriter, r *http.Request)
+		// UpdateJSON : PUT /api/todos/{todoId}
+		UpdateJSON(w http.ResponseWriter, r *http.Request)
 	}
 
 	handler struct {
@@ -70,12 +93,27 @@ func Mount(r chi.Router, h Handler) {
 			r.Get("/", h.Get)
 			r.Delete("/", h.Delete)
 			r.Post("/delete", h.Delete)
+			r.Post("/comments", h.PostComment)
+			r.Post("/snooze", h.Snooze)
+			r.Post("/unsnooze", h.Unsnooze)
+			r.Route("/comments/{commentId}", func(r chi.Router) {
+				r.Patch("/", h.EditComment)
+				r.Post("/edit", h.EditComment)
+				r.Delete("/", h.DeleteComment)
+				r.Post("/delete", h.DeleteComment)
+			})
 		})
+		r.Get("/snoozed", h.Snoozed)
 		r.Post("/sort", h.Sort)
 		r.Post("/create", h.CreateTodo)
 		r.Post("/add-subtask", h.AddSubtask)
 		r.Post("/add-comment", h.AddComment)
 	})
+	r.Route("/api/todos", func(r chi.Router) {
+		r.Get("/", h.ListJSON)
+		r.Get("/{todoId}", h.GetJSON)
+		r.Put("/{todoId}", h.UpdateJSON)
+	})
 }
 
 func (h handler) Sort(w http.ResponseWriter, r *http.Request) {
@@ -108,7 +146,7 @@ func (h handler) Sort(w http.ResponseWriter, r *http.Request) {
 
 func (h handler) Search(w http.ResponseWriter, r *http.Request) {
 	var search = r.URL.Query().Get("search")
-	todos, err := h.service.Search(r.Context(), search)
+	results, err := h.service.SearchResults(r.Context(), search)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -116,9 +154,9 @@ func (h handler) Search(w http.ResponseWriter, r *http.Request) {
 
 	switch isHTMX(r) {
 	case true:
-		err = partials.RenderTodos(todos).Render(r.Context(), w)
+		err = partials.SearchResults(results).Render(r.Context(), w)
 	default:
-		err = pages.TodosPage(todos, search).Render(r.Context(), w)
+		err = pages.TodosPage(results, search).Render(r.Context(), w)
 	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -165,9 +203,25 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 	var completed = r.Form.Get("completed") == "true"
 	var description = r.Form.Get("description")
 
-	todo, err := h.service.Update(r.Context(), todoID, completed, description)
+	// forms carry the version of the todo they were rendered from; without one the change is saved regardless
+	var todo *domain.Todo
+	switch version := r.Form.Get("version"); version {
+	case "":
+		todo, err = h.service.Update(r.Context(), todoID, completed, description)
+	default:
+		var v int
+		if v, err = strconv.Atoi(version); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		todo, err = h.service.UpdateVersion(r.Context(), todoID, v, completed, description)
+	}
+	if errors.Is(err, ErrVersionConflict) {
+		h.conflict(w, r, todoID, partials.TodoChange{Completed: completed, Description: description})
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		updateError(w, err)
 		return
 	}
 
@@ -182,6 +236,30 @@ func (h handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// conflict shows the todo as it was saved next to the change that could not be saved, to merge the two
+func (h handler) conflict(w http.ResponseWriter, r *http.Request, todoID uuid.UUID, mine partials.TodoChange) {
+	current, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		updateError(w, err)
+		return
+	}
+	if current == nil {
+		updateError(w, ErrTodoNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusConflict)
+	switch isHTMX(r) {
+	case true:
+		err = partials.TodoConflict(current, mine).Render(r.Context(), w)
+	default:
+		err = pages.TodoConflictPage(current, mine).Render(r.Context(), w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h handler) Get(w http.ResponseWriter, r *http.Request) {
 	var id = chi.URLParam(r, "todoId")
 	var todoID uuid.UUID
@@ -200,7 +278,11 @@ func (h handler) Get(w http.ResponseWriter, r *http.Request) {
 	case true:
 		err = partials.EditTodoForm(todo).Render(r.Context(), w)
 	default:
-		err = pages.TodoPage(todo).Render(r.Context(), w)
+		var viewer partials.Viewer
+		if viewer, err = h.viewer(r); err != nil {
+			break
+		}
+		err = pages.TodoPage(todo, viewer).Render(r.Context(), w)
 	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -303,6 +385,239 @@ func (h handler) AddComment(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h handler) PostComment(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var content = r.Form.Get("content")
+	var userID = identity.UserID(r.Context())
+
+	switch parentID := r.Form.Get("parent_id"); parentID {
+	case "":
+		err = h.service.AddComment(r.Context(), todoID, content, userID)
+	default:
+		var parentUUID uuid.UUID
+		if parentUUID, err = uuid.Parse(parentID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.service.ReplyToComment(r.Context(), todoID, parentUUID, content, userID)
+	}
+	if err != nil {
+		commentError(w, err)
+		return
+	}
+
+	h.renderComments(w, r, todoID)
+}
+
+func (h handler) EditComment(w http.ResponseWriter, r *http.Request) {
+	todoID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.EditComment(r.Context(), todoID, commentID, r.Form.Get("content"), identity.UserID(r.Context())); err != nil {
+		commentError(w, err)
+		return
+	}
+
+	h.renderComments(w, r, todoID)
+}
+
+func (h handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	todoID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.DeleteComment(r.Context(), todoID, commentID, identity.UserID(r.Context())); err != nil {
+		commentError(w, err)
+		return
+	}
+
+	h.renderComments(w, r, todoID)
+}
+
+func (h handler) Snooze(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	until, err := SnoozeUntil(r.Form.Get("action"), r.Form.Get("until"), time.Now())
+	if err != nil {
+		snoozeError(w, err)
+		return
+	}
+
+	if err = h.service.Snooze(r.Context(), todoID, until, r.Form.Get("notify") == "true"); err != nil {
+		snoozeError(w, err)
+		return
+	}
+
+	h.renderSnooze(w, r, todoID)
+}
+
+func (h handler) Unsnooze(w http.ResponseWriter, r *http.Request) {
+	todoID, err := uuid.Parse(chi.URLParam(r, "todoId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.Unsnooze(r.Context(), todoID); err != nil {
+		snoozeError(w, err)
+		return
+	}
+
+	h.renderSnooze(w, r, todoID)
+}
+
+func (h handler) Snoozed(w http.ResponseWriter, r *http.Request) {
+	todos, err := h.service.GetSnoozed(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch isHTMX(r) {
+	case true:
+		err = partials.SnoozedTodos(todos).Render(r.Context(), w)
+	default:
+		err = pages.SnoozedPage(todos).Render(r.Context(), w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// renderSnooze responds to snoozing from the todo page with its snooze section, and from a list by
+// taking the todo out of it; the browser is sent back to where it came from without HTMX
+func (h handler) renderSnooze(w http.ResponseWriter, r *http.Request, todoID uuid.UUID) {
+	fromTodo := r.Form.Get("view") == "todo"
+	if !isHTMX(r) {
+		switch fromTodo {
+		case true:
+			http.Redirect(w, r, "/todos/"+todoID.String()+"#snooze", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/", http.StatusFound)
+		}
+		return
+	}
+	if !fromTodo {
+		_, _ = w.Write([]byte(""))
+		return
+	}
+
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err = partials.Snooze(todo).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// renderComments responds with the comments section of the todo, or sends the browser back to the todo
+func (h handler) renderComments(w http.ResponseWriter, r *http.Request, todoID uuid.UUID) {
+	if !isHTMX(r) {
+		http.Redirect(w, r, "/todos/"+todoID.String()+"#comments", http.StatusFound)
+		return
+	}
+
+	todo, err := h.service.Get(r.Context(), todoID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if todo == nil {
+		commentError(w, ErrTodoNotFound)
+		return
+	}
+	viewer, err := h.viewer(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err = partials.Comments(todo, viewer).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// viewer is the user making the request, along with the users their comments may mention
+func (h handler) viewer(r *http.Request) (partials.Viewer, error) {
+	users, err := h.service.Users(r.Context())
+	if err != nil {
+		return partials.Viewer{}, err
+	}
+	return partials.Viewer{UserID: identity.UserID(r.Context()), Users: users}, nil
+}
+
+func commentIDs(r *http.Request) (todoID uuid.UUID, commentID uuid.UUID, err error) {
+	if todoID, err = uuid.Parse(chi.URLParam(r, "todoId")); err != nil {
+		return
+	}
+	commentID, err = uuid.Parse(chi.URLParam(r, "commentId"))
+	return
+}
+
+func updateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrVersionConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func commentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound), errors.Is(err, ErrCommentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrPermissionDenied):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func snoozeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrTodoNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidDate), errors.Is(err, ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func isHTMX(r *http.Request) bool {
 	// Check for "HX-Request" header
 	if r.Header.Get("HX-Request") != "" {
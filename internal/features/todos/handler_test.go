@@ -0,0 +1,98 @@
+package todos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestHandler_Update(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Feed the cat")
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+	patch := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/todos/"+todo.ID.String(), strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := patch(url.Values{"description": {"Feed the cats"}, "version": {"1"}})
+	if todo = list.Get(todo.ID); rec.Code != http.StatusOK || todo.Description != "Feed the cats" {
+		t.Fatalf("PATCH = %d, todo %q", rec.Code, todo.Description)
+	}
+
+	// a second tab still showing the first version gets a screen to merge its change with the saved one
+	rec = patch(url.Values{"description": {"Feed the dog"}, "completed": {"true"}, "version": {"1"}})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("PATCH from version 1 = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Feed the cats", `value="Feed the dog"`, `name="version" value="2"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("PATCH from version 1 is missing %q:\n%s", want, body)
+		}
+	}
+	if todo = list.Get(todo.ID); todo.Description != "Feed the cats" || todo.Completed {
+		t.Errorf("PATCH from version 1 changed the todo: %+v", todo)
+	}
+
+	// saving from the merge screen carries the version it was shown
+	if rec = patch(url.Values{"description": {"Feed the dog"}, "version": {"2"}}); rec.Code != http.StatusOK || list.Get(todo.ID).Description != "Feed the dog" {
+		t.Errorf("PATCH from the merge screen = %d, todo %q", rec.Code, todo.Description)
+	}
+	if rec = patch(url.Values{"description": {"Feed the dog"}, "version": {"latest"}}); rec.Code != http.StatusBadRequest {
+		t.Errorf("PATCH with a bad version = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandler_Update_concurrent(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Feed the cat")
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())))
+
+	// two tabs saving from the same version at the same time: one saves, the other is sent to the merge screen
+	for round := 0; round < 20; round++ {
+		version := strconv.Itoa(list.Get(todo.ID).Version)
+		codes := make([]int, 2)
+		var wg sync.WaitGroup
+		for i := range codes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				form := url.Values{"description": {"Feed the cat " + strconv.Itoa(i)}, "version": {version}}
+				req := httptest.NewRequest(http.MethodPatch, "/todos/"+todo.ID.String(), strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.Header.Set("HX-Request", "true")
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+				codes[i] = rec.Code
+			}(i)
+		}
+		wg.Wait()
+
+		conflicts := 0
+		for _, code := range codes {
+			if code == http.StatusConflict {
+				conflicts++
+			}
+		}
+		if conflicts != 1 {
+			t.Fatalf("PATCH twice from version %s = %v, want exactly one %d", version, codes, http.StatusConflict)
+		}
+	}
+}
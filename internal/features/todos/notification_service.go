@@ -10,7 +10,8 @@ import (
 // NotificationService handles todo reminders and notifications
 type NotificationService interface {
 	ScheduleReminder(ctx context.Context, todo *domain.Todo)
-	SendNotification(ctx context.Context, userID uuid.UUID, message string)
+	// SendNotification sends the notification on to the user beyond their inbox, returning why it could not
+	SendNotification(ctx context.Context, userID uuid.UUID, message string) error
 }
 
 // noopNotificationService is a no-operation implementation of NotificationService
@@ -24,6 +25,7 @@ func (s *noopNotificationService) ScheduleReminder(ctx context.Context, todo *do
 	// No-op implementation
 }
 
-func (s *noopNotificationService) SendNotification(ctx context.Context, userID uuid.UUID, message string) {
+func (s *noopNotificationService) SendNotification(ctx context.Context, userID uuid.UUID, message string) error {
 	// No-op implementation
+	return nil
 }
@@ -0,0 +1,111 @@
+package todos
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// queryDate is the layout of the due date range parameters
+const queryDate = "2006-01-02"
+
+// ParseQuery reads a todo query from URL parameters
+//
+// The parameters are search, tag (repeatable), category, priority, due_from, due_to,
+// assignee, completed and archived.
+func ParseQuery(values url.Values) (domain.Query, error) {
+	query := domain.Query{
+		Search:   strings.TrimSpace(values.Get("search")),
+		Category: strings.TrimSpace(values.Get("category")),
+	}
+
+	for _, tag := range values["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
+	if v := values.Get("priority"); v != "" {
+		priority, ok := domain.ParsePriority(v)
+		if !ok {
+			return query, ErrInvalidPriority
+		}
+		query.Priority = &priority
+	}
+	if v := values.Get("due_from"); v != "" {
+		from, err := time.ParseInLocation(queryDate, v, time.Local)
+		if err != nil {
+			return query, ErrInvalidDate
+		}
+		query.DueFrom = &from
+	}
+	if v := values.Get("due_to"); v != "" {
+		to, err := time.ParseInLocation(queryDate, v, time.Local)
+		if err != nil {
+			return query, ErrInvalidDate
+		}
+		// the range includes the whole of the last day
+		to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		query.DueTo = &to
+	}
+	if v := values.Get("assignee"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			return query, ErrInvalidInput
+		}
+		query.AssignedTo = &userID
+	}
+	if v := values.Get("completed"); v != "" {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, ErrInvalidInput
+		}
+		query.Completed = &completed
+	}
+	if v := values.Get("archived"); v != "" {
+		archived, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, ErrInvalidInput
+		}
+		query.IncludeArchived = archived
+	}
+
+	return query, nil
+}
+
+// QueryValues writes a todo query as the URL parameters read by ParseQuery
+func QueryValues(query domain.Query) url.Values {
+	values := url.Values{}
+	if query.Search != "" {
+		values.Set("search", query.Search)
+	}
+	for _, tag := range query.Tags {
+		values.Add("tag", tag)
+	}
+	if query.Category != "" {
+		values.Set("category", query.Category)
+	}
+	if query.Priority != nil {
+		values.Set("priority", query.Priority.String())
+	}
+	if query.DueFrom != nil {
+		values.Set("due_from", query.DueFrom.Format(queryDate))
+	}
+	if query.DueTo != nil {
+		values.Set("due_to", query.DueTo.Format(queryDate))
+	}
+	if query.AssignedTo != nil {
+		values.Set("assignee", query.AssignedTo.String())
+	}
+	if query.Completed != nil {
+		values.Set("completed", strconv.FormatBool(*query.Completed))
+	}
+	if query.IncludeArchived {
+		values.Set("archived", "true")
+	}
+	return values
+}
@@ -2,23 +2,47 @@ package todos
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/fulltext"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/markdown"
 )
 
 type (
 	Service interface {
 		// Add adds a todo to the list
 		Add(ctx context.Context, description string) (*domain.Todo, error)
-		// Remove removes a todo from the list
+		// Remove removes a todo from the list for good, along with its attachments
 		Remove(ctx context.Context, id uuid.UUID) error
 		// Update updates a todo in the list
 		Update(ctx context.Context, id uuid.UUID, completed bool, description string) (*domain.Todo, error)
-		// Search returns a list of todos that match the search string
+		// Save adds the todo as it is, keeping the ID it was given, under its parent when it has one
+		Save(ctx context.Context, todo *domain.Todo) (*domain.Todo, error)
+		// Change makes the changes to a todo all at once, or none of them when one fails
+		Change(ctx context.Context, id uuid.UUID, changes ...domain.Change) (*domain.Todo, error)
+		// UpdateVersion updates a todo in the list only while it is still at the version the change was made to,
+		// returning ErrVersionConflict once someone else has changed it
+		UpdateVersion(ctx context.Context, id uuid.UUID, version int, completed bool, description string) (*domain.Todo, error)
+		// All returns every todo, snoozed or not, in the order they were dragged into
+		All(ctx context.Context) ([]*domain.Todo, error)
+		// Search returns a list of todos that match the search string, the most relevant first
 		Search(ctx context.Context, search string) ([]*domain.Todo, error)
+		// SearchResults returns the todos that match the search string with snippets of where they matched, the most relevant first
+		//
+		// An empty search returns every todo in the order they were dragged into, without snippets.
+		SearchResults(ctx context.Context, search string) ([]fulltext.Result, error)
+		// List returns a page of the todos matching the query, leaving out the todos snoozed until later
+		List(ctx context.Context, query domain.Query, req domain.PageRequest) (domain.Page, error)
+		// ListAt returns a page of the todos matching the query as they were at the time, for repositories
+		// keeping a history
+		ListAt(ctx context.Context, at time.Time, query domain.Query, req domain.PageRequest) (domain.Page, error)
 		// Get returns a todo by id
 		Get(ctx context.Context, id uuid.UUID) (*domain.Todo, error)
 		// Sort sorts the todos by the given ids
@@ -28,9 +52,23 @@ type (
 		AddWithDetails(ctx context.Context, description string, dueDate *time.Time, priority domain.Priority, category string, tags []string) (*domain.Todo, error)
 		AddSubtask(ctx context.Context, parentID uuid.UUID, description string) (*domain.Todo, error)
 		AddComment(ctx context.Context, todoID uuid.UUID, content string, userID uuid.UUID) error
+		// ReplyToComment adds a comment replying to another comment on the todo
+		ReplyToComment(ctx context.Context, todoID, parentID uuid.UUID, content string, userID uuid.UUID) error
+		// EditComment changes the content of a comment; only the user who made it may do so
+		EditComment(ctx context.Context, todoID, commentID uuid.UUID, content string, userID uuid.UUID) error
+		// DeleteComment removes a comment and its replies; only the user who made it may do so
+		DeleteComment(ctx context.Context, todoID, commentID uuid.UUID, userID uuid.UUID) error
+		// Users returns the users who can be mentioned in comments
+		Users(ctx context.Context) ([]*domain.User, error)
 		SetRecurring(ctx context.Context, id uuid.UUID, frequency string, endDate *time.Time) error
 		Archive(ctx context.Context, id uuid.UUID) error
 		Assign(ctx context.Context, todoID uuid.UUID, userID uuid.UUID) error
+		// Snooze hides a todo from the default views until the time, telling the user making the request when it comes back if they ask
+		Snooze(ctx context.Context, id uuid.UUID, until time.Time, notify bool) error
+		// Unsnooze brings a snoozed todo back straight away
+		Unsnooze(ctx context.Context, id uuid.UUID) error
+		// Resurface brings back the snoozed todos whose time has come, returning them
+		Resurface(ctx context.Context) ([]*domain.Todo, error)
 
 		// Query methods
 		GetByCategory(ctx context.Context, category string) ([]*domain.Todo, error)
@@ -43,45 +81,148 @@ type (
 		GetSubtasks(ctx context.Context, parentID uuid.UUID) ([]*domain.Todo, error)
 		GetOverdue(ctx context.Context) ([]*domain.Todo, error)
 		GetUpcoming(ctx context.Context, days int) ([]*domain.Todo, error)
+		// GetSnoozed returns the todos snoozed until later, those coming back soonest first
+		GetSnoozed(ctx context.Context) ([]*domain.Todo, error)
 	}
 
 	service struct {
 		todos         domain.TodoRepository
+		users         domain.UserRepository
+		inbox         domain.NotificationRepository
 		notifications NotificationService
+		attachments   AttachmentStore
+		// index is kept up to date by the todo repository, as every feature changes the todos through it
+		index *fulltext.Index
+		now   func() time.Time
 	}
 )
 
-func NewService(todos domain.TodoRepository) Service {
+func NewService(todos domain.TodoRepository, users domain.UserRepository, inbox domain.NotificationRepository, notifications NotificationService, attachments AttachmentStore) Service {
+	indexed, ok := todos.(fulltext.Indexed)
+	if !ok {
+		indexed = fulltext.NewIndex().TodoRepository(todos)
+	}
 	return &service{
-		todos:         todos,
-		notifications: NewNoopNotificationService(),
+		todos:         indexed,
+		users:         users,
+		inbox:         inbox,
+		notifications: notifications,
+		attachments:   attachments,
+		index:         indexed.Index(),
+		now:           time.Now,
 	}
 }
 
 func (s service) Add(_ context.Context, description string) (*domain.Todo, error) {
-	todo := s.todos.Add(description)
-
-	return todo, nil
+	return s.todos.Add(description), nil
 }
 
-func (s service) Remove(_ context.Context, id uuid.UUID) error {
+func (s service) Remove(ctx context.Context, id uuid.UUID) error {
+	// the files go first so that a failure leaves the todo to try again with
+	if todo := s.todos.Get(id); todo != nil {
+		if err := s.attachments.RemoveAll(ctx, todo); err != nil {
+			return err
+		}
+	}
 	s.todos.Remove(id)
 
 	return nil
 }
 
 func (s service) Update(_ context.Context, id uuid.UUID, completed bool, description string) (*domain.Todo, error) {
-	todo := s.todos.Update(id, completed, description)
+	return s.todos.Update(id, completed, description), nil
+}
 
-	return todo, nil
+func (s service) Save(_ context.Context, todo *domain.Todo) (*domain.Todo, error) {
+	if todo.ParentID != nil {
+		return s.todos.AddSubtask(*todo.ParentID, todo)
+	}
+
+	s.todos.Save(todo)
+	return s.todos.Get(todo.ID), nil
+}
+
+func (s service) Change(_ context.Context, id uuid.UUID, changes ...domain.Change) (*domain.Todo, error) {
+	return s.todos.Change(id, changes...)
 }
 
-func (s service) Search(_ context.Context, search string) ([]*domain.Todo, error) {
-	todos := s.todos.Search(search)
+func (s service) UpdateVersion(ctx context.Context, id uuid.UUID, version int, completed bool, description string) (*domain.Todo, error) {
+	// the version is checked as the change is made, so that of two changes made to the same version one fails
+	return s.Change(ctx, id, domain.Expect{Version: version}, domain.Update{Completed: completed, Description: description})
+}
+
+func (s service) All(_ context.Context) ([]*domain.Todo, error) {
+	return s.todos.All(), nil
+}
 
+func (s service) Search(ctx context.Context, search string) ([]*domain.Todo, error) {
+	results, err := s.SearchResults(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]*domain.Todo, len(results))
+	for i, result := range results {
+		todos[i] = result.Todo
+	}
 	return todos, nil
 }
 
+func (s service) SearchResults(_ context.Context, search string) ([]fulltext.Result, error) {
+	now := s.now()
+	all := s.todos.All()
+
+	if strings.TrimSpace(search) == "" {
+		todos := awake(all, now)
+		results := make([]fulltext.Result, len(todos))
+		for i, todo := range todos {
+			results[i] = fulltext.Result{Todo: todo}
+		}
+		return results, nil
+	}
+
+	results := make([]fulltext.Result, 0)
+	for _, result := range s.index.Search(search) {
+		if !result.Todo.IsSnoozed(now) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func (s service) List(_ context.Context, query domain.Query, req domain.PageRequest) (domain.Page, error) {
+	page, ok := req.Page(awake(s.todos.Find(query), s.now()))
+	if !ok {
+		return page, ErrInvalidCursor
+	}
+
+	return page, nil
+}
+
+func (s service) ListAt(_ context.Context, at time.Time, query domain.Query, req domain.PageRequest) (domain.Page, error) {
+	history, ok := s.todos.(domain.TodoHistory)
+	if !ok {
+		return domain.Page{}, ErrNoHistory
+	}
+	todos, err := history.TodosAt(at)
+	if err != nil {
+		return domain.Page{}, err
+	}
+
+	matching := make([]*domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if query.Matches(todo) {
+			matching = append(matching, todo)
+		}
+	}
+	page, ok := req.Page(awake(matching, at))
+	if !ok {
+		return page, ErrInvalidCursor
+	}
+
+	return page, nil
+}
+
 func (s service) Get(_ context.Context, id uuid.UUID) (*domain.Todo, error) {
 	todo := s.todos.Get(id)
 
@@ -107,12 +248,12 @@ func (s *service) AddWithDetails(ctx context.Context, description string, dueDat
 		return nil, ErrInvalidPriority
 	}
 
-	todo := s.todos.Add(description)
+	todo := domain.NewTodo(description)
 	todo.DueDate = dueDate
 	todo.Priority = priority
 	todo.Category = category
 	todo.Tags = tags
-	todo.UpdatedAt = time.Now()
+	s.todos.Save(todo)
 
 	if dueDate != nil {
 		s.notifications.ScheduleReminder(ctx, todo)
@@ -135,8 +276,10 @@ func (s *service) AddSubtask(ctx context.Context, parentID uuid.UUID, descriptio
 		return nil, ErrInvalidInput
 	}
 
-	subtask := s.todos.Add(description)
-	parent.AddSubtask(subtask)
+	subtask, err := s.todos.AddSubtask(parentID, domain.NewTodo(description))
+	if err != nil {
+		return nil, err
+	}
 	return subtask, nil
 }
 
@@ -154,41 +297,185 @@ func (s *service) AddComment(ctx context.Context, todoID uuid.UUID, content stri
 		return ErrInvalidInput
 	}
 
-	todo.AddComment(content, userID)
-	return nil
+	return s.addComment(ctx, todoID, domain.Comment{Content: content, UserID: userID})
 }
 
-func (s *service) SetRecurring(ctx context.Context, id uuid.UUID, frequency string, endDate *time.Time) error {
-	todo := s.todos.Get(id)
+func (s *service) ReplyToComment(ctx context.Context, todoID, parentID uuid.UUID, content string, userID uuid.UUID) error {
+	if strings.TrimSpace(content) == "" {
+		return ErrInvalidInput
+	}
+
+	todo := s.todos.Get(todoID)
 	if todo == nil {
 		return ErrTodoNotFound
 	}
 
-	todo.SetRecurring(frequency, endDate)
+	if todo.Archived {
+		return ErrInvalidInput
+	}
+
+	return s.addComment(ctx, todoID, domain.Comment{Content: content, UserID: userID, ParentID: &parentID})
+}
+
+// addComment adds the comment to the todo as made now, telling the users it mentions
+func (s *service) addComment(ctx context.Context, todoID uuid.UUID, comment domain.Comment) error {
+	comment.ID = uuid.New()
+	comment.CreatedAt = s.now()
+	todo, err := s.todos.Change(todoID, domain.AddComment{Comment: comment})
+	if err != nil {
+		return err
+	}
+	s.notifyMentions(ctx, todo, comment, "")
 	return nil
 }
 
-func (s *service) Archive(ctx context.Context, id uuid.UUID) error {
-	todo := s.todos.Get(id)
-	if todo == nil {
-		return ErrTodoNotFound
+func (s *service) EditComment(ctx context.Context, todoID, commentID uuid.UUID, content string, userID uuid.UUID) error {
+	if strings.TrimSpace(content) == "" {
+		return ErrInvalidInput
 	}
 
-	todo.Archive()
+	var previous string
+	if todo := s.todos.Get(todoID); todo != nil && todo.Comment(commentID) != nil {
+		previous = todo.Comment(commentID).Content
+	}
+	todo, err := s.todos.Change(todoID, domain.EditComment{CommentID: commentID, UserID: userID, Content: content})
+	if err != nil {
+		return err
+	}
+	s.notifyMentions(ctx, todo, *todo.Comment(commentID), previous)
 	return nil
 }
 
+func (s *service) DeleteComment(ctx context.Context, todoID, commentID uuid.UUID, userID uuid.UUID) error {
+	_, err := s.todos.Change(todoID, domain.RemoveComment{CommentID: commentID, UserID: userID})
+	return err
+}
+
+func (s *service) Users(ctx context.Context) ([]*domain.User, error) {
+	return s.users.All(), nil
+}
+
+// notifyMentions tells the users mentioned in the comment that they were mentioned
+//
+// Users already mentioned in the previous content of an edited comment, and the author, are not told again.
+func (s *service) notifyMentions(ctx context.Context, todo *domain.Todo, comment domain.Comment, previous string) {
+	notified := make(map[uuid.UUID]bool)
+	for _, username := range markdown.Mentions(previous) {
+		if user := s.users.GetByUsername(username); user != nil {
+			notified[user.ID] = true
+		}
+	}
+
+	for _, username := range markdown.Mentions(comment.Content) {
+		user := s.users.GetByUsername(username)
+		if user == nil || user.ID == comment.UserID || notified[user.ID] {
+			continue
+		}
+		notified[user.ID] = true
+
+		message := fmt.Sprintf("%s mentioned you on %q", s.userName(comment.UserID), todo.PlainDescription())
+		notification := domain.NewNotification(user.ID, domain.NotificationMention, todo.ID, message)
+		notification.CommentID = &comment.ID
+		notification.ActorID = &comment.UserID
+		s.notify(ctx, notification)
+	}
+}
+
+// notify adds the notification to the inbox of the user and sends it on
+//
+// The notification is in the inbox whether or not it could be sent on, so a failure to send it does not
+// fail the change that caused it; failures are counted by the metrics of the notification service.
+func (s *service) notify(ctx context.Context, notification *domain.Notification) {
+	s.inbox.Add(notification)
+	_ = s.notifications.SendNotification(ctx, notification.UserID, notification.Message)
+}
+
+// userName returns how the user is shown to others
+func (s *service) userName(userID uuid.UUID) string {
+	if user := s.users.Get(userID); user != nil {
+		return user.DisplayName()
+	}
+	return "Someone"
+}
+
+func (s *service) SetRecurring(ctx context.Context, id uuid.UUID, frequency string, endDate *time.Time) error {
+	_, err := s.todos.Change(id, domain.SetRecurring{Frequency: frequency, EndDate: endDate})
+	return err
+}
+
+func (s *service) Archive(ctx context.Context, id uuid.UUID) error {
+	_, err := s.todos.Change(id, domain.SetArchived{Archived: true})
+	return err
+}
+
 func (s *service) Assign(ctx context.Context, todoID uuid.UUID, userID uuid.UUID) error {
 	todo := s.todos.Get(todoID)
 	if todo == nil {
 		return ErrTodoNotFound
 	}
 
-	todo.AssignedTo = &userID
-	todo.UpdatedAt = time.Now()
+	alreadyAssigned := todo.AssignedTo != nil && *todo.AssignedTo == userID
+	todo, err := s.todos.Change(todoID, domain.Assign{UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	// nobody needs telling about assigning a todo to themselves
+	if actorID := identity.UserID(ctx); !alreadyAssigned && actorID != userID {
+		message := fmt.Sprintf("%s assigned %q to you", s.userName(actorID), todo.PlainDescription())
+		notification := domain.NewNotification(userID, domain.NotificationAssignment, todo.ID, message)
+		if actorID != uuid.Nil {
+			notification.ActorID = &actorID
+		}
+		s.notify(ctx, notification)
+	}
 	return nil
 }
 
+func (s *service) Snooze(ctx context.Context, id uuid.UUID, until time.Time, notify bool) error {
+	if s.todos.Get(id) == nil {
+		return ErrTodoNotFound
+	}
+
+	if !until.After(s.now()) {
+		return ErrInvalidDate
+	}
+
+	snooze := domain.Snooze{Until: until, UserID: identity.UserID(ctx), Notify: notify}
+	_, err := s.todos.Change(id, domain.SetSnooze{Snooze: snooze})
+	return err
+}
+
+func (s *service) Unsnooze(ctx context.Context, id uuid.UUID) error {
+	_, err := s.todos.Change(id, domain.Unsnooze{})
+	return err
+}
+
+func (s *service) Resurface(ctx context.Context) ([]*domain.Todo, error) {
+	now := s.now()
+
+	var resurfaced []*domain.Todo
+	for _, todo := range s.todos.All() {
+		snooze := todo.Snoozed
+		if snooze == nil || todo.IsSnoozed(now) {
+			continue
+		}
+		// the todo may have been woken or snoozed again since it was read; the repository only wakes it when
+		// it is still due
+		woken, err := s.todos.Change(todo.ID, domain.Wake{Now: now})
+		if err != nil {
+			continue
+		}
+		resurfaced = append(resurfaced, woken)
+
+		if snooze.Notify && snooze.UserID != uuid.Nil {
+			message := fmt.Sprintf("%q is back from snoozing", todo.PlainDescription())
+			s.notify(ctx, domain.NewNotification(snooze.UserID, domain.NotificationSnooze, todo.ID, message))
+		}
+	}
+	return resurfaced, nil
+}
+
 func (s *service) GetByCategory(ctx context.Context, category string) ([]*domain.Todo, error) {
 	return s.todos.GetByCategory(category), nil
 }
@@ -228,3 +515,29 @@ func (s *service) GetOverdue(ctx context.Context) ([]*domain.Todo, error) {
 func (s *service) GetUpcoming(ctx context.Context, days int) ([]*domain.Todo, error) {
 	return s.todos.GetUpcoming(days), nil
 }
+
+func (s *service) GetSnoozed(ctx context.Context) ([]*domain.Todo, error) {
+	now := s.now()
+
+	var snoozed []*domain.Todo
+	for _, todo := range s.todos.All() {
+		if todo.IsSnoozed(now) {
+			snoozed = append(snoozed, todo)
+		}
+	}
+	sort.SliceStable(snoozed, func(i, j int) bool {
+		return snoozed[i].Snoozed.Until.Before(snoozed[j].Snoozed.Until)
+	})
+	return snoozed, nil
+}
+
+// awake leaves out the todos snoozed until later
+func awake(todos []*domain.Todo, now time.Time) []*domain.Todo {
+	list := make([]*domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if !todo.IsSnoozed(now) {
+			list = append(list, todo)
+		}
+	}
+	return list
+}
@@ -0,0 +1,289 @@
+package todos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/fulltext"
+	"github.com/stackus/todos/internal/identity"
+)
+
+// sentNotifications records the notifications sent instead of sending them
+type sentNotifications struct {
+	noopNotificationService
+	to []uuid.UUID
+}
+
+func (s *sentNotifications) SendNotification(_ context.Context, userID uuid.UUID, _ string) error {
+	s.to = append(s.to, userID)
+	return nil
+}
+
+// removedAttachments records the todos whose attachments were removed
+type removedAttachments struct {
+	todos []uuid.UUID
+}
+
+func (r *removedAttachments) RemoveAll(_ context.Context, todo *domain.Todo) error {
+	r.todos = append(r.todos, todo.ID)
+	return nil
+}
+
+func Test_service_Remove(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Fix the layout")
+	removed := &removedAttachments{}
+	s := NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), removed)
+
+	for _, id := range []uuid.UUID{todo.ID, uuid.New()} {
+		if err := s.Remove(context.Background(), id); err != nil {
+			t.Fatalf("Remove() error = %v", err)
+		}
+	}
+	if len(removed.todos) != 1 || removed.todos[0] != todo.ID {
+		t.Errorf("attachments removed for %v, want only %v", removed.todos, todo.ID)
+	}
+	if list.Get(todo.ID) != nil {
+		t.Errorf("the todo is still in the list")
+	}
+}
+
+func Test_service_Mentions(t *testing.T) {
+	alice := &domain.User{ID: uuid.New(), Username: "alice", Name: "Alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	users := domain.NewUsers()
+	users.Save(alice)
+	users.Save(bob)
+
+	list := domain.NewTodos()
+	todo := list.Add("Plan the **party**")
+	inbox := domain.NewNotifications()
+	sent := &sentNotifications{}
+	s := NewService(list, users, inbox, sent, NewNoopAttachmentStore())
+	ctx := context.Background()
+
+	// the author and unknown users are not told
+	if err := s.AddComment(ctx, todo.ID, "@bob and @Alice, @alice again, @carol and @nobody", alice.ID); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if len(sent.to) != 1 || sent.to[0] != bob.ID {
+		t.Fatalf("sent to %v, want only bob %v", sent.to, bob.ID)
+	}
+	mentions := inbox.ForUser(bob.ID)
+	if len(mentions) != 1 {
+		t.Fatalf("inbox of bob = %v, want one notification", mentions)
+	}
+	if got := mentions[0]; got.Kind != domain.NotificationMention || got.TodoID != todo.ID ||
+		got.CommentID == nil || got.Message != `Alice mentioned you on "Plan the party"` {
+		t.Errorf("notification = %+v, want a mention by Alice on the todo", got)
+	}
+
+	// editing only tells the users who were not mentioned before
+	commentID := *mentions[0].CommentID
+	reply := uuid.New()
+	if err := s.ReplyToComment(ctx, todo.ID, commentID, "thanks", reply); err != nil {
+		t.Fatalf("ReplyToComment() error = %v", err)
+	}
+	if err := s.EditComment(ctx, todo.ID, commentID, "@bob, ping @alice", alice.ID); err != nil {
+		t.Fatalf("EditComment() error = %v", err)
+	}
+	if err := s.EditComment(ctx, todo.ID, list.Get(todo.ID).Comments[1].ID, "thanks @alice", reply); err != nil {
+		t.Fatalf("EditComment() error = %v", err)
+	}
+	if len(sent.to) != 2 || sent.to[1] != alice.ID {
+		t.Errorf("sent to %v, want bob then alice", sent.to)
+	}
+	if got := inbox.ForUser(alice.ID); len(got) != 1 || got[0].Message != `Someone mentioned you on "Plan the party"` {
+		t.Errorf("inbox of alice = %v, want a mention by someone", got)
+	}
+}
+
+func Test_service_Assign(t *testing.T) {
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	users := domain.NewUsers()
+	users.Save(alice)
+
+	list := domain.NewTodos()
+	todo := list.Add("Feed the cat")
+	inbox := domain.NewNotifications()
+	sent := &sentNotifications{}
+	s := NewService(list, users, inbox, sent, NewNoopAttachmentStore())
+
+	// taking a todo on yourself tells nobody
+	if err := s.Assign(identity.WithUserID(context.Background(), alice.ID), todo.ID, alice.ID); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if len(sent.to) != 0 {
+		t.Fatalf("sent to %v, want nobody", sent.to)
+	}
+
+	bobID := uuid.New()
+	ctx := identity.WithUserID(context.Background(), alice.ID)
+	for i := 0; i < 2; i++ {
+		if err := s.Assign(ctx, todo.ID, bobID); err != nil {
+			t.Fatalf("Assign() error = %v", err)
+		}
+	}
+	if len(sent.to) != 1 || sent.to[0] != bobID {
+		t.Fatalf("sent to %v, want bob once", sent.to)
+	}
+	got := inbox.ForUser(bobID)
+	if len(got) != 1 || got[0].Kind != domain.NotificationAssignment || *got[0].ActorID != alice.ID {
+		t.Errorf("inbox of bob = %v, want an assignment by alice", got)
+	}
+}
+
+func Test_service_Snooze(t *testing.T) {
+	list := domain.NewTodos()
+	passport, cat := list.Add("Renew the passport"), list.Add("Feed the cat")
+	inbox := domain.NewNotifications()
+	sent := &sentNotifications{}
+	s := NewService(list, domain.NewUsers(), inbox, sent, NewNoopAttachmentStore()).(*service)
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+	userID := uuid.New()
+	ctx := identity.WithUserID(context.Background(), userID)
+
+	if err := s.Snooze(ctx, passport.ID, now.Add(-time.Minute), false); !errors.Is(err, ErrInvalidDate) {
+		t.Fatalf("Snooze() into the past error = %v, want %v", err, ErrInvalidDate)
+	}
+	if err := s.Snooze(ctx, passport.ID, now.AddDate(0, 0, 1), true); err != nil {
+		t.Fatalf("Snooze() error = %v", err)
+	}
+	if err := s.Snooze(ctx, cat.ID, now.Add(time.Hour), false); err != nil {
+		t.Fatalf("Snooze() error = %v", err)
+	}
+
+	// snoozed todos are hidden from the default views
+	if todos, _ := s.Search(ctx, ""); len(todos) != 0 {
+		t.Fatalf("Search() = %v, want nothing while both are snoozed", todos)
+	}
+	if snoozed, _ := s.GetSnoozed(ctx); len(snoozed) != 2 || snoozed[0].ID != cat.ID {
+		t.Fatalf("GetSnoozed() = %v, want the cat then the passport", snoozed)
+	}
+
+	// the cat comes back quietly after an hour
+	now = now.Add(time.Hour)
+	resurfaced, err := s.Resurface(ctx)
+	if err != nil {
+		t.Fatalf("Resurface() error = %v", err)
+	}
+	if len(resurfaced) != 1 || resurfaced[0].ID != cat.ID || len(sent.to) != 0 {
+		t.Fatalf("Resurface() = %v sending to %v, want the cat back quietly", resurfaced, sent.to)
+	}
+	if todos, _ := s.Search(ctx, ""); len(todos) != 1 || todos[0].ID != cat.ID {
+		t.Fatalf("Search() = %v, want the cat", todos)
+	}
+
+	// the passport comes back the next day, telling the user who snoozed it
+	now = now.AddDate(0, 0, 1)
+	if resurfaced, _ = s.Resurface(ctx); len(resurfaced) != 1 || resurfaced[0].ID != passport.ID {
+		t.Fatalf("Resurface() = %v, want the passport", resurfaced)
+	}
+	if len(sent.to) != 1 || sent.to[0] != userID {
+		t.Fatalf("sent to %v, want the user who snoozed", sent.to)
+	}
+	if got := inbox.ForUser(userID); len(got) != 1 || got[0].Kind != domain.NotificationSnooze {
+		t.Errorf("inbox = %v, want the passport back", got)
+	}
+}
+
+func Test_service_SearchResults(t *testing.T) {
+	list := fulltext.NewIndex().TodoRepository(domain.NewTodos())
+	s := NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())
+	ctx := context.Background()
+
+	party, err := s.AddWithDetails(ctx, "Throw a party", nil, domain.PriorityMedium, "Social", []string{"friends"})
+	if err != nil {
+		t.Fatalf("AddWithDetails() error = %v", err)
+	}
+	cake, _ := s.Add(ctx, "Bake a cake")
+	// imported todos are indexed by the repository they are saved to, without going through the service
+	list.Add("Buy balloons for the party")
+
+	if err = s.AddComment(ctx, cake.ID, "Chocolate, for the party", uuid.New()); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	results, _ := s.SearchResults(ctx, "parties")
+	if len(results) != 3 || results[0].Todo.ID != party.ID {
+		t.Fatalf("SearchResults(parties) = %v, want all three with the party first", results)
+	}
+	for _, result := range results {
+		if len(result.Snippet) == 0 {
+			t.Errorf("SearchResults(parties) has no snippet for %q", result.Todo.Description)
+		}
+	}
+
+	if _, err = s.Update(ctx, cake.ID, false, "Bake a pie"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if todos, _ := s.Search(ctx, "cake"); len(todos) != 0 {
+		t.Errorf("Search(cake) = %v after the cake became a pie", todos)
+	}
+	if err = s.Remove(ctx, party.ID); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if todos, _ := s.Search(ctx, "friends"); len(todos) != 0 {
+		t.Errorf("Search(friends) = %v after the party was removed", todos)
+	}
+	if results, _ = s.SearchResults(ctx, ""); len(results) != 2 || results[0].Snippet != nil {
+		t.Errorf("SearchResults() = %v, want every todo without snippets", results)
+	}
+}
+
+func Test_service_UpdateVersion(t *testing.T) {
+	list := domain.NewTodos()
+	todo := list.Add("Feed the cat")
+	s := NewService(list, domain.NewUsers(), domain.NewNotifications(), NewNoopNotificationService(), NewNoopAttachmentStore())
+
+	updated, err := s.UpdateVersion(context.Background(), todo.ID, 1, false, "Feed the cats")
+	if err != nil || updated.Description != "Feed the cats" || updated.Version != 2 {
+		t.Fatalf("UpdateVersion() = %+v, %v, want version 2", updated, err)
+	}
+
+	// a change made from the version before is turned away and leaves the todo alone
+	if _, err = s.UpdateVersion(context.Background(), todo.ID, 1, true, "Feed the dog"); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("UpdateVersion() from version 1 = %v, want %v", err, ErrVersionConflict)
+	}
+	if todo = list.Get(todo.ID); todo.Description != "Feed the cats" || todo.Completed || todo.Version != 2 {
+		t.Errorf("UpdateVersion() changed the todo on a conflict: %+v", todo)
+	}
+
+	if _, err = s.UpdateVersion(context.Background(), uuid.New(), 1, true, "Nothing"); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("UpdateVersion() of a missing todo = %v, want %v", err, ErrTodoNotFound)
+	}
+}
+
+func TestSnoozeUntil(t *testing.T) {
+	// a Wednesday afternoon
+	now := time.Date(2024, time.March, 6, 14, 20, 30, 0, time.UTC)
+	tests := map[string]struct {
+		action  string
+		custom  string
+		want    time.Time
+		wantErr error
+	}{
+		"Later":        {action: "later", want: time.Date(2024, time.March, 6, 17, 20, 0, 0, time.UTC)},
+		"Tomorrow":     {action: "tomorrow", want: time.Date(2024, time.March, 7, 9, 0, 0, 0, time.UTC)},
+		"NextWeek":     {action: "next_week", want: time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)},
+		"Custom":       {action: "custom", custom: "2024-04-01T08:30", want: time.Date(2024, time.April, 1, 8, 30, 0, 0, time.UTC)},
+		"CustomBadly":  {action: "custom", custom: "soon", wantErr: ErrInvalidDate},
+		"UnknownQuick": {action: "someday", wantErr: ErrInvalidInput},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := SnoozeUntil(tt.action, tt.custom, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SnoozeUntil() error = %v, want %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("SnoozeUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
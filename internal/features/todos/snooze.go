@@ -0,0 +1,38 @@
+package todos
+
+import (
+	"time"
+)
+
+// snoozeLayout is the layout of the custom time a todo is snoozed until, as sent by a datetime-local input
+const snoozeLayout = "2006-01-02T15:04"
+
+// morning is the hour snoozed todos come back on the days after today
+const morning = 9
+
+// SnoozeUntil returns when a todo snoozed with the quick action comes back
+//
+// The actions are "later" for three hours from now, "tomorrow" and "next_week" for nine in the
+// morning of the next day and the next Monday, and "custom" for the local time in custom.
+func SnoozeUntil(action string, custom string, now time.Time) (time.Time, error) {
+	switch action {
+	case "later":
+		return now.Add(3 * time.Hour).Truncate(time.Minute), nil
+	case "tomorrow":
+		return atMorning(now.AddDate(0, 0, 1)), nil
+	case "next_week":
+		return atMorning(now.AddDate(0, 0, 7-(int(now.Weekday())+6)%7)), nil
+	case "custom":
+		until, err := time.ParseInLocation(snoozeLayout, custom, now.Location())
+		if err != nil {
+			return time.Time{}, ErrInvalidDate
+		}
+		return until, nil
+	default:
+		return time.Time{}, ErrInvalidInput
+	}
+}
+
+func atMorning(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), morning, 0, 0, 0, day.Location())
+}
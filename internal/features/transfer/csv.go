@@ -0,0 +1,365 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// csvField is a todo field that CSV columns can be mapped onto
+type csvField struct {
+	Name     string
+	Label    string
+	Required bool
+	// Aliases are the header names recognised when guessing a mapping
+	Aliases []string
+}
+
+var csvFields = []csvField{
+	{Name: "description", Label: "Description", Required: true, Aliases: []string{"description", "title", "task", "todo", "name", "summary"}},
+	{Name: "completed", Label: "Completed", Aliases: []string{"completed", "done", "complete", "status"}},
+	{Name: "priority", Label: "Priority", Aliases: []string{"priority", "pri", "importance"}},
+	{Name: "category", Label: "Category", Aliases: []string{"category", "project", "list"}},
+	{Name: "tags", Label: "Tags", Aliases: []string{"tags", "tag", "labels", "contexts"}},
+	{Name: "due_date", Label: "Due date", Aliases: []string{"due_date", "due date", "duedate", "due", "deadline"}},
+	{Name: "assigned_to", Label: "Assigned to", Aliases: []string{"assigned_to", "assigned to", "assignee", "owner"}},
+	{Name: "created_at", Label: "Created at", Aliases: []string{"created_at", "created at", "created", "creation date"}},
+	{Name: "id", Label: "Row ID", Aliases: []string{"id"}},
+	{Name: "parent_id", Label: "Parent row ID", Aliases: []string{"parent_id", "parent id", "parent"}},
+}
+
+// csvHeader is the header written by EncodeCSV
+var csvHeader = []string{
+	"id", "parent_id", "description", "completed", "priority", "category", "tags", "due_date",
+	"assigned_to", "recurring", "archived", "created_at", "updated_at", "comments",
+}
+
+// csvDateLayouts are the date formats accepted in imported date columns
+var csvDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+// ColumnMapping maps todo field names onto the CSV headers they are read from
+type ColumnMapping map[string]string
+
+// EncodeCSV writes the todos and their subtasks as CSV rows; subtasks follow their parent
+func EncodeCSV(w io.Writer, todos []*domain.Todo) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, todo := range todos {
+		if err := encodeCSVRows(cw, todo); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func encodeCSVRows(cw *csv.Writer, todo *domain.Todo) error {
+	var parentID, dueDate, assignedTo, recurring string
+	if todo.ParentID != nil {
+		parentID = todo.ParentID.String()
+	}
+	if todo.DueDate != nil {
+		dueDate = todo.DueDate.Format(time.RFC3339)
+	}
+	if todo.AssignedTo != nil {
+		assignedTo = todo.AssignedTo.String()
+	}
+	if todo.Recurring != nil {
+		recurring = todo.Recurring.Frequency
+	}
+	comments := make([]string, len(todo.Comments))
+	for i, comment := range todo.Comments {
+		comments[i] = comment.Content
+	}
+
+	err := cw.Write([]string{
+		todo.ID.String(),
+		parentID,
+		escapeCSVCell(todo.Description),
+		strconv.FormatBool(todo.Completed),
+		todo.Priority.String(),
+		escapeCSVCell(todo.Category),
+		escapeCSVCell(strings.Join(todo.Tags, ", ")),
+		dueDate,
+		assignedTo,
+		recurring,
+		strconv.FormatBool(todo.Archived),
+		todo.CreatedAt.Format(time.RFC3339),
+		todo.UpdatedAt.Format(time.RFC3339),
+		escapeCSVCell(strings.Join(comments, "\n")),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, subtask := range todo.Subtasks {
+		if err = encodeCSVRows(cw, subtask); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeCSVCell keeps a spreadsheet from running text that looks like a formula, by starting it with a quote
+// that the spreadsheet shows as text
+func escapeCSVCell(v string) string {
+	if isFormula(v) {
+		return "'" + v
+	}
+	return v
+}
+
+// unescapeCSVCell takes off the quote escapeCSVCell put on, so exported todos import as they were
+func unescapeCSVCell(v string) string {
+	if strings.HasPrefix(v, "'") && isFormula(v[1:]) {
+		return v[1:]
+	}
+	return v
+}
+
+// isFormula reports whether a spreadsheet would take the text for a formula
+func isFormula(v string) bool {
+	return v != "" && strings.ContainsRune("=+-@", rune(v[0]))
+}
+
+// ReadCSVHeader returns the header of CSV content and a mapping guessed from it
+func ReadCSVHeader(r io.Reader) ([]string, ColumnMapping, error) {
+	header, err := csv.NewReader(r).Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return header, GuessMapping(header), nil
+}
+
+// GuessMapping maps every field onto the first header matching one of its aliases
+func GuessMapping(header []string) ColumnMapping {
+	mapping := make(ColumnMapping)
+	for _, field := range csvFields {
+		for _, alias := range field.Aliases {
+			for _, h := range header {
+				if _, mapped := mapping[field.Name]; !mapped && strings.EqualFold(strings.TrimSpace(h), alias) {
+					mapping[field.Name] = h
+				}
+			}
+		}
+	}
+	return mapping
+}
+
+// DecodeCSV parses CSV content into new, unsaved todos using the column mapping
+//
+// Rows naming a parent row ID are nested under that row. Every row is validated; the
+// returned error joins the problems found on all of them.
+func DecodeCSV(r io.Reader, mapping ColumnMapping) ([]*domain.Todo, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+
+	columns := make(map[string]int)
+	for _, field := range csvFields {
+		name, ok := mapping[field.Name]
+		if !ok || name == "" {
+			if field.Required {
+				return nil, fmt.Errorf("%w: no column is mapped to %s", ErrInvalidFormat, strings.ToLower(field.Label))
+			}
+			continue
+		}
+		index := -1
+		for i, h := range header {
+			if h == name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return nil, fmt.Errorf("%w: there is no %q column", ErrInvalidFormat, name)
+		}
+		columns[field.Name] = index
+	}
+
+	var errs []error
+	var todos []*domain.Todo
+	rows := make(map[string]*domain.Todo)
+	parents := make(map[*domain.Todo]string)
+	lines := make(map[*domain.Todo]int)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// a malformed row leaves the reader unable to find where the next one starts
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+		}
+		line, _ := cr.FieldPos(0)
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return unescapeCSVCell(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+
+		todo, err := decodeCSVRow(value)
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+			continue
+		}
+		if id := value("id"); id != "" {
+			if _, exists := rows[id]; exists {
+				errs = append(errs, &LineError{Line: line, Err: fmt.Errorf("%w: duplicate row ID %q", ErrInvalidFormat, id)})
+				continue
+			}
+			rows[id] = todo
+		}
+		if parent := value("parent_id"); parent != "" {
+			parents[todo] = parent
+		}
+		lines[todo] = line
+		todos = append(todos, todo)
+	}
+
+	roots := make([]*domain.Todo, 0, len(todos))
+	for _, todo := range todos {
+		parentID, ok := parents[todo]
+		if !ok {
+			roots = append(roots, todo)
+			continue
+		}
+		parent, ok := rows[parentID]
+		if !ok {
+			errs = append(errs, &LineError{Line: lines[todo], Err: fmt.Errorf("%w: unknown parent row ID %q", ErrInvalidFormat, parentID)})
+			continue
+		}
+		if isAncestor(todo, parent, rows, parents) {
+			errs = append(errs, &LineError{Line: lines[todo], Err: fmt.Errorf("%w: row is its own ancestor", ErrInvalidFormat)})
+			continue
+		}
+		parent.AddSubtask(todo)
+	}
+
+	return roots, errors.Join(errs...)
+}
+
+// isAncestor reports whether todo appears in the chain of parents starting at parent
+func isAncestor(todo, parent *domain.Todo, rows map[string]*domain.Todo, parents map[*domain.Todo]string) bool {
+	seen := make(map[*domain.Todo]bool)
+	for parent != nil && !seen[parent] {
+		if parent == todo {
+			return true
+		}
+		seen[parent] = true
+		parent = rows[parents[parent]]
+	}
+	return false
+}
+
+func decodeCSVRow(value func(field string) string) (*domain.Todo, error) {
+	var errs []error
+
+	todo := domain.NewTodo(value("description"))
+	if todo.Description == "" {
+		errs = append(errs, fmt.Errorf("%w: missing description", ErrInvalidFormat))
+	}
+	if v := value("completed"); v != "" {
+		completed, err := parseCompleted(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		todo.Completed = completed
+	}
+	if v := value("priority"); v != "" {
+		priority, err := parsePriority(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		todo.Priority = priority
+	}
+	todo.Category = value("category")
+	for _, tag := range strings.FieldsFunc(value("tags"), isTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			todo.Tags = append(todo.Tags, tag)
+		}
+	}
+	if v := value("due_date"); v != "" {
+		due, err := parseDate(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		todo.DueDate = &due
+	}
+	if v := value("assigned_to"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: assignee %q is not a user ID", ErrInvalidFormat, v))
+		}
+		todo.AssignedTo = &userID
+	}
+	if v := value("created_at"); v != "" {
+		createdAt, err := parseDate(v)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		todo.CreatedAt = createdAt
+	}
+
+	return todo, errors.Join(errs...)
+}
+
+func isTagSeparator(r rune) bool {
+	return r == ',' || r == ';' || r == '|'
+}
+
+func parseCompleted(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "yes", "y", "1", "x", "done", "completed", "complete":
+		return true, nil
+	case "false", "no", "n", "0", "open", "todo", "pending", "needs-action":
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: completed value %q", ErrInvalidFormat, v)
+}
+
+// parsePriority accepts priority names, todo.txt letters and the numbers 1 (high) to 3 (low)
+func parsePriority(v string) (domain.Priority, error) {
+	if priority, ok := domain.ParsePriority(v); ok {
+		return priority, nil
+	}
+	switch strings.ToUpper(v) {
+	case "A", "1":
+		return domain.PriorityHigh, nil
+	case "B", "2":
+		return domain.PriorityMedium, nil
+	case "C", "3":
+		return domain.PriorityLow, nil
+	}
+	return domain.PriorityMedium, fmt.Errorf("%w: priority %q", ErrInvalidFormat, v)
+}
+
+func parseDate(v string) (time.Time, error) {
+	for _, layout := range csvDateLayouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: date %q", ErrInvalidFormat, v)
+}
@@ -0,0 +1,217 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestGuessMapping(t *testing.T) {
+	tests := map[string]struct {
+		header []string
+		want   ColumnMapping
+	}{
+		"Export": {
+			header: csvHeader,
+			want: ColumnMapping{
+				"id":          "id",
+				"parent_id":   "parent_id",
+				"description": "description",
+				"completed":   "completed",
+				"priority":    "priority",
+				"category":    "category",
+				"tags":        "tags",
+				"due_date":    "due_date",
+				"assigned_to": "assigned_to",
+				"created_at":  "created_at",
+			},
+		},
+		"Spreadsheet": {
+			header: []string{"Task", " Done ", "Deadline", "Notes", "Labels"},
+			want: ColumnMapping{
+				"description": "Task",
+				"completed":   " Done ",
+				"due_date":    "Deadline",
+				"tags":        "Labels",
+			},
+		},
+		"Unknown": {
+			header: []string{"foo", "bar"},
+			want:   ColumnMapping{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := GuessMapping(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GuessMapping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	var due = time.Date(2026, 11, 5, 0, 0, 0, 0, time.Local)
+	var mapping = ColumnMapping{
+		"description": "Task",
+		"completed":   "Done",
+		"priority":    "Importance",
+		"tags":        "Labels",
+		"due_date":    "Deadline",
+		"id":          "Key",
+		"parent_id":   "Parent",
+	}
+	tests := map[string]struct {
+		content   string
+		mapping   ColumnMapping
+		want      []*domain.Todo
+		wantLines []int
+		wantErr   error
+	}{
+		"MappedColumns": {
+			content: "Task,Done,Importance,Labels,Deadline,Key,Parent\n" +
+				"Plan vacation,no,high,travel;planning,2026-11-05,1,\n" +
+				"Book flights,yes,,,,2,1\n",
+			mapping: mapping,
+			want: []*domain.Todo{{
+				Description: "Plan vacation",
+				Priority:    domain.PriorityHigh,
+				Tags:        []string{"travel", "planning"},
+				DueDate:     &due,
+				Subtasks: []*domain.Todo{
+					{Description: "Book flights", Completed: true, Priority: domain.PriorityMedium, Tags: []string{}},
+				},
+			}},
+		},
+		"RowErrors": {
+			content: "Task,Done,Importance,Labels,Deadline,Key,Parent\n" +
+				"Fine,,,,,,\n" +
+				",maybe,urgent,,soon,,\n" +
+				"Orphan,,,,,,99\n",
+			mapping:   mapping,
+			wantLines: []int{3, 4},
+			wantErr:   ErrInvalidFormat,
+		},
+		"DescriptionNotMapped": {
+			content: "Task\nsomething\n",
+			mapping: ColumnMapping{},
+			wantErr: ErrInvalidFormat,
+		},
+		"MappedColumnMissing": {
+			content: "Task\nsomething\n",
+			mapping: ColumnMapping{"description": "Title"},
+			wantErr: ErrInvalidFormat,
+		},
+		"ParentCycle": {
+			content:   "Task,Key,Parent\na,1,2\nb,2,1\n",
+			mapping:   ColumnMapping{"description": "Task", "id": "Key", "parent_id": "Parent"},
+			wantLines: []int{2, 3},
+			wantErr:   ErrInvalidFormat,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := DecodeCSV(strings.NewReader(tt.content), tt.mapping)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeCSV() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, line := range tt.wantLines {
+				if !strings.Contains(err.Error(), fmt.Sprintf("line %d:", line)) {
+					t.Errorf("DecodeCSV() error = %v, want a problem on line %d", err, line)
+				}
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DecodeCSV() = %d todos, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				assertSameTree(t, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	var list = domain.NewTodos()
+	var due = time.Date(2026, 12, 24, 18, 0, 0, 0, time.Local)
+
+	vacation := list.Add("Plan vacation, somewhere \"warm\"")
+	vacation.Category = "Personal"
+	vacation.Tags = []string{"travel", "planning"}
+	vacation.DueDate = &due
+	vacation.Priority = domain.PriorityHigh
+	flights := list.Add("Book flights")
+	flights.Update(true, flights.Description)
+	vacation.AddSubtask(flights)
+
+	var buf bytes.Buffer
+	if err := EncodeCSV(&buf, []*domain.Todo{vacation}); err != nil {
+		t.Fatalf("EncodeCSV() error = %v", err)
+	}
+	content := buf.String()
+	header, mapping, err := ReadCSVHeader(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ReadCSVHeader() error = %v", err)
+	}
+	if !reflect.DeepEqual(header, csvHeader) {
+		t.Errorf("ReadCSVHeader() = %v, want %v", header, csvHeader)
+	}
+	got, err := DecodeCSV(strings.NewReader(content), mapping)
+	if err != nil {
+		t.Fatalf("DecodeCSV() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("round trip = %d todos, want 1", len(got))
+	}
+	assertSameTree(t, got[0], vacation)
+}
+
+func TestEncodeCSV_formulas(t *testing.T) {
+	sum := domain.NewTodo("=SUM(A1:A9)")
+	sum.Category = "+cmd"
+	sum.Tags = []string{"@home"}
+	sum.Comments = []domain.Comment{{Content: "-2+3"}}
+
+	var buf bytes.Buffer
+	if err := EncodeCSV(&buf, []*domain.Todo{sum}); err != nil {
+		t.Fatalf("EncodeCSV() error = %v", err)
+	}
+	for _, want := range []string{"'=SUM(A1:A9)", "'+cmd", "'@home", "'-2+3"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("EncodeCSV() = %q, want %q escaped", buf.String(), want)
+		}
+	}
+
+	// the quotes are taken off again on import
+	_, mapping, _ := ReadCSVHeader(strings.NewReader(buf.String()))
+	got, err := DecodeCSV(strings.NewReader(buf.String()), mapping)
+	if err != nil {
+		t.Fatalf("DecodeCSV() error = %v", err)
+	}
+	if got[0].Description != sum.Description || got[0].Category != sum.Category || got[0].Tags[0] != "@home" {
+		t.Errorf("DecodeCSV() = %q %q %v", got[0].Description, got[0].Category, got[0].Tags)
+	}
+}
+
+// assertSameTree compares the todos, and their subtasks, on the fields that imports carry
+func assertSameTree(t *testing.T, got, want *domain.Todo) {
+	t.Helper()
+	assertSameTodo(t, got, want)
+	if len(got.Subtasks) != len(want.Subtasks) {
+		t.Fatalf("Subtasks = %d, want %d", len(got.Subtasks), len(want.Subtasks))
+	}
+	for i := range got.Subtasks {
+		if got.Subtasks[i].ParentID == nil || *got.Subtasks[i].ParentID != got.ID {
+			t.Errorf("Subtasks[%d].ParentID = %v, want %v", i, got.Subtasks[i].ParentID, got.ID)
+		}
+		assertSameTree(t, got.Subtasks[i], want.Subtasks[i])
+	}
+}
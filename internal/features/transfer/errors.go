@@ -0,0 +1,40 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidFormat   = errors.New("invalid format")
+	ErrNothingToImport = errors.New("nothing to import")
+	ErrTodoNotFound    = errors.New("todo not found")
+)
+
+// LineError locates a problem in imported content
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// RecordError locates a problem in an imported record
+type RecordError struct {
+	Record string
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %s: %v", e.Record, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
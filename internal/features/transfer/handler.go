@@ -0,0 +1,315 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+// maxUploadSize limits the size of uploaded import files
+const maxUploadSize = 10 << 20
+
+const markdownContentType = "text/markdown; charset=utf-8"
+
+type (
+	Handler interface {
+		// ExportTodoTxt : GET /export/todo.txt
+		ExportTodoTxt(w http.ResponseWriter, r *http.Request)
+		// ExportCSV : GET /export/csv
+		ExportCSV(w http.ResponseWriter, r *http.Request)
+		// ExportJSON : GET /export/json
+		ExportJSON(w http.ResponseWriter, r *http.Request)
+		// ExportMarkdown : GET /export/markdown
+		ExportMarkdown(w http.ResponseWriter, r *http.Request)
+		// ExportMarkdownTree : GET /export/markdown/{todoId}
+		ExportMarkdownTree(w http.ResponseWriter, r *http.Request)
+		// Import : GET /import
+		Import(w http.ResponseWriter, r *http.Request)
+		// ImportTodoTxt : POST /import/todo.txt
+		ImportTodoTxt(w http.ResponseWriter, r *http.Request)
+		// ImportCSV : POST /import/csv
+		ImportCSV(w http.ResponseWriter, r *http.Request)
+		// ImportJSON : POST /import/json
+		ImportJSON(w http.ResponseWriter, r *http.Request)
+		// ImportMarkdown : POST /import/markdown
+		ImportMarkdown(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/export", func(r chi.Router) {
+		r.Get("/todo.txt", h.ExportTodoTxt)
+		r.Get("/csv", h.ExportCSV)
+		r.Get("/json", h.ExportJSON)
+		r.Get("/markdown", h.ExportMarkdown)
+		r.Get("/markdown/{todoId}", h.ExportMarkdownTree)
+	})
+	r.Route("/import", func(r chi.Router) {
+		r.Get("/", h.Import)
+		r.Post("/todo.txt", h.ImportTodoTxt)
+		r.Post("/csv", h.ImportCSV)
+		r.Post("/json", h.ImportJSON)
+		r.Post("/markdown", h.ImportMarkdown)
+	})
+}
+
+func (h handler) ExportTodoTxt(w http.ResponseWriter, r *http.Request) {
+	export(w, r, "text/plain; charset=utf-8", "todo.txt", h.service.ExportTodoTxt)
+}
+
+func (h handler) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	export(w, r, "text/csv; charset=utf-8", "todos.csv", h.service.ExportCSV)
+}
+
+func (h handler) ExportJSON(w http.ResponseWriter, r *http.Request) {
+	export(w, r, "application/json", "todos.json", h.service.ExportJSON)
+}
+
+func (h handler) ExportMarkdown(w http.ResponseWriter, r *http.Request) {
+	export(w, r, markdownContentType, "todos.md", h.service.ExportMarkdown)
+}
+
+func (h handler) ExportMarkdownTree(w http.ResponseWriter, r *http.Request) {
+	var id = chi.URLParam(r, "todoId")
+	var todoID uuid.UUID
+	var err error
+	if todoID, err = uuid.Parse(id); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = h.service.ExportMarkdownTree(r.Context(), todoID, &buf); err != nil {
+		switch {
+		case errors.Is(err, ErrTodoNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", markdownContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="todo-`+todoID.String()+`.md"`)
+	_, _ = buf.WriteTo(w)
+}
+
+func (h handler) Import(w http.ResponseWriter, r *http.Request) {
+	if err := pages.ImportPage().Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) ImportTodoTxt(w http.ResponseWriter, r *http.Request) {
+	content, err := importContent(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Form.Get("confirm") != "true" {
+		todos, err := h.service.PreviewTodoTxt(r.Context(), strings.NewReader(content))
+		renderPreview(w, r, "/import/todo.txt", todos, content, err)
+		return
+	}
+
+	if _, err = h.service.ImportTodoTxt(r.Context(), strings.NewReader(content)); err != nil {
+		renderPreview(w, r, "/import/todo.txt", nil, content, err)
+		return
+	}
+
+	redirectHome(w, r)
+}
+
+func (h handler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	content, err := importContent(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the first step only chooses which columns supply which fields
+	if r.Form.Get("mapped") != "true" {
+		header, mapping, err := h.service.CSVHeader(r.Context(), strings.NewReader(content))
+		if err != nil {
+			renderPreview(w, r, "/import/csv", nil, content, err)
+			return
+		}
+		if err = pages.ImportMappingPage("/import/csv", header, mappingColumns(mapping), content).Render(r.Context(), w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	mapping := make(ColumnMapping)
+	params := url.Values{"mapped": {"true"}}
+	for _, field := range csvFields {
+		if header := r.Form.Get("map_" + field.Name); header != "" {
+			mapping[field.Name] = header
+			params.Set("map_"+field.Name, header)
+		}
+	}
+	// the preview posts back here so the mapping travels in the URL
+	action := "/import/csv?" + params.Encode()
+
+	if r.Form.Get("confirm") != "true" {
+		todos, err := h.service.PreviewCSV(r.Context(), strings.NewReader(content), mapping)
+		renderPreview(w, r, action, todos, content, err)
+		return
+	}
+
+	if _, err = h.service.ImportCSV(r.Context(), strings.NewReader(content), mapping); err != nil {
+		renderPreview(w, r, action, nil, content, err)
+		return
+	}
+
+	redirectHome(w, r)
+}
+
+func (h handler) ImportJSON(w http.ResponseWriter, r *http.Request) {
+	content, err := importContent(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Form.Get("confirm") != "true" {
+		todos, err := h.service.PreviewJSON(r.Context(), strings.NewReader(content))
+		renderPreview(w, r, "/import/json", todos, content, err)
+		return
+	}
+
+	if _, err = h.service.ImportJSON(r.Context(), strings.NewReader(content)); err != nil {
+		renderPreview(w, r, "/import/json", nil, content, err)
+		return
+	}
+
+	redirectHome(w, r)
+}
+
+func (h handler) ImportMarkdown(w http.ResponseWriter, r *http.Request) {
+	content, err := importContent(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Form.Get("confirm") != "true" {
+		todos, err := h.service.PreviewMarkdown(r.Context(), strings.NewReader(content))
+		renderPreview(w, r, "/import/markdown", todos, content, err)
+		return
+	}
+
+	if _, err = h.service.ImportMarkdown(r.Context(), strings.NewReader(content)); err != nil {
+		renderPreview(w, r, "/import/markdown", nil, content, err)
+		return
+	}
+
+	redirectHome(w, r)
+}
+
+// export writes the todos matching the query parameters as a file download
+func export(w http.ResponseWriter, r *http.Request, contentType, filename string, write func(context.Context, domain.Query, io.Writer) error) {
+	query, err := todos.ParseQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = write(r.Context(), query, &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	_, _ = buf.WriteTo(w)
+}
+
+// importContent returns the uploaded file, or the pasted content when no file was sent
+func importContent(w http.ResponseWriter, r *http.Request) (string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return "", err
+	}
+
+	file, _, err := r.FormFile("file")
+	switch {
+	case errors.Is(err, http.ErrMissingFile), errors.Is(err, http.ErrNotMultipart):
+		return r.Form.Get("content"), nil
+	case err != nil:
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func mappingColumns(mapping ColumnMapping) []partials.ImportColumn {
+	columns := make([]partials.ImportColumn, len(csvFields))
+	for i, field := range csvFields {
+		columns[i] = partials.ImportColumn{
+			Field:    "map_" + field.Name,
+			Label:    field.Label,
+			Required: field.Required,
+			Header:   mapping[field.Name],
+		}
+	}
+	return columns
+}
+
+func renderPreview(w http.ResponseWriter, r *http.Request, action string, todos []*domain.Todo, content string, err error) {
+	var problems []string
+	if err != nil {
+		problems = strings.Split(err.Error(), "\n")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	if err := pages.ImportPreviewPage(action, todos, content, problems).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func redirectHome(w http.ResponseWriter, r *http.Request) {
+	switch isHTMX(r) {
+	case true:
+		w.Header().Set("HX-Redirect", "/")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Redirect(w, r, "/", http.StatusFound)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,207 @@
+package transfer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	todoRecord struct {
+		ID          uuid.UUID        `json:"id"`
+		Description string           `json:"description"`
+		Completed   bool             `json:"completed"`
+		Priority    string           `json:"priority"`
+		Category    string           `json:"category,omitempty"`
+		Tags        []string         `json:"tags"`
+		DueDate     *time.Time       `json:"dueDate,omitempty"`
+		AssignedTo  *uuid.UUID       `json:"assignedTo,omitempty"`
+		Recurring   *recurringRecord `json:"recurring,omitempty"`
+		Archived    bool             `json:"archived,omitempty"`
+		CreatedAt   time.Time        `json:"createdAt"`
+		UpdatedAt   time.Time        `json:"updatedAt"`
+		Comments    []commentRecord  `json:"comments"`
+		Subtasks    []todoRecord     `json:"subtasks"`
+	}
+
+	recurringRecord struct {
+		Frequency      string     `json:"frequency"`
+		EndDate        *time.Time `json:"endDate,omitempty"`
+		LastOccurrence time.Time  `json:"lastOccurrence"`
+		// Occurrences are when the todo was completed
+		Occurrences []time.Time `json:"occurrences,omitempty"`
+	}
+
+	commentRecord struct {
+		ID        uuid.UUID  `json:"id"`
+		ParentID  *uuid.UUID `json:"parentId,omitempty"`
+		Content   string     `json:"content"`
+		CreatedAt time.Time  `json:"createdAt"`
+		EditedAt  *time.Time `json:"editedAt,omitempty"`
+		UserID    uuid.UUID  `json:"userId"`
+	}
+)
+
+// EncodeJSON writes the todos as a JSON array with their subtasks nested inside them
+func EncodeJSON(w io.Writer, todos []*domain.Todo) error {
+	records := make([]todoRecord, len(todos))
+	for i, todo := range todos {
+		records[i] = newTodoRecord(todo)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// DecodeJSON parses JSON written by EncodeJSON into new, unsaved todos
+//
+// Every record is validated; the returned error joins the problems found in all of them.
+func DecodeJSON(r io.Reader) ([]*domain.Todo, error) {
+	var records []todoRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+
+	var errs []error
+	todos := make([]*domain.Todo, 0, len(records))
+	for i, record := range records {
+		todo, err := record.todo(fmt.Sprintf("%d", i+1))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, errors.Join(errs...)
+}
+
+func newTodoRecord(todo *domain.Todo) todoRecord {
+	record := todoRecord{
+		ID:          todo.ID,
+		Description: todo.Description,
+		Completed:   todo.Completed,
+		Priority:    todo.Priority.String(),
+		Category:    todo.Category,
+		Tags:        todo.Tags,
+		DueDate:     todo.DueDate,
+		AssignedTo:  todo.AssignedTo,
+		Archived:    todo.Archived,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		Comments:    make([]commentRecord, len(todo.Comments)),
+		Subtasks:    make([]todoRecord, len(todo.Subtasks)),
+	}
+	if record.Tags == nil {
+		record.Tags = make([]string, 0)
+	}
+	if todo.Recurring != nil {
+		record.Recurring = &recurringRecord{
+			Frequency:      todo.Recurring.Frequency,
+			EndDate:        todo.Recurring.EndDate,
+			LastOccurrence: todo.Recurring.LastOccurrence,
+			Occurrences:    todo.Occurrences,
+		}
+	}
+	for i, comment := range todo.Comments {
+		record.Comments[i] = commentRecord{
+			ID:        comment.ID,
+			ParentID:  comment.ParentID,
+			Content:   comment.Content,
+			CreatedAt: comment.CreatedAt,
+			EditedAt:  comment.EditedAt,
+			UserID:    comment.UserID,
+		}
+	}
+	for i, subtask := range todo.Subtasks {
+		record.Subtasks[i] = newTodoRecord(subtask)
+	}
+	return record
+}
+
+// todo validates the record and converts it; path locates the record for error messages
+func (r todoRecord) todo(path string) (*domain.Todo, error) {
+	var errs []error
+
+	todo := domain.NewTodo(strings.TrimSpace(r.Description))
+	if todo.Description == "" {
+		errs = append(errs, &RecordError{Record: path, Err: fmt.Errorf("%w: missing description", ErrInvalidFormat)})
+	}
+	if r.Priority != "" {
+		priority, ok := domain.ParsePriority(r.Priority)
+		if !ok {
+			errs = append(errs, &RecordError{Record: path, Err: fmt.Errorf("%w: priority %q", ErrInvalidFormat, r.Priority)})
+		}
+		todo.Priority = priority
+	}
+	todo.Completed = r.Completed
+	todo.Category = r.Category
+	if r.Tags != nil {
+		todo.Tags = r.Tags
+	}
+	todo.DueDate = r.DueDate
+	todo.AssignedTo = r.AssignedTo
+	todo.Archived = r.Archived
+	if !r.CreatedAt.IsZero() {
+		todo.CreatedAt = r.CreatedAt
+	}
+	if r.Recurring != nil {
+		if r.Recurring.Frequency == "" {
+			errs = append(errs, &RecordError{Record: path, Err: fmt.Errorf("%w: missing recurring frequency", ErrInvalidFormat)})
+		}
+		todo.Recurring = &domain.RecurringConfig{
+			Frequency:      r.Recurring.Frequency,
+			EndDate:        r.Recurring.EndDate,
+			LastOccurrence: r.Recurring.LastOccurrence,
+		}
+		todo.Occurrences = r.Recurring.Occurrences
+	}
+	// comments get new IDs so replies are pointed at the new ID of the comment they reply to
+	commentIDs := make(map[uuid.UUID]uuid.UUID, len(r.Comments))
+	for i, c := range r.Comments {
+		if strings.TrimSpace(c.Content) == "" {
+			errs = append(errs, &RecordError{Record: fmt.Sprintf("%s comment %d", path, i+1), Err: fmt.Errorf("%w: missing content", ErrInvalidFormat)})
+			continue
+		}
+		comment := domain.Comment{ID: uuid.New(), Content: c.Content, CreatedAt: c.CreatedAt, EditedAt: c.EditedAt, UserID: c.UserID}
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = time.Now()
+		}
+		if c.ParentID != nil {
+			parentID, ok := commentIDs[*c.ParentID]
+			if !ok {
+				errs = append(errs, &RecordError{Record: fmt.Sprintf("%s comment %d", path, i+1), Err: fmt.Errorf("%w: reply to an unknown comment", ErrInvalidFormat)})
+				continue
+			}
+			comment.ParentID = &parentID
+		}
+		if c.ID != uuid.Nil {
+			commentIDs[c.ID] = comment.ID
+		}
+		todo.Comments = append(todo.Comments, comment)
+	}
+	for i, s := range r.Subtasks {
+		subtask, err := s.todo(fmt.Sprintf("%s.%d", path, i+1))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		todo.AddSubtask(subtask)
+	}
+	if !r.UpdatedAt.IsZero() {
+		todo.UpdatedAt = r.UpdatedAt
+	}
+
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	return todo, nil
+}
@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestJSON_RoundTrip(t *testing.T) {
+	var list = domain.NewTodos()
+	var due = time.Date(2026, 12, 24, 18, 0, 0, 0, time.Local)
+
+	vacation := list.Add("Plan vacation")
+	vacation.Category = "Personal"
+	vacation.Tags = []string{"travel"}
+	vacation.DueDate = &due
+	vacation.Recurring = &domain.RecurringConfig{Frequency: "yearly"}
+	question := vacation.AddComment("somewhere warm", uuid.New())
+	vacation.AddReply(question.ID, "and sunny", uuid.New())
+	flights := list.Add("Book flights")
+	vacation.AddSubtask(flights)
+	seats := list.Add("Pick seats")
+	flights.AddSubtask(seats)
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, []*domain.Todo{vacation}); err != nil {
+		t.Fatalf("EncodeJSON() error = %v", err)
+	}
+	got, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("round trip = %d todos, want 1", len(got))
+	}
+	if got[0].ID == vacation.ID {
+		t.Errorf("ID = %v, want a new ID", got[0].ID)
+	}
+	if comments := got[0].Comments; len(comments) != 2 || comments[0].Content != "somewhere warm" ||
+		comments[1].ParentID == nil || *comments[1].ParentID != comments[0].ID {
+		t.Errorf("Comments = %v, want the original comment and the reply to it", comments)
+	}
+	assertSameTree(t, got[0], vacation)
+}
+
+func TestDecodeJSON_Errors(t *testing.T) {
+	content := `[
+		{"description": "fine"},
+		{"description": " ", "priority": "urgent"},
+		{"description": "parent", "subtasks": [{"description": ""}]}
+	]`
+
+	_, err := DecodeJSON(strings.NewReader(content))
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("DecodeJSON() error = %v, want %v", err, ErrInvalidFormat)
+	}
+	for _, record := range []string{"record 2:", "record 3.1:"} {
+		if !strings.Contains(err.Error(), record) {
+			t.Errorf("DecodeJSON() error = %v, want a problem in %s", err, record)
+		}
+	}
+}
+
+func TestService_ImportJSON(t *testing.T) {
+	list := domain.NewTodos()
+	list.Add("existing")
+	svc := NewService(list)
+
+	content := `[{"description": "parent", "subtasks": [{"description": "child"}]}, {"description": "other"}]`
+	imported, err := svc.ImportJSON(context.Background(), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ImportJSON() error = %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("ImportJSON() = %d todos, want 2", len(imported))
+	}
+	child := imported[0].Subtasks[0]
+	if list.Get(child.ID) == nil || *child.ParentID != imported[0].ID {
+		t.Errorf("subtask was not saved under its parent")
+	}
+	if got := len(list.All()); got != 4 {
+		t.Errorf("list has %d todos, want 4", got)
+	}
+
+	if _, err = svc.ImportJSON(context.Background(), strings.NewReader(`[{"description": "a"}, {"description": ""}]`)); err == nil {
+		t.Fatalf("ImportJSON() error = nil, want an error")
+	}
+	if got := len(list.All()); got != 4 {
+		t.Errorf("list has %d todos after a failed import, want 4", got)
+	}
+}
+
+func TestService_importAll_invalid(t *testing.T) {
+	list := domain.NewTodos()
+	svc := NewService(list).(*service)
+	loop := domain.NewTodo("loop")
+	loop.Subtasks = []*domain.Todo{loop}
+
+	tests := map[string][]*domain.Todo{
+		"missing todo":   {domain.NewTodo("a"), nil},
+		"no description": {domain.NewTodo("a"), domain.NewTodo(" ")},
+		"under itself":   {loop},
+	}
+	for name, todos := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := svc.importAll(todos); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("importAll() error = %v, want %v", err, ErrInvalidFormat)
+			}
+			if got := len(list.All()); got != 0 {
+				t.Errorf("list has %d todos after a failed import, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package transfer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// markdownIndent is the indentation written for each level of subtasks
+const markdownIndent = "  "
+
+// markdownTabWidth is the number of columns a tab counts for when reading indentation
+const markdownTabWidth = 4
+
+// markdownItem matches a checklist item: a bullet or numbered list marker followed by a checkbox
+var markdownItem = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+\[([ xX])\](?:\s+(.*))?$`)
+
+// EncodeMarkdown writes the todos as a Markdown checklist with subtasks nested under their parent
+//
+// Due dates and tags are written after the description as "due:2006-01-02" and "#tag"; spaces in
+// tags are written as underscores so that they remain a single word.
+func EncodeMarkdown(w io.Writer, todos []*domain.Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, todo := range todos {
+		if err := encodeMarkdownItems(bw, todo, 0); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func encodeMarkdownItems(bw *bufio.Writer, todo *domain.Todo, depth int) error {
+	if _, err := bw.WriteString(strings.Repeat(markdownIndent, depth) + encodeMarkdownItem(todo) + "\n"); err != nil {
+		return err
+	}
+	for _, subtask := range todo.Subtasks {
+		if err := encodeMarkdownItems(bw, subtask, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeMarkdownItem(todo *domain.Todo) string {
+	var parts []string
+
+	if todo.Completed {
+		parts = append(parts, "- [x]")
+	} else {
+		parts = append(parts, "- [ ]")
+	}
+	for _, word := range strings.Fields(todo.Description) {
+		// escaped so that the word is not read back as a tag
+		if strings.HasPrefix(word, "#") {
+			word = `\` + word
+		}
+		parts = append(parts, word)
+	}
+	if todo.DueDate != nil {
+		parts = append(parts, "due:"+todo.DueDate.Format(todoTxtDate))
+	}
+	for _, tag := range todo.Tags {
+		parts = append(parts, "#"+todoTxtWord(tag))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// DecodeMarkdown parses the checklist items in Markdown content into new, unsaved todos
+//
+// Items indented below another item become its subtasks. Everything that is not a checklist
+// item, such as headings, paragraphs, plain list items and fenced code, is skipped. Every item
+// is parsed; the returned error joins the problems found on all of them.
+func DecodeMarkdown(r io.Reader) ([]*domain.Todo, error) {
+	type level struct {
+		indent int
+		todo   *domain.Todo
+	}
+
+	var todos []*domain.Todo
+	var errs []error
+	var parents []level
+	var fenced bool
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if trimmed := strings.TrimSpace(text); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		match := markdownItem.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		todo, err := decodeMarkdownItem(match[2] != " ", match[3])
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+		}
+
+		indent := markdownIndentWidth(match[1])
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		switch {
+		case len(parents) > 0:
+			parents[len(parents)-1].todo.AddSubtask(todo)
+		default:
+			todos = append(todos, todo)
+		}
+		parents = append(parents, level{indent: indent, todo: todo})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, errors.Join(errs...)
+}
+
+// decodeMarkdownItem reads the due date and tags from the end of the item text; the rest is the description
+func decodeMarkdownItem(completed bool, text string) (*domain.Todo, error) {
+	var errs []error
+
+	todo := domain.NewTodo("")
+	todo.Completed = completed
+
+	fields := strings.Fields(text)
+	var tags []string
+metadata:
+	for len(fields) > 0 {
+		field := fields[len(fields)-1]
+		switch {
+		case len(field) > 1 && field[0] == '#' && field[1] != '#':
+			tags = append(tags, fromTodoTxtWord(field[1:]))
+		case strings.HasPrefix(field, "due:") && todo.DueDate == nil:
+			due, err := time.ParseInLocation(todoTxtDate, strings.TrimPrefix(field, "due:"), time.Local)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: due date %q", ErrInvalidFormat, field))
+			}
+			todo.DueDate = &due
+		default:
+			break metadata
+		}
+		fields = fields[:len(fields)-1]
+	}
+
+	// the tags were collected from the end of the line
+	for i := len(tags) - 1; i >= 0; i-- {
+		todo.Tags = append(todo.Tags, tags[i])
+	}
+	for i, field := range fields {
+		if strings.HasPrefix(field, `\#`) {
+			fields[i] = field[1:]
+		}
+	}
+	todo.Description = strings.Join(fields, " ")
+	if todo.Description == "" {
+		errs = append(errs, fmt.Errorf("%w: missing description", ErrInvalidFormat))
+	}
+
+	return todo, errors.Join(errs...)
+}
+
+func markdownIndentWidth(indent string) int {
+	var width int
+	for _, r := range indent {
+		switch r {
+		case '\t':
+			width += markdownTabWidth - width%markdownTabWidth
+		default:
+			width++
+		}
+	}
+	return width
+}
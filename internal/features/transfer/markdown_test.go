@@ -0,0 +1,135 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestEncodeMarkdown(t *testing.T) {
+	var due = time.Date(2026, 11, 5, 18, 0, 0, 0, time.Local)
+
+	vacation := domain.NewTodo("Plan vacation")
+	vacation.DueDate = &due
+	vacation.Tags = []string{"travel", "long weekend"}
+	flights := domain.NewTodo("Book #1 choice\nof flights")
+	flights.Completed = true
+	vacation.AddSubtask(flights)
+	seats := domain.NewTodo("Pick seats")
+	flights.AddSubtask(seats)
+
+	var buf bytes.Buffer
+	if err := EncodeMarkdown(&buf, []*domain.Todo{vacation}); err != nil {
+		t.Fatalf("EncodeMarkdown() error = %v", err)
+	}
+
+	want := "- [ ] Plan vacation due:2026-11-05 #travel #long_weekend\n" +
+		"  - [x] Book \\#1 choice of flights\n" +
+		"    - [ ] Pick seats\n"
+	if got := buf.String(); got != want {
+		t.Errorf("EncodeMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeMarkdown(t *testing.T) {
+	var due = time.Date(2026, 11, 5, 0, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		content   string
+		want      []*domain.Todo
+		wantLines []int
+		wantErr   error
+	}{
+		"Nested": {
+			content: "# Trip\n" +
+				"Some notes about the trip.\n\n" +
+				"- [ ] Plan vacation due:2026-11-05 #travel #long_weekend\n" +
+				"  - [X] Book flights\n" +
+				"\t- [ ] Pick seats\n" +
+				"  * [ ] Pack\n" +
+				"1. [x] Ask for time off\n" +
+				"- a plain item\n" +
+				"```\n- [ ] not a task\n```\n",
+			want: []*domain.Todo{
+				{
+					Description: "Plan vacation",
+					Priority:    domain.PriorityMedium,
+					DueDate:     &due,
+					Tags:        []string{"travel", "long weekend"},
+					Subtasks: []*domain.Todo{
+						{
+							Description: "Book flights",
+							Priority:    domain.PriorityMedium,
+							Completed:   true,
+							Tags:        []string{},
+							Subtasks:    []*domain.Todo{{Priority: domain.PriorityMedium, Description: "Pick seats", Tags: []string{}}},
+						},
+						{Priority: domain.PriorityMedium, Description: "Pack", Tags: []string{}},
+					},
+				},
+				{Priority: domain.PriorityMedium, Description: "Ask for time off", Completed: true, Tags: []string{}},
+			},
+		},
+		"MetadataOnlyAtTheEnd": {
+			content: "- [ ] Fix #12 before due:tomorrow is over \\#hashtag #work\n",
+			want: []*domain.Todo{
+				{Priority: domain.PriorityMedium, Description: "Fix #12 before due:tomorrow is over #hashtag", Tags: []string{"work"}},
+			},
+		},
+		"Errors": {
+			content:   "- [ ] fine\n- [ ]\n- [ ] Pay rent due:soon\n",
+			wantLines: []int{2, 3},
+			wantErr:   ErrInvalidFormat,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := DecodeMarkdown(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeMarkdown() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, line := range tt.wantLines {
+				if !strings.Contains(err.Error(), (&LineError{Line: line, Err: ErrInvalidFormat}).Error()) {
+					t.Errorf("DecodeMarkdown() error = %v, want a problem on line %d", err, line)
+				}
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DecodeMarkdown() = %d todos, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				assertSameTree(t, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestMarkdown_RoundTrip(t *testing.T) {
+	var due = time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local)
+
+	vacation := domain.NewTodo("Plan #2 vacation")
+	vacation.DueDate = &due
+	vacation.Tags = []string{"travel", "long weekend"}
+	flights := domain.NewTodo("Book flights")
+	flights.Completed = true
+	vacation.AddSubtask(flights)
+
+	var buf bytes.Buffer
+	if err := EncodeMarkdown(&buf, []*domain.Todo{vacation}); err != nil {
+		t.Fatalf("EncodeMarkdown() error = %v", err)
+	}
+	got, err := DecodeMarkdown(&buf)
+	if err != nil {
+		t.Fatalf("DecodeMarkdown() error = %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("round trip = %d todos, want 1", len(got))
+	}
+	assertSameTree(t, got[0], vacation)
+}
@@ -0,0 +1,252 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// ExportTodoTxt writes the todos matching the query in the todo.txt format
+		ExportTodoTxt(ctx context.Context, query domain.Query, w io.Writer) error
+		// PreviewTodoTxt parses todo.txt content without adding anything to the list
+		PreviewTodoTxt(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+		// ImportTodoTxt parses todo.txt content and adds every todo to the list
+		ImportTodoTxt(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+
+		// ExportCSV writes the todos matching the query, and their subtasks, as CSV
+		ExportCSV(ctx context.Context, query domain.Query, w io.Writer) error
+		// CSVHeader returns the header of CSV content and the column mapping guessed from it
+		CSVHeader(ctx context.Context, r io.Reader) ([]string, ColumnMapping, error)
+		// PreviewCSV parses CSV content without adding anything to the list
+		PreviewCSV(ctx context.Context, r io.Reader, mapping ColumnMapping) ([]*domain.Todo, error)
+		// ImportCSV parses CSV content and adds every todo to the list, or none when any row is invalid
+		ImportCSV(ctx context.Context, r io.Reader, mapping ColumnMapping) ([]*domain.Todo, error)
+
+		// ExportJSON writes the todos matching the query, with their subtasks and comments, as JSON
+		ExportJSON(ctx context.Context, query domain.Query, w io.Writer) error
+		// PreviewJSON parses JSON content without adding anything to the list
+		PreviewJSON(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+		// ImportJSON parses JSON content and adds every todo to the list, or none when any record is invalid
+		ImportJSON(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+
+		// ExportMarkdown writes the todos matching the query, and their subtasks, as a Markdown checklist
+		ExportMarkdown(ctx context.Context, query domain.Query, w io.Writer) error
+		// ExportMarkdownTree writes a single todo and its subtasks as a Markdown checklist
+		ExportMarkdownTree(ctx context.Context, todoID uuid.UUID, w io.Writer) error
+		// PreviewMarkdown parses a Markdown checklist without adding anything to the list
+		PreviewMarkdown(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+		// ImportMarkdown parses a Markdown checklist and adds every todo to the list, or none when any item is invalid
+		ImportMarkdown(ctx context.Context, r io.Reader) ([]*domain.Todo, error)
+	}
+
+	service struct {
+		todos domain.TodoRepository
+	}
+)
+
+func NewService(todos domain.TodoRepository) Service {
+	return &service{
+		todos: todos,
+	}
+}
+
+func (s service) ExportTodoTxt(_ context.Context, query domain.Query, w io.Writer) error {
+	return EncodeTodoTxt(w, s.todos.Find(query))
+}
+
+func (s service) PreviewTodoTxt(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	return DecodeTodoTxt(r)
+}
+
+func (s service) ImportTodoTxt(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	todos, err := DecodeTodoTxt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importAll(todos)
+}
+
+func (s service) ExportCSV(_ context.Context, query domain.Query, w io.Writer) error {
+	return EncodeCSV(w, s.roots(query))
+}
+
+func (s service) CSVHeader(_ context.Context, r io.Reader) ([]string, ColumnMapping, error) {
+	return ReadCSVHeader(r)
+}
+
+func (s service) PreviewCSV(_ context.Context, r io.Reader, mapping ColumnMapping) ([]*domain.Todo, error) {
+	return DecodeCSV(r, mapping)
+}
+
+func (s service) ImportCSV(_ context.Context, r io.Reader, mapping ColumnMapping) ([]*domain.Todo, error) {
+	todos, err := DecodeCSV(r, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importAll(todos)
+}
+
+func (s service) ExportJSON(_ context.Context, query domain.Query, w io.Writer) error {
+	return EncodeJSON(w, s.roots(query))
+}
+
+func (s service) PreviewJSON(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	return DecodeJSON(r)
+}
+
+func (s service) ImportJSON(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	todos, err := DecodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importAll(todos)
+}
+
+func (s service) ExportMarkdown(_ context.Context, query domain.Query, w io.Writer) error {
+	return EncodeMarkdown(w, s.roots(query))
+}
+
+func (s service) ExportMarkdownTree(_ context.Context, todoID uuid.UUID, w io.Writer) error {
+	todo := s.todos.Get(todoID)
+	if todo == nil {
+		return ErrTodoNotFound
+	}
+	return EncodeMarkdown(w, []*domain.Todo{todo})
+}
+
+func (s service) PreviewMarkdown(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	return DecodeMarkdown(r)
+}
+
+func (s service) ImportMarkdown(_ context.Context, r io.Reader) ([]*domain.Todo, error) {
+	todos, err := DecodeMarkdown(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importAll(todos)
+}
+
+// roots returns the todos matching the query, leaving out subtasks that are exported within a matching parent
+func (s service) roots(query domain.Query) []*domain.Todo {
+	matches := s.todos.Find(query)
+
+	matched := make(map[uuid.UUID]bool, len(matches))
+	for _, todo := range matches {
+		matched[todo.ID] = true
+	}
+
+	list := make([]*domain.Todo, 0, len(matches))
+	for _, todo := range matches {
+		if todo.ParentID != nil && s.hasMatchedAncestor(todo, matched) {
+			continue
+		}
+		list = append(list, todo)
+	}
+	return list
+}
+
+func (s service) hasMatchedAncestor(todo *domain.Todo, matched map[uuid.UUID]bool) bool {
+	seen := make(map[uuid.UUID]bool)
+	for todo.ParentID != nil && !seen[todo.ID] {
+		seen[todo.ID] = true
+		if todo = s.todos.Get(*todo.ParentID); todo == nil {
+			return false
+		}
+		if matched[todo.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// importAll adds the decoded todos and their subtasks as a single unit
+//
+// The todos are checked before any is added and saved all at once, so the list never holds part of an import.
+func (s service) importAll(todos []*domain.Todo) ([]*domain.Todo, error) {
+	if len(todos) == 0 {
+		return nil, ErrNothingToImport
+	}
+	if err := validateImport(todos); err != nil {
+		return nil, err
+	}
+
+	var added []*domain.Todo
+	var add func(todo *domain.Todo, parent *domain.Todo) *domain.Todo
+	add = func(todo *domain.Todo, parent *domain.Todo) *domain.Todo {
+		todoAdded := fresh(todo)
+		added = append(added, todoAdded)
+		if parent != nil {
+			parentID := parent.ID
+			todoAdded.ParentID = &parentID
+			parent.Subtasks = append(parent.Subtasks, todoAdded)
+		}
+		for _, subtask := range todo.Subtasks {
+			add(subtask, todoAdded)
+		}
+		return todoAdded
+	}
+
+	imported := make([]*domain.Todo, len(todos))
+	for i, todo := range todos {
+		imported[i] = add(todo, nil)
+	}
+	s.todos.Save(added...)
+
+	return imported, nil
+}
+
+// validateImport returns why the decoded todos cannot be imported: a todo is missing or has no description, or
+// is found twice in the tree, as a todo under itself would be
+func validateImport(todos []*domain.Todo) error {
+	seen := make(map[*domain.Todo]bool)
+	var check func(list []*domain.Todo) error
+	check = func(list []*domain.Todo) error {
+		for _, todo := range list {
+			if todo == nil {
+				return fmt.Errorf("%w: a todo is missing", ErrInvalidFormat)
+			}
+			if seen[todo] {
+				return fmt.Errorf("%w: %q is under more than one parent or under itself", ErrInvalidFormat, todo.Description)
+			}
+			seen[todo] = true
+			if strings.TrimSpace(todo.Description) == "" {
+				return fmt.Errorf("%w: a todo has no description", ErrInvalidFormat)
+			}
+			if err := check(todo.Subtasks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return check(todos)
+}
+
+// fresh returns a new todo with everything of a decoded todo but its identity and subtasks
+func fresh(todo *domain.Todo) *domain.Todo {
+	added := domain.NewTodo(todo.Description)
+	added.Completed = todo.Completed
+	added.CreatedAt = todo.CreatedAt
+	added.DueDate = todo.DueDate
+	added.Priority = todo.Priority
+	added.Category = todo.Category
+	added.Tags = todo.Tags
+	added.AssignedTo = todo.AssignedTo
+	added.Comments = todo.Comments
+	added.Recurring = todo.Recurring
+	added.Occurrences = todo.Occurrences
+	added.Archived = todo.Archived
+	added.UpdatedAt = todo.UpdatedAt
+
+	return added
+}
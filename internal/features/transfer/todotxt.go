@@ -0,0 +1,209 @@
+package transfer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// todoTxtDate is the date layout used throughout the todo.txt format
+const todoTxtDate = "2006-01-02"
+
+// todo.txt recurrence intervals for the frequencies the app understands
+var todoTxtRecurrences = map[string]string{
+	"daily":   "1d",
+	"weekly":  "1w",
+	"monthly": "1m",
+	"yearly":  "1y",
+}
+
+// EncodeTodoTxt writes the todos in the todo.txt format, one todo per line
+//
+// Spaces in the category and tags are written as underscores so that they remain a single word.
+func EncodeTodoTxt(w io.Writer, todos []*domain.Todo) error {
+	bw := bufio.NewWriter(w)
+	for _, todo := range todos {
+		if _, err := bw.WriteString(encodeTodoTxtLine(todo) + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// DecodeTodoTxt parses todo.txt content into new, unsaved todos
+//
+// Every line is parsed; the returned error joins the problems found on all lines.
+func DecodeTodoTxt(r io.Reader) ([]*domain.Todo, error) {
+	var todos []*domain.Todo
+	var errs []error
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		todo, err := decodeTodoTxtLine(text)
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+			continue
+		}
+		todos = append(todos, todo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return todos, errors.Join(errs...)
+}
+
+func encodeTodoTxtLine(todo *domain.Todo) string {
+	var parts []string
+
+	if todo.Completed {
+		parts = append(parts, "x", todo.UpdatedAt.Format(todoTxtDate))
+	} else {
+		parts = append(parts, "("+priorityLetter(todo.Priority)+")")
+	}
+	if !todo.CreatedAt.IsZero() {
+		parts = append(parts, todo.CreatedAt.Format(todoTxtDate))
+	}
+	parts = append(parts, strings.Join(strings.Fields(todo.Description), " "))
+	if todo.Category != "" {
+		parts = append(parts, "+"+todoTxtWord(todo.Category))
+	}
+	for _, tag := range todo.Tags {
+		parts = append(parts, "@"+todoTxtWord(tag))
+	}
+	if todo.DueDate != nil {
+		parts = append(parts, "due:"+todo.DueDate.Format(todoTxtDate))
+	}
+	if todo.Recurring != nil {
+		parts = append(parts, "rec:"+encodeTodoTxtRecurrence(todo.Recurring.Frequency))
+	}
+	// completed tasks lose their leading priority so it is kept as a tag instead
+	if todo.Completed {
+		parts = append(parts, "pri:"+priorityLetter(todo.Priority))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func decodeTodoTxtLine(text string) (*domain.Todo, error) {
+	fields := strings.Fields(text)
+	todo := domain.NewTodo("")
+
+	if len(fields) > 0 && fields[0] == "x" {
+		todo.Completed = true
+		fields = fields[1:]
+		if len(fields) > 0 {
+			if completedAt, err := time.ParseInLocation(todoTxtDate, fields[0], time.Local); err == nil {
+				todo.UpdatedAt = completedAt
+				fields = fields[1:]
+			}
+		}
+	}
+	if len(fields) > 0 && isTodoTxtPriority(fields[0]) {
+		todo.Priority = letterPriority(fields[0][1])
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if createdAt, err := time.ParseInLocation(todoTxtDate, fields[0], time.Local); err == nil {
+			todo.CreatedAt = createdAt
+			fields = fields[1:]
+		}
+	}
+
+	var description []string
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '+' && todo.Category == "":
+			todo.Category = fromTodoTxtWord(field[1:])
+		case len(field) > 1 && field[0] == '@':
+			todo.Tags = append(todo.Tags, fromTodoTxtWord(field[1:]))
+		case strings.HasPrefix(field, "due:"):
+			due, err := time.ParseInLocation(todoTxtDate, strings.TrimPrefix(field, "due:"), time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("%w: due date %q", ErrInvalidFormat, field)
+			}
+			todo.DueDate = &due
+		case strings.HasPrefix(field, "rec:"):
+			frequency, ok := decodeTodoTxtRecurrence(strings.TrimPrefix(field, "rec:"))
+			if !ok {
+				return nil, fmt.Errorf("%w: recurrence %q is not daily, weekly, monthly or yearly", ErrInvalidFormat, field)
+			}
+			todo.Recurring = &domain.RecurringConfig{Frequency: frequency, LastOccurrence: todo.CreatedAt}
+		case strings.HasPrefix(field, "pri:") && len(field) == 5:
+			todo.Priority = letterPriority(field[4])
+		default:
+			description = append(description, field)
+		}
+	}
+
+	todo.Description = strings.Join(description, " ")
+	if todo.Description == "" {
+		return nil, fmt.Errorf("%w: missing description", ErrInvalidFormat)
+	}
+
+	return todo, nil
+}
+
+func isTodoTxtPriority(field string) bool {
+	return len(field) == 3 && field[0] == '(' && field[1] >= 'A' && field[1] <= 'Z' && field[2] == ')'
+}
+
+func priorityLetter(priority domain.Priority) string {
+	switch priority {
+	case domain.PriorityHigh:
+		return "A"
+	case domain.PriorityLow:
+		return "C"
+	default:
+		return "B"
+	}
+}
+
+// letterPriority maps A and B onto high and medium; everything after is low
+func letterPriority(letter byte) domain.Priority {
+	switch letter {
+	case 'A':
+		return domain.PriorityHigh
+	case 'B':
+		return domain.PriorityMedium
+	default:
+		return domain.PriorityLow
+	}
+}
+
+func encodeTodoTxtRecurrence(frequency string) string {
+	if interval, ok := todoTxtRecurrences[strings.ToLower(frequency)]; ok {
+		return interval
+	}
+	return todoTxtWord(frequency)
+}
+
+// decodeTodoTxtRecurrence returns the frequency of a recurrence interval, or of a frequency named outright, and
+// false for any the app does not understand
+func decodeTodoTxtRecurrence(interval string) (string, bool) {
+	// a leading plus marks strict recurrence from the due date which the app does not distinguish
+	interval = strings.TrimPrefix(interval, "+")
+	for frequency, i := range todoTxtRecurrences {
+		if strings.EqualFold(i, interval) || strings.EqualFold(frequency, interval) {
+			return frequency, true
+		}
+	}
+	return "", false
+}
+
+func todoTxtWord(s string) string {
+	return strings.Join(strings.Fields(s), "_")
+}
+
+func fromTodoTxtWord(s string) string {
+	return strings.ReplaceAll(s, "_", " ")
+}
@@ -0,0 +1,208 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestEncodeTodoTxt(t *testing.T) {
+	var created = time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	var completed = time.Date(2026, 10, 3, 0, 0, 0, 0, time.Local)
+	var due = time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		todo *domain.Todo
+		want string
+	}{
+		"Minimal": {
+			todo: &domain.Todo{Description: "Bake a cake", Priority: domain.PriorityMedium, CreatedAt: created},
+			want: "(B) 2026-10-01 Bake a cake\n",
+		},
+		"AllFields": {
+			todo: &domain.Todo{
+				Description: "Feed the cat",
+				Priority:    domain.PriorityHigh,
+				CreatedAt:   created,
+				Category:    "Pets",
+				Tags:        []string{"pet care", "daily"},
+				DueDate:     &due,
+				Recurring:   &domain.RecurringConfig{Frequency: "daily"},
+			},
+			want: "(A) 2026-10-01 Feed the cat +Pets @pet_care @daily due:2026-10-20 rec:1d\n",
+		},
+		"Completed": {
+			todo: &domain.Todo{
+				Description: "Take out the trash",
+				Completed:   true,
+				Priority:    domain.PriorityLow,
+				CreatedAt:   created,
+				UpdatedAt:   completed,
+			},
+			want: "x 2026-10-03 2026-10-01 Take out the trash pri:C\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeTodoTxt(&buf, []*domain.Todo{tt.todo}); err != nil {
+				t.Fatalf("EncodeTodoTxt() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("EncodeTodoTxt() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTodoTxt(t *testing.T) {
+	var due = time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	tests := map[string]struct {
+		content string
+		want    []*domain.Todo
+		wantErr error
+	}{
+		"PlainDescription": {
+			content: "Call mom\n",
+			want:    []*domain.Todo{{Description: "Call mom", Priority: domain.PriorityMedium, Tags: []string{}}},
+		},
+		"PriorityProjectsAndContexts": {
+			content: "(A) Call mom +Family +Phone @phone due:2026-10-20 rec:+1w\n",
+			want: []*domain.Todo{{
+				Description: "Call mom +Phone",
+				Priority:    domain.PriorityHigh,
+				Category:    "Family",
+				Tags:        []string{"phone"},
+				DueDate:     &due,
+				Recurring:   &domain.RecurringConfig{Frequency: "weekly"},
+			}},
+		},
+		"CompletedWithPriorityTag": {
+			content: "x 2026-10-03 Call mom pri:A\n",
+			want:    []*domain.Todo{{Description: "Call mom", Completed: true, Priority: domain.PriorityHigh, Tags: []string{}}},
+		},
+		"LowPriorityLetters": {
+			content: "(D) Someday\n",
+			want:    []*domain.Todo{{Description: "Someday", Priority: domain.PriorityLow, Tags: []string{}}},
+		},
+		"SkipsBlankLines": {
+			content: "\nfirst\n\n   \nsecond\n",
+			want: []*domain.Todo{
+				{Description: "first", Priority: domain.PriorityMedium, Tags: []string{}},
+				{Description: "second", Priority: domain.PriorityMedium, Tags: []string{}},
+			},
+		},
+		"InvalidDueDate": {
+			content: "Call mom due:tomorrow\n",
+			wantErr: ErrInvalidFormat,
+		},
+		"MissingDescription": {
+			content: "(A) +Family @phone\n",
+			wantErr: ErrInvalidFormat,
+		},
+		"FrequencyByName": {
+			content: "Water the plants rec:daily\n",
+			want: []*domain.Todo{{
+				Description: "Water the plants",
+				Priority:    domain.PriorityMedium,
+				Tags:        []string{},
+				Recurring:   &domain.RecurringConfig{Frequency: "daily"},
+			}},
+		},
+		"UnknownRecurrence": {
+			content: "Water the plants rec:2w\n",
+			wantErr: ErrInvalidFormat,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := DecodeTodoTxt(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeTodoTxt() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("DecodeTodoTxt() = %d todos, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				assertSameTodo(t, got[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestDecodeTodoTxt_recurrenceLine(t *testing.T) {
+	_, err := DecodeTodoTxt(strings.NewReader("Call mom rec:1w\nWater the plants rec:fortnightly\n"))
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 || !strings.Contains(err.Error(), "rec:fortnightly") {
+		t.Errorf("DecodeTodoTxt() error = %v, want the recurrence on line 2", err)
+	}
+}
+
+func TestTodoTxt_RoundTrip(t *testing.T) {
+	var list = domain.NewTodos()
+	var due = time.Date(2026, 12, 24, 0, 0, 0, 0, time.Local)
+
+	cake := list.Add("Bake a cake")
+	cake.Priority = domain.PriorityHigh
+	cake.Category = "Cooking"
+	cake.Tags = []string{"baking", "dessert"}
+	cake.DueDate = &due
+
+	cat := list.Add("Feed the cat")
+	cat.Category = "Pets"
+	cat.Tags = []string{"pet care", "daily"}
+	cat.SetRecurring("daily", nil)
+
+	trash := list.Add("Take out the trash")
+	trash.Priority = domain.PriorityLow
+	trash.Update(true, trash.Description)
+
+	var buf bytes.Buffer
+	if err := EncodeTodoTxt(&buf, list.All()); err != nil {
+		t.Fatalf("EncodeTodoTxt() error = %v", err)
+	}
+	got, err := DecodeTodoTxt(&buf)
+	if err != nil {
+		t.Fatalf("DecodeTodoTxt() error = %v", err)
+	}
+
+	if len(got) != len(list.All()) {
+		t.Fatalf("round trip = %d todos, want %d", len(got), len(list.All()))
+	}
+	for i, want := range list.All() {
+		assertSameTodo(t, got[i], want)
+	}
+}
+
+// assertSameTodo compares the fields that the todo.txt format carries
+func assertSameTodo(t *testing.T, got, want *domain.Todo) {
+	t.Helper()
+	if got.Description != want.Description {
+		t.Errorf("Description = %q, want %q", got.Description, want.Description)
+	}
+	if got.Completed != want.Completed {
+		t.Errorf("Completed = %v, want %v", got.Completed, want.Completed)
+	}
+	if got.Priority != want.Priority {
+		t.Errorf("Priority = %v, want %v", got.Priority, want.Priority)
+	}
+	if got.Category != want.Category {
+		t.Errorf("Category = %q, want %q", got.Category, want.Category)
+	}
+	if !reflect.DeepEqual(got.Tags, want.Tags) {
+		t.Errorf("Tags = %v, want %v", got.Tags, want.Tags)
+	}
+	if (got.DueDate == nil) != (want.DueDate == nil) || (got.DueDate != nil && !got.DueDate.Equal(*want.DueDate)) {
+		t.Errorf("DueDate = %v, want %v", got.DueDate, want.DueDate)
+	}
+	if (got.Recurring == nil) != (want.Recurring == nil) || (got.Recurring != nil && got.Recurring.Frequency != want.Recurring.Frequency) {
+		t.Errorf("Recurring = %v, want %v", got.Recurring, want.Recurring)
+	}
+}
@@ -0,0 +1,10 @@
+package users
+
+import "errors"
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrInvalidUsername  = errors.New("usernames are 2 to 32 letters, digits, dots, dashes or underscores")
+	ErrUsernameTaken    = errors.New("username is taken")
+	ErrPermissionDenied = errors.New("permission denied")
+)
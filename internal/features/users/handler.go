@@ -0,0 +1,105 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/templates/pages"
+)
+
+type (
+	Handler interface {
+		// Profile : GET /users/me
+		Profile(w http.ResponseWriter, r *http.Request)
+		// SaveProfile : POST /users/me
+		SaveProfile(w http.ResponseWriter, r *http.Request)
+		// User : GET /users/{username}
+		User(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+	}
+)
+
+func NewHandler(svc Service) Handler {
+	return &handler{service: svc}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/me", h.Profile)
+		r.Post("/me", h.SaveProfile)
+		r.Get("/{username}", h.User)
+	})
+}
+
+func (h handler) Profile(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.Get(r.Context(), identity.UserID(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var username, name string
+	if user != nil {
+		username, name = user.Username, user.Name
+	}
+	if err = pages.ProfilePage(username, name, "").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) SaveProfile(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var username = r.Form.Get("username")
+	var name = r.Form.Get("name")
+
+	_, err := h.service.SaveProfile(r.Context(), identity.UserID(r.Context()), username, name)
+	switch {
+	case errors.Is(err, ErrInvalidUsername), errors.Is(err, ErrUsernameTaken):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = pages.ProfilePage(username, name, err.Error()).Render(r.Context(), w)
+	case errors.Is(err, ErrPermissionDenied):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	default:
+		http.Redirect(w, r, "/users/me", http.StatusFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) User(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.GetByUsername(r.Context(), chi.URLParam(r, "username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	todos, err := h.service.Assigned(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err = pages.UserPage(user, todos).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
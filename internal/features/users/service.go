@@ -0,0 +1,79 @@
+package users
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+type (
+	Service interface {
+		// Get returns the user with the ID, or nil when they have not made a profile
+		Get(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+		// GetByUsername returns the user with the username
+		GetByUsername(ctx context.Context, username string) (*domain.User, error)
+		// SaveProfile sets the username and name of the user, making their profile the first time
+		SaveProfile(ctx context.Context, userID uuid.UUID, username, name string) (*domain.User, error)
+		// Assigned returns the todos assigned to the user
+		Assigned(ctx context.Context, userID uuid.UUID) ([]*domain.Todo, error)
+	}
+
+	service struct {
+		users domain.UserRepository
+		todos domain.TodoRepository
+	}
+)
+
+func NewService(users domain.UserRepository, todos domain.TodoRepository) Service {
+	return &service{
+		users: users,
+		todos: todos,
+	}
+}
+
+func (s service) Get(_ context.Context, userID uuid.UUID) (*domain.User, error) {
+	return s.users.Get(userID), nil
+}
+
+func (s service) GetByUsername(_ context.Context, username string) (*domain.User, error) {
+	user := s.users.GetByUsername(username)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (s service) SaveProfile(_ context.Context, userID uuid.UUID, username, name string) (*domain.User, error) {
+	if userID == uuid.Nil {
+		return nil, ErrPermissionDenied
+	}
+
+	username, ok := domain.NormalizeUsername(username)
+	// "me" names the profile page of whoever is looking at it
+	if !ok || username == "me" {
+		return nil, ErrInvalidUsername
+	}
+
+	if other := s.users.GetByUsername(username); other != nil && other.ID != userID {
+		return nil, ErrUsernameTaken
+	}
+
+	user := s.users.Get(userID)
+	if user == nil {
+		user = &domain.User{ID: userID, CreatedAt: time.Now()}
+	}
+	user.Username = username
+	user.Name = strings.TrimSpace(name)
+	s.users.Save(user)
+
+	return user, nil
+}
+
+func (s service) Assigned(_ context.Context, userID uuid.UUID) ([]*domain.Todo, error) {
+	return s.todos.GetByAssignee(userID), nil
+}
@@ -0,0 +1,223 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+	"github.com/stackus/todos/internal/identity"
+)
+
+type (
+	// ViewRequest creates or replaces a view
+	ViewRequest struct {
+		Name   string       `json:"name"`
+		Query  QueryRequest `json:"query"`
+		Sort   string       `json:"sort,omitempty"`
+		Pinned bool         `json:"pinned,omitempty"`
+	}
+
+	// QueryRequest is the search of a view; dates are written as 2006-01-02
+	QueryRequest struct {
+		Search    string     `json:"search,omitempty"`
+		Tags      []string   `json:"tags,omitempty"`
+		Category  string     `json:"category,omitempty"`
+		Priority  string     `json:"priority,omitempty"`
+		DueFrom   string     `json:"dueFrom,omitempty"`
+		DueTo     string     `json:"dueTo,omitempty"`
+		Assignee  *uuid.UUID `json:"assignee,omitempty"`
+		Completed *bool      `json:"completed,omitempty"`
+		Archived  bool       `json:"archived,omitempty"`
+	}
+
+	// ViewResponse is a view as the API returns it
+	ViewResponse struct {
+		ID        uuid.UUID    `json:"id"`
+		Name      string       `json:"name"`
+		Query     QueryRequest `json:"query"`
+		Sort      string       `json:"sort"`
+		Pinned    bool         `json:"pinned"`
+		URL       string       `json:"url"`
+		CreatedAt time.Time    `json:"createdAt"`
+		UpdatedAt time.Time    `json:"updatedAt"`
+	}
+)
+
+func (h handler) ListJSON(w http.ResponseWriter, r *http.Request) {
+	views, err := h.service.List(r.Context(), identity.UserID(r.Context()))
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	res := make([]ViewResponse, len(views))
+	for i, view := range views {
+		res[i] = viewResponse(view)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h handler) CreateJSON(w http.ResponseWriter, r *http.Request) {
+	input, err := decodeInput(r)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	view, err := h.service.Create(r.Context(), identity.UserID(r.Context()), input)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/views/"+view.ID.String())
+	writeJSON(w, http.StatusCreated, viewResponse(view))
+}
+
+func (h handler) GetJSON(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.service.Get(r.Context(), identity.UserID(r.Context()), viewID)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, viewResponse(view))
+}
+
+func (h handler) UpdateJSON(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input, err := decodeInput(r)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	view, err := h.service.Update(r.Context(), identity.UserID(r.Context()), viewID, input)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, viewResponse(view))
+}
+
+func (h handler) DeleteJSON(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), identity.UserID(r.Context()), viewID); err != nil {
+		viewError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h handler) TodosJSON(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, list, err := h.service.Todos(r.Context(), identity.UserID(r.Context()), viewID)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	res := make([]todos.TodoResponse, len(list))
+	for i, todo := range list {
+		res[i] = todos.NewTodoResponse(todo)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// decodeInput reads a view from the JSON body, checking its search the way todos.ParseQuery does
+func decodeInput(r *http.Request) (Input, error) {
+	var req ViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Input{}, todos.ErrInvalidInput
+	}
+
+	values := url.Values{
+		"search":   {req.Query.Search},
+		"tag":      req.Query.Tags,
+		"category": {req.Query.Category},
+		"priority": {req.Query.Priority},
+		"due_from": {req.Query.DueFrom},
+		"due_to":   {req.Query.DueTo},
+	}
+	if req.Query.Assignee != nil {
+		values.Set("assignee", req.Query.Assignee.String())
+	}
+	if req.Query.Completed != nil {
+		values.Set("completed", strconv.FormatBool(*req.Query.Completed))
+	}
+	if req.Query.Archived {
+		values.Set("archived", "true")
+	}
+	query, err := todos.ParseQuery(values)
+	if err != nil {
+		return Input{}, err
+	}
+
+	order, ok := domain.ParseSortOrder(req.Sort)
+	if !ok {
+		return Input{}, ErrInvalidSort
+	}
+	return Input{Name: req.Name, Query: query, Sort: order, Pinned: req.Pinned}, nil
+}
+
+func viewResponse(view *domain.View) ViewResponse {
+	values := todos.QueryValues(view.Query)
+	query := QueryRequest{
+		Search:    view.Query.Search,
+		Tags:      view.Query.Tags,
+		Category:  view.Query.Category,
+		DueFrom:   values.Get("due_from"),
+		DueTo:     values.Get("due_to"),
+		Assignee:  view.Query.AssignedTo,
+		Completed: view.Query.Completed,
+		Archived:  view.Query.IncludeArchived,
+	}
+	if view.Query.Priority != nil {
+		query.Priority = view.Query.Priority.String()
+	}
+	return ViewResponse{
+		ID:        view.ID,
+		Name:      view.Name,
+		Query:     query,
+		Sort:      string(view.Sort),
+		Pinned:    view.Pinned,
+		URL:       "/views/" + view.ID.String(),
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
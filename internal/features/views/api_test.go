@@ -0,0 +1,78 @@
+package views
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/home"
+	"github.com/stackus/todos/internal/features/todos"
+	"github.com/stackus/todos/internal/identity"
+)
+
+func TestAPI(t *testing.T) {
+	cat := domain.NewTodo("Feed the cat")
+	cat.Tags = []string{"pets"}
+	list := domain.NewTodos()
+	list.Save(cat, domain.NewTodo("Bake a cake"))
+	saved := domain.NewViews()
+
+	router := chi.NewRouter()
+	Mount(router, NewHandler(NewService(saved, list), home.NewService(list, saved)))
+	userID := uuid.New()
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(identity.WithUserID(req.Context(), userID))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/views", `{"name":"Pets","query":{"tags":["pets"],"priority":"urgent"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST with a bad priority = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = do(http.MethodPost, "/api/views", `{"name":"Pets","query":{"tags":["pets"],"dueTo":"2030-01-31"},"sort":"due","pinned":true}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST = %d %s, want %d", rec.Code, rec.Body, http.StatusCreated)
+	}
+	var created ViewResponse
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("decoding the view: %v", err)
+	}
+	if created.Name != "Pets" || created.Sort != "due" || !created.Pinned || created.Query.DueTo != "2030-01-31" ||
+		rec.Header().Get("Location") != "/api/views/"+created.ID.String() {
+		t.Fatalf("POST = %+v at %q", created, rec.Header().Get("Location"))
+	}
+
+	// nothing is due, so the due range finds no todos until it is taken out
+	rec = do(http.MethodPut, "/api/views/"+created.ID.String(), `{"name":"Pets","query":{"tags":["pets"]}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT = %d %s", rec.Code, rec.Body)
+	}
+	rec = do(http.MethodGet, "/api/views/"+created.ID.String()+"/todos", "")
+	var found []todos.TodoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&found); err != nil || len(found) != 1 || found[0].ID != cat.ID {
+		t.Fatalf("GET todos = %v, %v, want the cat", found, err)
+	}
+
+	// the page of the view shows the same todos
+	rec = do(http.MethodGet, "/views/"+created.ID.String(), "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Feed the cat") || strings.Contains(rec.Body.String(), "Bake a cake") {
+		t.Fatalf("GET the page = %d, want the cat alone", rec.Code)
+	}
+
+	if rec = do(http.MethodDelete, "/api/views/"+created.ID.String(), ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec = do(http.MethodGet, "/api/views/"+created.ID.String(), ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET after DELETE = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,10 @@
+package views
+
+import "errors"
+
+var (
+	ErrViewNotFound     = errors.New("view not found")
+	ErrInvalidName      = errors.New("views need a name of up to 100 characters")
+	ErrInvalidSort      = errors.New("unknown sort order")
+	ErrPermissionDenied = errors.New("permission denied")
+)
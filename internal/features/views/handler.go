@@ -0,0 +1,346 @@
+package views
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/home"
+	"github.com/stackus/todos/internal/features/todos"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/templates/pages"
+	"github.com/stackus/todos/internal/templates/partials"
+)
+
+type (
+	Handler interface {
+		// Views : GET /views
+		Views(w http.ResponseWriter, r *http.Request)
+		// Create : POST /views
+		Create(w http.ResponseWriter, r *http.Request)
+		// View : GET /views/{viewId}
+		View(w http.ResponseWriter, r *http.Request)
+		// Update : POST /views/{viewId}/edit
+		Update(w http.ResponseWriter, r *http.Request)
+		// Pin : POST /views/{viewId}/pin
+		Pin(w http.ResponseWriter, r *http.Request)
+		// Delete : DELETE /views/{viewId}
+		// Delete : POST /views/{viewId}/delete
+		Delete(w http.ResponseWriter, r *http.Request)
+
+		// ListJSON : GET /api/views
+		ListJSON(w http.ResponseWriter, r *http.Request)
+		// CreateJSON : POST /api/views
+		CreateJSON(w http.ResponseWriter, r *http.Request)
+		// GetJSON : GET /api/views/{viewId}
+		GetJSON(w http.ResponseWriter, r *http.Request)
+		// UpdateJSON : PUT /api/views/{viewId}
+		UpdateJSON(w http.ResponseWriter, r *http.Request)
+		// DeleteJSON : DELETE /api/views/{viewId}
+		DeleteJSON(w http.ResponseWriter, r *http.Request)
+		// TodosJSON : GET /api/views/{viewId}/todos
+		TodosJSON(w http.ResponseWriter, r *http.Request)
+	}
+
+	handler struct {
+		service Service
+		lists   home.Service
+	}
+)
+
+// NewHandler serves the views, with the smart lists in their sidebar
+func NewHandler(svc Service, lists home.Service) Handler {
+	return &handler{service: svc, lists: lists}
+}
+
+func Mount(r chi.Router, h Handler) {
+	r.Route("/views", func(r chi.Router) {
+		r.Get("/", h.Views)
+		r.Post("/", h.Create)
+		r.Route("/{viewId}", func(r chi.Router) {
+			r.Get("/", h.View)
+			r.Post("/edit", h.Update)
+			r.Post("/pin", h.Pin)
+			r.Delete("/", h.Delete)
+			r.Post("/delete", h.Delete)
+		})
+	})
+	r.Route("/api/views", func(r chi.Router) {
+		r.Get("/", h.ListJSON)
+		r.Post("/", h.CreateJSON)
+		r.Route("/{viewId}", func(r chi.Router) {
+			r.Get("/", h.GetJSON)
+			r.Put("/", h.UpdateJSON)
+			r.Delete("/", h.DeleteJSON)
+			r.Get("/todos", h.TodosJSON)
+		})
+	})
+}
+
+func (h handler) Views(w http.ResponseWriter, r *http.Request) {
+	// the form starts from the search it was opened with
+	form := newForm("/views", identity.UserID(r.Context()), r.URL.Query())
+	h.renderViews(w, r, http.StatusOK, form)
+}
+
+func (h handler) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := identity.UserID(r.Context())
+
+	input, err := formInput(r.Form)
+	if err == nil {
+		var view *domain.View
+		if view, err = h.service.Create(r.Context(), userID, input); err == nil {
+			http.Redirect(w, r, "/views/"+view.ID.String(), http.StatusFound)
+			return
+		}
+	}
+	if !invalid(err) {
+		viewError(w, err)
+		return
+	}
+
+	form := newForm("/views", userID, r.Form)
+	form.Problem = err.Error()
+	h.renderViews(w, r, http.StatusBadRequest, form)
+}
+
+func (h handler) View(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	view, list, err := h.service.Todos(r.Context(), identity.UserID(r.Context()), viewID)
+	if err != nil {
+		viewError(w, err)
+		return
+	}
+
+	if !isHTMX(r) {
+		h.renderView(w, r, http.StatusOK, view, list, viewForm(view))
+		return
+	}
+	if err = partials.RenderTodos(list, "").Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) Update(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := identity.UserID(r.Context())
+
+	input, err := formInput(r.Form)
+	if err == nil {
+		if _, err = h.service.Update(r.Context(), userID, viewID, input); err == nil {
+			http.Redirect(w, r, "/views/"+viewID.String(), http.StatusFound)
+			return
+		}
+	}
+	if !invalid(err) {
+		viewError(w, err)
+		return
+	}
+
+	view, list, terr := h.service.Todos(r.Context(), userID, viewID)
+	if terr != nil {
+		viewError(w, terr)
+		return
+	}
+	form := newForm("/views/"+viewID.String()+"/edit", userID, r.Form)
+	form.Problem = err.Error()
+	h.renderView(w, r, http.StatusBadRequest, view, list, form)
+}
+
+func (h handler) Pin(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err = h.service.Pin(r.Context(), identity.UserID(r.Context()), viewID, r.Form.Get("pinned") == "true"); err != nil {
+		viewError(w, err)
+		return
+	}
+
+	h.renderList(w, r)
+}
+
+func (h handler) Delete(w http.ResponseWriter, r *http.Request) {
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), identity.UserID(r.Context()), viewID); err != nil {
+		viewError(w, err)
+		return
+	}
+
+	h.renderList(w, r)
+}
+
+// renderList responds with the list of views, or sends the browser back to it
+func (h handler) renderList(w http.ResponseWriter, r *http.Request) {
+	if !isHTMX(r) {
+		http.Redirect(w, r, "/views", http.StatusFound)
+		return
+	}
+
+	views, err := h.service.List(r.Context(), identity.UserID(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// the sidebar changes along with the pins
+	w.Header().Set("HX-Refresh", "true")
+	if err = partials.Views(views).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) renderViews(w http.ResponseWriter, r *http.Request, status int, form partials.ViewForm) {
+	userID := identity.UserID(r.Context())
+	views, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sidebar, err := home.Sidebar(r.Context(), h.lists, userID, r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	if err = pages.ViewsPage(views, form, sidebar).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h handler) renderView(w http.ResponseWriter, r *http.Request, status int, view *domain.View, list []*domain.Todo, form partials.ViewForm) {
+	sidebar, err := home.Sidebar(r.Context(), h.lists, identity.UserID(r.Context()), "/views/"+view.ID.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	if err = pages.ViewPage(view, list, form, sidebar).Render(r.Context(), w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// formInput reads a view from the fields of its form
+//
+// The search uses the parameters of todos.ParseQuery, except that the tags are given together separated by commas.
+func formInput(form url.Values) (Input, error) {
+	values := url.Values{}
+	for key, value := range form {
+		values[key] = value
+	}
+	for _, tag := range strings.Split(form.Get("tags"), ",") {
+		values.Add("tag", tag)
+	}
+
+	query, err := todos.ParseQuery(values)
+	if err != nil {
+		return Input{}, err
+	}
+	order, ok := domain.ParseSortOrder(form.Get("sort"))
+	if !ok {
+		return Input{}, ErrInvalidSort
+	}
+	return Input{
+		Name:   form.Get("name"),
+		Query:  query,
+		Sort:   order,
+		Pinned: form.Get("pinned") == "true",
+	}, nil
+}
+
+// newForm fills the form of a view from the parameters of a search and the other fields of the form
+func newForm(action string, userID uuid.UUID, values url.Values) partials.ViewForm {
+	tags := values["tag"]
+	if v := values.Get("tags"); v != "" {
+		tags = append(tags, v)
+	}
+	return partials.ViewForm{
+		Action:    action,
+		Name:      values.Get("name"),
+		Search:    values.Get("search"),
+		Tags:      strings.Join(tags, ", "),
+		Category:  values.Get("category"),
+		Priority:  values.Get("priority"),
+		DueFrom:   values.Get("due_from"),
+		DueTo:     values.Get("due_to"),
+		Assignee:  values.Get("assignee"),
+		Completed: values.Get("completed"),
+		Archived:  values.Get("archived") == "true",
+		Sort:      values.Get("sort"),
+		Pinned:    values.Get("pinned") == "true",
+		Me:        userID.String(),
+	}
+}
+
+// viewForm fills the form editing the view
+func viewForm(view *domain.View) partials.ViewForm {
+	values := todos.QueryValues(view.Query)
+	values.Set("name", view.Name)
+	values.Set("sort", string(view.Sort))
+	if view.Pinned {
+		values.Set("pinned", "true")
+	}
+	return newForm("/views/"+view.ID.String()+"/edit", view.UserID, values)
+}
+
+// invalid returns true for the errors that are fixed by changing the form
+func invalid(err error) bool {
+	return errors.Is(err, ErrInvalidName) || errors.Is(err, ErrInvalidSort) ||
+		errors.Is(err, todos.ErrInvalidDate) || errors.Is(err, todos.ErrInvalidPriority) || errors.Is(err, todos.ErrInvalidInput)
+}
+
+func viewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrViewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrPermissionDenied):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case invalid(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isHTMX(r *http.Request) bool {
+	// Check for "HX-Request" header
+	if r.Header.Get("HX-Request") != "" {
+		return true
+	}
+
+	return false
+}
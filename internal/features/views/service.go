@@ -0,0 +1,145 @@
+package views
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// maxNameLength is the longest name a view may have, in characters
+const maxNameLength = 100
+
+type (
+	Service interface {
+		// List returns the views of the user ordered by name
+		List(ctx context.Context, userID uuid.UUID) ([]*domain.View, error)
+		// Get returns a view of the user; the views of other users are not found
+		Get(ctx context.Context, userID, viewID uuid.UUID) (*domain.View, error)
+		// Create saves a search as a new view of the user
+		Create(ctx context.Context, userID uuid.UUID, input Input) (*domain.View, error)
+		// Update replaces the name, search, sort order and pin of a view of the user
+		Update(ctx context.Context, userID, viewID uuid.UUID, input Input) (*domain.View, error)
+		// Pin pins a view of the user to the sidebar, or takes it off
+		Pin(ctx context.Context, userID, viewID uuid.UUID, pinned bool) (*domain.View, error)
+		// Delete removes a view of the user
+		Delete(ctx context.Context, userID, viewID uuid.UUID) error
+		// Todos returns the todos in a view of the user, in its order
+		Todos(ctx context.Context, userID, viewID uuid.UUID) (*domain.View, []*domain.Todo, error)
+	}
+
+	// Input is what a view is made of
+	Input struct {
+		Name   string
+		Query  domain.Query
+		Sort   domain.SortOrder
+		Pinned bool
+	}
+
+	service struct {
+		views domain.ViewRepository
+		todos domain.TodoRepository
+		now   func() time.Time
+	}
+)
+
+func NewService(views domain.ViewRepository, todos domain.TodoRepository) Service {
+	return &service{
+		views: views,
+		todos: todos,
+		now:   time.Now,
+	}
+}
+
+func (s service) List(_ context.Context, userID uuid.UUID) ([]*domain.View, error) {
+	return s.views.ForUser(userID), nil
+}
+
+func (s service) Get(_ context.Context, userID, viewID uuid.UUID) (*domain.View, error) {
+	return s.owned(userID, viewID)
+}
+
+func (s service) Create(_ context.Context, userID uuid.UUID, input Input) (*domain.View, error) {
+	if userID == uuid.Nil {
+		return nil, ErrPermissionDenied
+	}
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	view := domain.NewView(userID, strings.TrimSpace(input.Name), input.Query, input.Sort, input.Pinned)
+	s.views.Save(view)
+	return view, nil
+}
+
+func (s service) Update(_ context.Context, userID, viewID uuid.UUID, input Input) (*domain.View, error) {
+	view, err := s.owned(userID, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if err = input.validate(); err != nil {
+		return nil, err
+	}
+
+	view.Name = strings.TrimSpace(input.Name)
+	view.Query = input.Query
+	view.Sort = input.Sort
+	view.Pinned = input.Pinned
+	view.UpdatedAt = s.now()
+	s.views.Save(view)
+	return view, nil
+}
+
+func (s service) Pin(_ context.Context, userID, viewID uuid.UUID, pinned bool) (*domain.View, error) {
+	view, err := s.owned(userID, viewID)
+	if err != nil {
+		return nil, err
+	}
+
+	view.Pinned = pinned
+	view.UpdatedAt = s.now()
+	s.views.Save(view)
+	return view, nil
+}
+
+func (s service) Delete(_ context.Context, userID, viewID uuid.UUID) error {
+	if _, err := s.owned(userID, viewID); err != nil {
+		return err
+	}
+
+	s.views.Remove(viewID)
+	return nil
+}
+
+func (s service) Todos(_ context.Context, userID, viewID uuid.UUID) (*domain.View, []*domain.Todo, error) {
+	view, err := s.owned(userID, viewID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return view, view.Todos(s.todos, s.now()), nil
+}
+
+// owned returns the view when it belongs to the user
+func (s service) owned(userID, viewID uuid.UUID) (*domain.View, error) {
+	view := s.views.Get(viewID)
+	if view == nil || view.UserID != userID {
+		return nil, ErrViewNotFound
+	}
+	return view, nil
+}
+
+func (i Input) validate() error {
+	name := strings.TrimSpace(i.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return ErrInvalidName
+	}
+	if _, ok := domain.ParseSortOrder(string(i.Sort)); !ok {
+		return ErrInvalidSort
+	}
+	return nil
+}
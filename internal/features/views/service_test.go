@@ -0,0 +1,88 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func Test_service(t *testing.T) {
+	cat := domain.NewTodo("Feed the cat")
+	cat.Tags = []string{"pets"}
+	dog := domain.NewTodo("Walk the dog")
+	dog.Tags = []string{"pets"}
+	dog.Priority = domain.PriorityHigh
+	list := domain.NewTodos()
+	list.Save(cat, dog, domain.NewTodo("Bake a cake"))
+
+	s := NewService(domain.NewViews(), list)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	tests := map[string]struct {
+		userID uuid.UUID
+		input  Input
+		want   error
+	}{
+		"NoName":    {userID: alice, input: Input{Name: "  "}, want: ErrInvalidName},
+		"LongName":  {userID: alice, input: Input{Name: strings.Repeat("é", 101)}, want: ErrInvalidName},
+		"BadSort":   {userID: alice, input: Input{Name: "Pets", Sort: "shuffle"}, want: ErrInvalidSort},
+		"Anonymous": {userID: uuid.Nil, input: Input{Name: "Pets"}, want: ErrPermissionDenied},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Create(ctx, tt.userID, tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("Create() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	view, err := s.Create(ctx, alice, Input{Name: " Pets ", Query: domain.Query{Tags: []string{"pets"}}, Sort: domain.SortPriority})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if view.Name != "Pets" {
+		t.Errorf("Create() Name = %q, want it trimmed", view.Name)
+	}
+
+	_, todos, err := s.Todos(ctx, alice, view.ID)
+	if err != nil {
+		t.Fatalf("Todos() error = %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != dog.ID || todos[1].ID != cat.ID {
+		t.Errorf("Todos() = %v, want the dog then the cat", todos)
+	}
+
+	// the views of one user are hidden from another
+	if _, err = s.Get(ctx, bob, view.ID); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Get() by another user error = %v, want %v", err, ErrViewNotFound)
+	}
+	if err = s.Delete(ctx, bob, view.ID); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Delete() by another user error = %v, want %v", err, ErrViewNotFound)
+	}
+	if views, _ := s.List(ctx, bob); len(views) != 0 {
+		t.Errorf("List() = %v for another user, want nothing", views)
+	}
+
+	if view, err = s.Pin(ctx, alice, view.ID, true); err != nil || !view.Pinned {
+		t.Fatalf("Pin() = %v, %v, want the view pinned", view, err)
+	}
+	if view, err = s.Update(ctx, alice, view.ID, Input{Name: "Cats", Query: domain.Query{Search: "cat"}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if view.Name != "Cats" || view.Pinned || view.Sort != domain.SortManual {
+		t.Errorf("Update() = %+v, want the view replaced", view)
+	}
+
+	if err = s.Delete(ctx, alice, view.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if views, _ := s.List(ctx, alice); len(views) != 0 {
+		t.Errorf("List() = %v after Delete(), want nothing", views)
+	}
+}
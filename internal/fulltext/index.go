@@ -0,0 +1,336 @@
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/markdown"
+)
+
+// The fields of a todo that are indexed and how much a word in each counts
+const (
+	descriptionWeight = 3
+	tagWeight         = 2
+	categoryWeight    = 2
+	commentWeight     = 1
+)
+
+// How much a word found other than as written counts against one found as written
+const (
+	prefixMatch = 0.8
+	typoMatch   = 0.6
+	typosMatch  = 0.4
+)
+
+// BM25 tuning: bm25K1 is how quickly more of the same word stops counting and bm25B how much longer todos are held back
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+type (
+	// Index is an inverted index of the words in todos
+	//
+	// The words of the description, comments, tags and category are indexed; the index is safe to use from
+	// more than one goroutine.
+	Index struct {
+		mu       sync.RWMutex
+		docs     map[uuid.UUID]*document
+		postings map[string]map[uuid.UUID]float64
+		// deleted are the todos taken out, so that a change to one indexed late does not bring it back
+		deleted map[uuid.UUID]bool
+		// length is the weighted number of words across all the todos
+		length float64
+	}
+
+	// Result is a todo found by a search
+	Result struct {
+		Todo  *domain.Todo
+		Score float64
+		// Snippet is the text around where the todo matched, or empty when the search was empty
+		Snippet []Fragment
+	}
+
+	// Fragment is a piece of a snippet, marked when it is a word that matched the search
+	Fragment struct {
+		Text  string
+		Match bool
+	}
+
+	// document is an indexed todo
+	document struct {
+		todo *domain.Todo
+		// revision is the revision of the todo indexed
+		revision int
+		// signature is all the text indexed, to tell when the todo has changed
+		signature string
+		terms     map[string]float64
+		length    float64
+	}
+)
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[uuid.UUID]*document),
+		postings: make(map[string]map[uuid.UUID]float64),
+		deleted:  make(map[uuid.UUID]bool),
+	}
+}
+
+// Put indexes the todo, or indexes it again if it has changed since it was last indexed
+//
+// Changes made at once may be indexed in any order, so a todo at or below the revision indexed, or one that
+// has been deleted, is left out.
+func (ix *Index) Put(todo *domain.Todo) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	if ix.deleted[todo.ID] {
+		return
+	}
+	if doc, ok := ix.docs[todo.ID]; ok && todo.Revision <= doc.revision {
+		return
+	}
+	ix.put(todo)
+}
+
+// Replace indexes the todo as it is, whatever its revision and whether it was deleted, for todos saved in
+// place of those with the same ID, as a restored backup is
+func (ix *Index) Replace(todo *domain.Todo) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	delete(ix.deleted, todo.ID)
+	ix.put(todo)
+}
+
+// Delete takes the todo out of the index, and keeps it out
+func (ix *Index) Delete(id uuid.UUID) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.delete(id)
+	ix.deleted[id] = true
+}
+
+// Sync brings the index in line with the todos, indexing the new and changed ones and taking out those that are gone
+func (ix *Index) Sync(todos []*domain.Todo) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	kept := make(map[uuid.UUID]bool, len(todos))
+	for _, todo := range todos {
+		kept[todo.ID] = true
+		delete(ix.deleted, todo.ID)
+		ix.put(todo)
+	}
+	for id := range ix.docs {
+		if !kept[id] {
+			ix.delete(id)
+			ix.deleted[id] = true
+		}
+	}
+}
+
+// Len returns how many todos are indexed
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// Search returns the todos holding every word of the query, the most relevant first
+//
+// Words are matched regardless of case, accents and common English endings. Words of four or more letters
+// also match words a typo away, or two typos for words of eight or more, and the last word matches the words
+// it begins as it is still being typed. An empty query finds nothing.
+func (ix *Index) Search(query string) []Result {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// each word of the query keeps only the todos holding it, adding to their scores
+	var scores map[uuid.UUID]float64
+	matched := make(map[string]bool)
+	for i, term := range terms {
+		termScores := make(map[uuid.UUID]float64)
+		for indexed, weight := range ix.match(term, i == len(terms)-1) {
+			matched[indexed] = true
+			idf := ix.idf(indexed)
+			for id, tf := range ix.postings[indexed] {
+				score := weight * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*ix.docs[id].length/ix.averageLength()))
+				if score > termScores[id] {
+					termScores[id] = score
+				}
+			}
+		}
+		if scores != nil {
+			for id := range termScores {
+				if score, ok := scores[id]; ok {
+					termScores[id] += score
+				} else {
+					delete(termScores, id)
+				}
+			}
+		}
+		scores = termScores
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		todo := ix.docs[id].todo
+		results = append(results, Result{Todo: todo, Score: score, Snippet: snippet(todo, matched)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Todo.CreatedAt.Equal(b.Todo.CreatedAt) {
+			return a.Todo.CreatedAt.Before(b.Todo.CreatedAt)
+		}
+		return a.Todo.ID.String() < b.Todo.ID.String()
+	})
+	return results
+}
+
+// match returns the indexed words the query word stands for and how much each counts
+func (ix *Index) match(term string, last bool) map[string]float64 {
+	found := make(map[string]float64)
+	if _, ok := ix.postings[term]; ok {
+		found[term] = 1
+	}
+
+	letters := utf8.RuneCountInString(term)
+	maxTypos := 0
+	switch {
+	case letters >= 8:
+		maxTypos = 2
+	case letters >= 4:
+		maxTypos = 1
+	}
+	for indexed := range ix.postings {
+		if indexed == term {
+			continue
+		}
+		if last && strings.HasPrefix(indexed, term) {
+			found[indexed] = prefixMatch
+			continue
+		}
+		if maxTypos == 0 {
+			continue
+		}
+		switch d := distance(term, indexed, maxTypos); {
+		case d == 1:
+			found[indexed] = typoMatch
+		case d == 2 && maxTypos == 2:
+			found[indexed] = typosMatch
+		}
+	}
+	return found
+}
+
+func (ix *Index) idf(term string) float64 {
+	n, df := float64(len(ix.docs)), float64(len(ix.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+func (ix *Index) averageLength() float64 {
+	if len(ix.docs) == 0 {
+		return 1
+	}
+	return math.Max(ix.length/float64(len(ix.docs)), 1)
+}
+
+func (ix *Index) put(todo *domain.Todo) {
+	signature := signatureOf(todo)
+	if doc, ok := ix.docs[todo.ID]; ok {
+		if doc.signature == signature {
+			doc.todo, doc.revision = todo, todo.Revision
+			return
+		}
+		ix.delete(todo.ID)
+	}
+
+	doc := &document{todo: todo, revision: todo.Revision, signature: signature, terms: make(map[string]float64)}
+	add := func(text string, weight float64) {
+		for _, token := range tokenize(text) {
+			doc.terms[token.term] += weight
+			doc.length += weight
+		}
+	}
+	add(todo.PlainDescription(), descriptionWeight)
+	for _, tag := range todo.Tags {
+		add(tag, tagWeight)
+	}
+	add(todo.Category, categoryWeight)
+	for _, comment := range todo.Comments {
+		add(markdown.PlainText(comment.Content), commentWeight)
+	}
+
+	ix.docs[todo.ID] = doc
+	ix.length += doc.length
+	for term, tf := range doc.terms {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[uuid.UUID]float64)
+		}
+		ix.postings[term][todo.ID] = tf
+	}
+}
+
+func (ix *Index) delete(id uuid.UUID) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.length -= doc.length
+	delete(ix.docs, id)
+}
+
+// signatureOf joins the text of the todo that is indexed
+func signatureOf(todo *domain.Todo) string {
+	var sb strings.Builder
+	sb.WriteString(todo.Description)
+	sb.WriteByte(0)
+	sb.WriteString(todo.Category)
+	for _, tag := range todo.Tags {
+		sb.WriteByte(0)
+		sb.WriteString(tag)
+	}
+	for _, comment := range todo.Comments {
+		sb.WriteByte(0)
+		sb.WriteString(comment.Content)
+	}
+	return sb.String()
+}
+
+// queryTerms returns the words of the query as they are indexed, each once
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	terms := make([]string, 0)
+	for _, token := range tokenize(query) {
+		if !seen[token.term] {
+			seen[token.term] = true
+			terms = append(terms, token.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,153 @@
+package fulltext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestIndex_Search(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	newTodo := func(description string, minutes int) *domain.Todo {
+		todo := domain.NewTodo(description)
+		todo.CreatedAt = now.Add(time.Duration(minutes) * time.Minute)
+		return todo
+	}
+	cake := newTodo("Bake a **chocolate** cake for the party", 0)
+	cake.Tags = []string{"baking", "dessert"}
+	cake.Category = "Cooking"
+	bread := newTodo("Buy bread", 1)
+	bread.Comments = []domain.Comment{{Content: "The bakery on the corner has the best sourdough"}}
+	cafe := newTodo("Meet Zoë at the café", 2)
+	vacation := newTodo("Plan the vacation", 3)
+	vacation.Category = "Personal"
+
+	ix := NewIndex()
+	ix.Sync([]*domain.Todo{cake, bread, cafe, vacation})
+
+	tests := map[string]struct {
+		query string
+		want  []*domain.Todo
+	}{
+		"Stemmed":        {query: "baked desserts", want: []*domain.Todo{cake}},
+		"Folded":         {query: "CAFE zoe", want: []*domain.Todo{cafe}},
+		"Typo":           {query: "vacaton", want: []*domain.Todo{vacation}},
+		"Prefix":         {query: "sourd", want: []*domain.Todo{bread}},
+		"EveryWord":      {query: "chocolate party", want: []*domain.Todo{cake}},
+		"NotEveryWord":   {query: "chocolate bread", want: []*domain.Todo{}},
+		"Category":       {query: "personal", want: []*domain.Todo{vacation}},
+		"RankedByWeight": {query: "bak", want: []*domain.Todo{cake, bread}},
+		"Empty":          {query: " ,. ", want: []*domain.Todo{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := ix.Search(tt.query)
+			if len(results) != len(tt.want) {
+				t.Fatalf("Search(%q) = %d results, want %d", tt.query, len(results), len(tt.want))
+			}
+			for i, todo := range tt.want {
+				if results[i].Todo != todo {
+					t.Errorf("Search(%q)[%d] = %q, want %q", tt.query, i, results[i].Todo.Description, todo.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_incremental(t *testing.T) {
+	ix := NewIndex()
+	todo := domain.NewTodo("Walk the dog")
+	ix.Put(todo)
+	if len(ix.Search("dog")) != 1 {
+		t.Fatalf("Search(dog) found nothing after Put()")
+	}
+
+	todo.Update(false, "Walk the cat")
+	ix.Put(todo)
+	if len(ix.Search("dog")) != 0 || len(ix.Search("cat")) != 1 {
+		t.Errorf("Search() still finds what the todo said before it changed")
+	}
+
+	// Sync picks up changes the index was not told about
+	todo.Tags = []string{"pets"}
+	other := domain.NewTodo("Feed the fish")
+	ix.Sync([]*domain.Todo{todo, other})
+	if len(ix.Search("pets")) != 1 || len(ix.Search("fish")) != 1 {
+		t.Errorf("Sync() missed a change")
+	}
+
+	ix.Delete(todo.ID)
+	ix.Sync([]*domain.Todo{other})
+	if len(ix.Search("cat")) != 0 || ix.Len() != 1 {
+		t.Errorf("Search() finds a deleted todo")
+	}
+	if len(ix.postings) != len(ix.docs[other.ID].terms) {
+		t.Errorf("postings = %v, want only the words of the todo left", ix.postings)
+	}
+}
+
+func TestIndex_Put_outOfOrder(t *testing.T) {
+	ix := NewIndex()
+	todo := domain.NewTodo("Walk the dog")
+	stale := *todo
+	todo.Update(false, "Walk the cat")
+
+	// the change is indexed before the todo it was made to
+	ix.Put(todo)
+	ix.Put(&stale)
+	if len(ix.Search("dog")) != 0 || len(ix.Search("cat")) != 1 {
+		t.Errorf("Put() indexed an older version over a newer one")
+	}
+
+	// a change indexed after the todo was deleted does not bring it back
+	ix.Delete(todo.ID)
+	changed := *todo
+	changed.Update(true, "Walk the cat")
+	ix.Put(&changed)
+	if ix.Len() != 0 {
+		t.Errorf("Put() brought back a deleted todo")
+	}
+
+	// a todo saved in place of a deleted one, as a restore does, is indexed again
+	ix.Replace(&stale)
+	if len(ix.Search("dog")) != 1 {
+		t.Errorf("Replace() did not index the todo")
+	}
+}
+
+func TestIndex_Search_snippet(t *testing.T) {
+	long := domain.NewTodo("Pack the suitcase with sunscreen, hats, sandals, a good book for the beach and the charger for the camera before leaving for the airport at dawn")
+	comment := domain.NewTodo("Call mum")
+	comment.Comments = []domain.Comment{{Content: "Ask about her **garden**"}}
+	ix := NewIndex()
+	ix.Sync([]*domain.Todo{long, comment})
+
+	tests := map[string]struct {
+		query string
+		want  string
+	}{
+		"Description": {query: "book", want: "…hats, sandals, a good [book] for the beach and the charger for the camera before leaving for the airport at dawn"},
+		"Comment":     {query: "gardening", want: "Ask about her [garden]"},
+		"Start":       {query: "pack", want: "[Pack] the suitcase with sunscreen, hats, sandals, a good book for the beach and the charger for the camera before leaving…"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results := ix.Search(tt.query)
+			if len(results) != 1 {
+				t.Fatalf("Search(%q) = %d results", tt.query, len(results))
+			}
+			var got string
+			for _, fragment := range results[0].Snippet {
+				if fragment.Match {
+					got += "[" + fragment.Text + "]"
+				} else {
+					got += fragment.Text
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Search(%q) snippet = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
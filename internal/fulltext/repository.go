@@ -0,0 +1,104 @@
+package fulltext
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// Indexed is a todo repository that keeps its todos in an index as they change
+type Indexed interface {
+	domain.TodoRepository
+	Index() *Index
+}
+
+// todoRepository indexes each todo changed through the todo repository it wraps
+//
+// Every feature changes the todos through the same repository, so imports, CalDAV, offline syncs and restores
+// are indexed as they are made, without the index being brought in line with all the todos on each search.
+type todoRepository struct {
+	domain.TodoRepository
+	index *Index
+}
+
+// todoHistoryRepository is an indexed todo repository that keeps a history, so that the services still find it
+type todoHistoryRepository struct {
+	todoRepository
+	history domain.TodoHistory
+}
+
+var (
+	_ Indexed            = (*todoRepository)(nil)
+	_ domain.TodoHistory = (*todoHistoryRepository)(nil)
+)
+
+// TodoRepository indexes the todos of the repository, and each todo as it is changed through the repository returned
+func (ix *Index) TodoRepository(next domain.TodoRepository) Indexed {
+	ix.Sync(next.All())
+
+	r := todoRepository{TodoRepository: next, index: ix}
+	if history, ok := next.(domain.TodoHistory); ok {
+		return &todoHistoryRepository{todoRepository: r, history: history}
+	}
+	return &r
+}
+
+func (r *todoRepository) Index() *Index {
+	return r.index
+}
+
+func (r *todoRepository) Add(description string) *domain.Todo {
+	todo := r.TodoRepository.Add(description)
+	r.index.Put(todo)
+	return todo
+}
+
+func (r *todoRepository) Save(todos ...*domain.Todo) {
+	r.TodoRepository.Save(todos...)
+	for _, todo := range todos {
+		if saved := r.TodoRepository.Get(todo.ID); saved != nil {
+			r.index.Replace(saved)
+		}
+	}
+}
+
+func (r *todoRepository) AddSubtask(parentID uuid.UUID, subtask *domain.Todo) (*domain.Todo, error) {
+	added, err := r.TodoRepository.AddSubtask(parentID, subtask)
+	if err != nil {
+		return nil, err
+	}
+	r.index.Put(added)
+	// the parent is changed too, as the subtask goes under it
+	if parent := r.TodoRepository.Get(parentID); parent != nil {
+		r.index.Put(parent)
+	}
+	return added, nil
+}
+
+func (r *todoRepository) Change(id uuid.UUID, changes ...domain.Change) (*domain.Todo, error) {
+	todo, err := r.TodoRepository.Change(id, changes...)
+	if err != nil {
+		return nil, err
+	}
+	r.index.Put(todo)
+	return todo, nil
+}
+
+func (r *todoRepository) Remove(id uuid.UUID) {
+	r.TodoRepository.Remove(id)
+	r.index.Delete(id)
+}
+
+func (r *todoRepository) Update(id uuid.UUID, completed bool, description string) *domain.Todo {
+	todo := r.TodoRepository.Update(id, completed, description)
+	if todo != nil {
+		r.index.Put(todo)
+	}
+	return todo
+}
+
+func (r *todoHistoryRepository) TodosAt(at time.Time) ([]*domain.Todo, error) {
+	return r.history.TodosAt(at)
+}
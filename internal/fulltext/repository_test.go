@@ -0,0 +1,45 @@
+package fulltext
+
+import (
+	"testing"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+func TestIndex_TodoRepository(t *testing.T) {
+	list := domain.NewTodos()
+	list.Add("Bake a cake")
+
+	ix := NewIndex()
+	repo := ix.TodoRepository(list)
+	if got := ix.Search("cake"); len(got) != 1 {
+		t.Fatalf("Search(cake) = %d results, want the todo there before", len(got))
+	}
+
+	// every way of changing the todos reaches the index
+	party := domain.NewTodo("Throw a party")
+	repo.Save(party)
+	balloons, _ := repo.AddSubtask(party.ID, domain.NewTodo("Buy balloons"))
+	if got := ix.Search("party"); len(got) != 1 || len(got[0].Todo.Subtasks) != 1 {
+		t.Errorf("Search(party) = %v, want the parent indexed with its subtask", got)
+	}
+	_, _ = repo.Change(balloons.ID, domain.SetTags{Tags: []string{"shopping"}})
+	repo.Update(party.ID, false, "Throw a surprise party")
+	repo.Remove(list.All()[0].ID)
+
+	tests := map[string]int{
+		"cake":     0,
+		"party":    1,
+		"surprise": 1,
+		"balloons": 1,
+		"shopping": 1,
+	}
+	for query, want := range tests {
+		if got := ix.Search(query); len(got) != want {
+			t.Errorf("Search(%s) = %d results, want %d", query, len(got), want)
+		}
+	}
+	if _, ok := repo.(domain.TodoHistory); ok {
+		t.Error("the repository keeps a history when the one it wraps does not")
+	}
+}
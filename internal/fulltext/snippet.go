@@ -0,0 +1,85 @@
+package fulltext
+
+import (
+	"strings"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/markdown"
+)
+
+// snippetLength is about how many bytes of text a snippet shows
+const snippetLength = 120
+
+// snippet picks the text of the todo where the matched words show up first, marking them
+//
+// The description comes first, then the comments, then the tags and category.
+func snippet(todo *domain.Todo, matched map[string]bool) []Fragment {
+	texts := []string{todo.PlainDescription()}
+	for _, comment := range todo.Comments {
+		texts = append(texts, markdown.PlainText(comment.Content))
+	}
+	texts = append(texts, strings.Join(append(append([]string{}, todo.Tags...), todo.Category), " · "))
+
+	for _, text := range texts {
+		tokens := tokenize(text)
+		for i, token := range tokens {
+			if matched[token.term] {
+				return fragments(text, tokens[i:], matched)
+			}
+		}
+	}
+	return nil
+}
+
+// fragments cuts a snippet out of the text beginning a little before the first of the tokens
+func fragments(text string, tokens []token, matched map[string]bool) []Fragment {
+	start := 0
+	if tokens[0].start > snippetLength/4 {
+		// start at a word some way back so that the match has a little of what comes before it
+		start = tokens[0].start - snippetLength/4
+		for start < tokens[0].start && text[start] != ' ' {
+			start++
+		}
+		if start < tokens[0].start {
+			start++
+		}
+	}
+	end := len(text)
+	if end-start > snippetLength {
+		end = start + snippetLength
+		for end > tokens[0].end && text[end] != ' ' {
+			end--
+		}
+	}
+
+	var list []Fragment
+	add := func(s string, match bool) {
+		if s == "" {
+			return
+		}
+		if n := len(list); n > 0 && list[n-1].Match == match {
+			list[n-1].Text += s
+			return
+		}
+		list = append(list, Fragment{Text: s, Match: match})
+	}
+	if start > 0 {
+		add("…", false)
+	}
+	at := start
+	for _, token := range tokens {
+		if token.end > end {
+			break
+		}
+		if matched[token.term] {
+			add(text[at:token.start], false)
+			add(text[token.start:token.end], true)
+			at = token.end
+		}
+	}
+	add(strings.TrimRight(text[at:end], " "), false)
+	if end < len(text) {
+		add("…", false)
+	}
+	return list
+}
@@ -0,0 +1,158 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// foldings spell the accented and joined letters of the Latin alphabets without their marks
+var foldings = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g", 'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j", 'ķ': "k", 'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r", 'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w", 'ý': "y", 'ÿ': "y", 'ŷ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Fold lowercases the text and takes the accents off its letters, so that "Café" and "cafe" read the same
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		if folded, ok := foldings[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// token is a word of some text and where it sits in it
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits the text into words, folding and stemming each into the term it is indexed by
+func tokenize(text string) []token {
+	tokens := make([]token, 0)
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start == -1:
+			start = i
+		case !isWord && start != -1:
+			tokens = append(tokens, token{term: Stem(Fold(text[start:i])), start: start, end: i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, token{term: Stem(Fold(text[start:])), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Stem cuts the common English endings off a folded word, so that "baking", "baked" and "bakes" are all "bak"
+//
+// It is far lighter than a full stemmer; all it has to do is cut the same words the same way.
+func Stem(word string) string {
+	if utf8.RuneCountInString(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ing") && len(word) > 5:
+		word = undouble(word[:len(word)-3])
+	case strings.HasSuffix(word, "ed") && len(word) > 4:
+		word = undouble(word[:len(word)-2])
+	case strings.HasSuffix(word, "ly") && len(word) > 4:
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "es") && endsWithAny(word[:len(word)-2], "s", "x", "z", "ch", "sh"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !endsWithAny(word, "ss", "us", "is"):
+		word = word[:len(word)-1]
+	}
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "ee") && len(word) > 3 {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// undouble drops the last of two same consonants left by a cut ending, as in "running"
+func undouble(word string) string {
+	n := len(word)
+	if n < 3 || word[n-1] != word[n-2] || strings.ContainsRune("aeiouylsz", rune(word[n-1])) {
+		return word
+	}
+	return word[:n-1]
+}
+
+func endsWithAny(word string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// distance is the number of letters inserted, removed, changed or swapped with a neighbour to turn a into b,
+// giving up with max+1 once it is more than max
+func distance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > max || -diff > max {
+		return max + 1
+	}
+
+	// rows two back, one back and the current one of the edit table
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minOf(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = minOf(curr[j], prev2[j-2]+1)
+			}
+			best = minOf(best, curr[j])
+		}
+		if best > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+func minOf(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+	return first
+}
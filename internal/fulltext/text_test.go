@@ -0,0 +1,60 @@
+package fulltext
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Café":          "cafe",
+		"Crème BRÛLÉE":  "creme brulee",
+		"Straße":        "strasse",
+		"Œuvre Łódź":    "oeuvre lodz",
+		"plain, as-is!": "plain, as-is!",
+	}
+	for in, want := range tests {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := map[string][]string{
+		"bak":   {"bake", "baked", "bakes", "baking"},
+		"run":   {"running", "runs"},
+		"plan":  {"planned", "planning", "plans"},
+		"dish":  {"dishes", "dish"},
+		"class": {"classes", "class"},
+		"berry": {"berries"},
+		"quick": {"quickly"},
+		"fall":  {"falling", "falls"},
+		"meet":  {"meeting", "meets"},
+		"cat":   {"cat", "cats"},
+	}
+	for want, words := range tests {
+		for _, word := range words {
+			if got := Stem(word); got != want {
+				t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+			}
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"cake", "cake", 0},
+		{"cake", "cakes", 1},
+		{"cake", "ckae", 1},
+		{"cake", "bake", 1},
+		{"vacation", "vacaton", 1},
+		{"vacation", "vcaaton", 2},
+		{"cake", "pie", 3},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b, 2); got != tt.want && !(tt.want > 2 && got == 3) {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package health tells load balancers and orchestrators whether the server is alive and ready for requests,
+// and which build of it is running
+//
+// /healthz answers as long as the process can serve requests at all. /readyz also runs the checks added with
+// Check, such as whether the todo repository can be reached, and fails once the server begins shutting down
+// so that it is taken out of rotation before it stops listening. /version reports the commit, build time and
+// Go version of the build.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// checkTimeout is how long /readyz waits for its checks
+const checkTimeout = 2 * time.Second
+
+// Checker returns why part of the server cannot serve requests, or nil when it can
+type Checker func(ctx context.Context) error
+
+// Health keeps the readiness checks of the server and whether it is shutting down
+type Health struct {
+	checks   []check
+	stopping atomic.Bool
+	version  Version
+}
+
+type check struct {
+	name string
+	run  Checker
+}
+
+// Report is the answer of /readyz: the status of the server and the result of each of its checks
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+const (
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+	StatusStopping = "shutting down"
+
+	checkPassed = "ok"
+)
+
+func New(version Version) *Health {
+	return &Health{version: version}
+}
+
+// Check adds a check run by /readyz; checks are added as the server starts, before it serves requests
+func (h *Health) Check(name string, run Checker) {
+	h.checks = append(h.checks, check{name: name, run: run})
+}
+
+// Shutdown fails /readyz from now on, whatever its checks say
+func (h *Health) Shutdown() {
+	h.stopping.Store(true)
+}
+
+// Ready runs the checks, reporting the server ready only when it is not shutting down and every check passes
+func (h *Health) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]string, len(h.checks))}
+	for _, c := range h.checks {
+		if err := c.run(ctx); err != nil {
+			report.Status = StatusNotReady
+			report.Checks[c.name] = err.Error()
+			continue
+		}
+		report.Checks[c.name] = checkPassed
+	}
+	if h.stopping.Load() {
+		report.Status = StatusStopping
+	}
+	return report
+}
+
+// Mount registers the endpoints; HEAD is answered as well as GET for probes that only look at the status
+func Mount(r chi.Router, h *Health) {
+	r.Get("/healthz", h.live)
+	r.Head("/healthz", h.live)
+	r.Get("/readyz", h.ready)
+	r.Head("/readyz", h.ready)
+	r.Get("/version", h.showVersion)
+}
+
+func (h *Health) live(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+func (h *Health) ready(w http.ResponseWriter, r *http.Request) {
+	report := h.Ready(r.Context())
+	status := http.StatusOK
+	if report.Status != StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func (h *Health) showVersion(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.version)
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestHealth(t *testing.T) {
+	h := New(Version{Commit: "abc123", BuildTime: "2026-10-19T12:00:00Z", GoVersion: runtime.Version()})
+	var repoErr error
+	h.Check("repository", func(context.Context) error { return repoErr })
+	h.Check("scheduler", func(context.Context) error { return nil })
+	router := chi.NewRouter()
+	Mount(router, h)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+	ready := func(wantCode int, wantStatus string) Report {
+		t.Helper()
+		rec := do(http.MethodGet, "/readyz")
+		var report Report
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("GET /readyz returned %q: %v", rec.Body.String(), err)
+		}
+		if rec.Code != wantCode || report.Status != wantStatus {
+			t.Errorf("GET /readyz = %d %q, want %d %q", rec.Code, report.Status, wantCode, wantStatus)
+		}
+		return report
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		if rec := do(method, "/healthz"); rec.Code != http.StatusOK {
+			t.Errorf("%s /healthz = %d, want %d", method, rec.Code, http.StatusOK)
+		}
+	}
+
+	if report := ready(http.StatusOK, StatusReady); report.Checks["repository"] != "ok" || report.Checks["scheduler"] != "ok" {
+		t.Errorf("checks = %v, want each ok", report.Checks)
+	}
+
+	repoErr = errors.New("the event store is closed")
+	if report := ready(http.StatusServiceUnavailable, StatusNotReady); report.Checks["repository"] != repoErr.Error() {
+		t.Errorf("repository check = %q, want %q", report.Checks["repository"], repoErr)
+	}
+	if rec := do(http.MethodHead, "/readyz"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("HEAD /readyz = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	// once shutting down the server is never ready, though it is still alive
+	repoErr = nil
+	h.Shutdown()
+	ready(http.StatusServiceUnavailable, StatusStopping)
+	if rec := do(http.MethodGet, "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("GET /healthz while shutting down = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec := do(http.MethodGet, "/version")
+	var v Version
+	if err := json.NewDecoder(rec.Body).Decode(&v); err != nil || v.Commit != "abc123" || v.BuildTime != "2026-10-19T12:00:00Z" || v.GoVersion != runtime.Version() {
+		t.Errorf("GET /version = %q, %v", rec.Body.String(), err)
+	}
+}
+
+func TestFromBuildInfo(t *testing.T) {
+	info := &debug.BuildInfo{Settings: []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "e91737b"},
+		{Key: "vcs.time", Value: "2026-10-18T09:30:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}}
+
+	got := fromBuildInfo(Version{}, info)
+	if got.Commit != "e91737b" || got.BuildTime != "2026-10-18T09:30:00Z" || !got.Modified {
+		t.Errorf("fromBuildInfo() = %+v, want the version control settings", got)
+	}
+
+	// what the linker flags set is kept
+	got = fromBuildInfo(Version{Commit: "abc123", BuildTime: "2026-10-19T12:00:00Z"}, info)
+	if got.Commit != "abc123" || got.BuildTime != "2026-10-19T12:00:00Z" {
+		t.Errorf("fromBuildInfo() = %+v, want the linker flags kept", got)
+	}
+}
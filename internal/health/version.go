@@ -0,0 +1,61 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Commit and BuildTime name the build when it is made with
+//
+//	go build -ldflags "-X github.com/stackus/todos/internal/health.Commit=... -X github.com/stackus/todos/internal/health.BuildTime=..."
+//
+// as `task build` does; otherwise they are taken from the version control details Go stamps into the binary.
+var (
+	Commit    string
+	BuildTime string
+)
+
+// Version is the build of the server that is running
+type Version struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	// Modified is whether the build was made from a tree with uncommitted changes, when that is known
+	Modified bool `json:"modified,omitempty"`
+}
+
+// ReadVersion returns the version of the running build, with "unknown" for what it cannot tell
+func ReadVersion() Version {
+	v := Version{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		v = fromBuildInfo(v, info)
+	}
+	if v.Commit == "" {
+		v.Commit = "unknown"
+	}
+	if v.BuildTime == "" {
+		v.BuildTime = "unknown"
+	}
+	return v
+}
+
+// fromBuildInfo fills in what the linker flags left out from the version control settings of the build
+//
+// Go records when the commit was made rather than when the build was, which is the closest it comes.
+func fromBuildInfo(v Version, info *debug.BuildInfo) Version {
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if v.Commit == "" {
+				v.Commit = setting.Value
+			}
+		case "vcs.time":
+			if v.BuildTime == "" {
+				v.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			v.Modified = setting.Value == "true"
+		}
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+// Package identity tells the people using the app apart
+//
+// The app has no accounts; every browser is given a user ID the first time it visits, kept in a long-lived
+// cookie as a token signed with the key of the server. User IDs are no secret, as they are shown in exports
+// and in the API, so a cookie holding a bare ID is never taken for the user it names. Features that record who
+// did something, or that only let the person who did it change it again, read the ID from the request context.
+package identity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CookieName is the cookie holding the signed token of the user
+const CookieName = "todos_user"
+
+const cookieMaxAge = 365 * 24 * time.Hour
+
+// keySize is the size of the keys made up by NewKey
+const keySize = 32
+
+type contextKey struct{}
+
+// Sessions hands out the tokens naming users and reads them back, trusting only those it signed
+type Sessions struct {
+	key []byte
+}
+
+// New returns sessions signing their tokens with the key; tokens signed with any other key are not taken
+func New(key []byte) *Sessions {
+	return &Sessions{key: key}
+}
+
+// NewKey makes up a random key, for servers that are not given one; the tokens signed with it last only until
+// the server restarts
+func NewKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Middleware puts the user ID of the request in its context, giving new visitors, and those whose token is
+// not one the sessions signed, an ID of their own
+func (s *Sessions) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := s.fromCookie(r)
+		if !ok {
+			userID = uuid.New()
+			http.SetCookie(w, &http.Cookie{
+				Name:     CookieName,
+				Value:    s.Token(userID),
+				Path:     "/",
+				MaxAge:   int(cookieMaxAge.Seconds()),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+	})
+}
+
+// Token returns the token naming the user: the ID followed by its signature
+func (s *Sessions) Token(userID uuid.UUID) string {
+	token := append(userID[:], s.sign(userID)...)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// UserID returns the user named by the token, and false when the token was not signed by the sessions
+func (s *Sessions) UserID(token string) (uuid.UUID, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != len(uuid.Nil)+sha256.Size {
+		return uuid.Nil, false
+	}
+	userID, err := uuid.FromBytes(data[:len(uuid.Nil)])
+	if err != nil || userID == uuid.Nil || !hmac.Equal(data[len(uuid.Nil):], s.sign(userID)) {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (s *Sessions) sign(userID uuid.UUID) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(CookieName))
+	mac.Write(userID[:])
+	return mac.Sum(nil)
+}
+
+func (s *Sessions) fromCookie(r *http.Request) (uuid.UUID, bool) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return s.UserID(cookie.Value)
+}
+
+// WithUserID returns a copy of the context carrying the user ID
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserID returns the user ID carried by the context, or the zero ID when there is none
+func UserID(ctx context.Context) uuid.UUID {
+	userID, _ := ctx.Value(contextKey{}).(uuid.UUID)
+	return userID
+}
@@ -0,0 +1,59 @@
+package identity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMiddleware(t *testing.T) {
+	sessions := New([]byte("secret"))
+	var seen uuid.UUID
+	handler := sessions.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = UserID(r.Context())
+	}))
+	visit := func(cookie string) []*http.Cookie {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: CookieName, Value: cookie})
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Result().Cookies()
+	}
+
+	// a new visitor is given an ID, in a token that does not give it away alone
+	cookies := visit("")
+	if len(cookies) != 1 || cookies[0].Name != CookieName {
+		t.Fatalf("cookies = %v, want the %s cookie", cookies, CookieName)
+	}
+	if seen == uuid.Nil || cookies[0].Value == seen.String() {
+		t.Fatalf("UserID() = %v with the cookie %q, want a signed token", seen, cookies[0].Value)
+	}
+	if userID, ok := sessions.UserID(cookies[0].Value); !ok || userID != seen {
+		t.Fatalf("the token names %v, want %v", userID, seen)
+	}
+
+	// a returning visitor keeps theirs
+	userID := uuid.New()
+	if cookies = visit(sessions.Token(userID)); seen != userID || len(cookies) != 0 {
+		t.Errorf("UserID() = %v with cookies %v, want %v and no new cookie", seen, cookies, userID)
+	}
+
+	// a cookie naming a user without being signed by the server, or signed with another key, is never taken
+	// for that user
+	other := New([]byte("another secret"))
+	for name, cookie := range map[string]string{
+		"bare ID":      userID.String(),
+		"nonsense":     "nonsense",
+		"other key":    other.Token(userID),
+		"cut short":    sessions.Token(userID)[:40],
+		"another user": sessions.Token(userID)[:22] + other.Token(uuid.New())[22:],
+	} {
+		if cookies = visit(cookie); seen == uuid.Nil || seen == userID || len(cookies) != 1 {
+			t.Errorf("%s: UserID() = %v with cookies %v, want a new ID", name, seen, cookies)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	breakNode
+	codeNode
+	strongNode
+	emphasisNode
+	linkNode
+)
+
+// node is a piece of inline Markdown
+type node struct {
+	kind     nodeKind
+	text     string
+	href     string
+	children []node
+}
+
+var autolinkPattern = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+
+// urlPrefixes start the bare URLs that are linked automatically
+var urlPrefixes = []string{"https://", "http://", "www."}
+
+func (o Options) renderInline(text string) string {
+	var b strings.Builder
+	writeHTML(&b, o.parseInline(text, true))
+	return b.String()
+}
+
+func plainInline(text string) string {
+	var b strings.Builder
+	writePlain(&b, Options{}.parseInline(text, true))
+	return b.String()
+}
+
+func writeHTML(b *strings.Builder, nodes []node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			b.WriteString(escape(n.text))
+		case breakNode:
+			b.WriteString("<br>\n")
+		case codeNode:
+			b.WriteString("<code>" + escape(n.text) + "</code>")
+		case strongNode:
+			b.WriteString("<strong>")
+			writeHTML(b, n.children)
+			b.WriteString("</strong>")
+		case emphasisNode:
+			b.WriteString("<em>")
+			writeHTML(b, n.children)
+			b.WriteString("</em>")
+		case linkNode:
+			b.WriteString(`<a href="` + escape(n.href) + `">`)
+			writeHTML(b, n.children)
+			b.WriteString("</a>")
+		}
+	}
+}
+
+func writePlain(b *strings.Builder, nodes []node) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode, codeNode:
+			b.WriteString(n.text)
+		case breakNode:
+			b.WriteString("\n")
+		default:
+			writePlain(b, n.children)
+		}
+	}
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
+
+// parseInline splits text into nodes; links is false within link text where links cannot nest
+func (o Options) parseInline(text string, links bool) []node {
+	var nodes []node
+	var pending strings.Builder
+	flush := func() {
+		if pending.Len() != 0 {
+			nodes = append(nodes, node{kind: textNode, text: pending.String()})
+			pending.Reset()
+		}
+	}
+	add := func(n ...node) {
+		flush()
+		nodes = append(nodes, n...)
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && isPunct(text[i+1]):
+			pending.WriteByte(text[i+1])
+			i += 2
+			continue
+		case c == '\n':
+			add(node{kind: breakNode})
+			i++
+			continue
+		case c == '`':
+			if n, end, ok := parseCode(text, i); ok {
+				add(n)
+				i = end
+				continue
+			}
+			// an unmatched run of backticks is text
+			run := runLength(text, i, '`')
+			pending.WriteString(text[i : i+run])
+			i += run
+			continue
+		case c == '[' && links:
+			if children, href, end, ok := o.parseLink(text, i); ok {
+				switch SafeURL(href) {
+				case true:
+					add(node{kind: linkNode, href: href, children: children})
+				default:
+					add(children...)
+				}
+				i = end
+				continue
+			}
+		case c == '<' && links:
+			if m := autolinkPattern.FindStringSubmatch(text[i:]); m != nil && SafeURL(m[1]) {
+				add(node{kind: linkNode, href: m[1], children: []node{{kind: textNode, text: m[1]}}})
+				i += len(m[0])
+				continue
+			}
+		case c == '*' || c == '_':
+			if n, end, ok := o.parseEmphasis(text, i, links); ok {
+				add(n)
+				i = end
+				continue
+			}
+			// a run that opens nothing is text, including its delimiters
+			run := runLength(text, i, c)
+			pending.WriteString(text[i : i+run])
+			i += run
+			continue
+		case c == '@' && links && o.Mention != nil && (i == 0 || !isWord(text[i-1]) && !strings.ContainsRune("@/.", rune(text[i-1]))):
+			if username, end := parseUsername(text, i+1); username != "" {
+				if href, ok := o.Mention(username); ok && SafeURL(href) {
+					add(node{kind: linkNode, href: href, children: []node{{kind: textNode, text: text[i:end]}}})
+					i = end
+					continue
+				}
+			}
+		case links && (i == 0 || !isWord(text[i-1])):
+			if href, end, ok := parseBareURL(text, i); ok {
+				add(node{kind: linkNode, href: href, children: []node{{kind: textNode, text: text[i:end]}}})
+				i = end
+				continue
+			}
+		}
+		pending.WriteByte(c)
+		i++
+	}
+	flush()
+
+	return nodes
+}
+
+// parseCode reads a code span opened by the run of backticks at text[start]
+func parseCode(text string, start int) (node, int, bool) {
+	run := runLength(text, start, '`')
+	for j := start + run; j < len(text); {
+		if text[j] != '`' {
+			j++
+			continue
+		}
+		closing := runLength(text, j, '`')
+		if closing == run {
+			code := strings.ReplaceAll(text[start+run:j], "\n", " ")
+			// a single space either side lets the code itself start or end with a backtick
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			return node{kind: codeNode, text: code}, j + closing, true
+		}
+		j += closing
+	}
+	return node{}, 0, false
+}
+
+// parseLink reads a [text](url) link starting at text[start]
+func (o Options) parseLink(text string, start int) ([]node, string, int, bool) {
+	depth := 0
+	closeText := -1
+	for j := start; j < len(text) && closeText == -1; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				closeText = j
+			}
+		}
+	}
+	if closeText == -1 || closeText+1 >= len(text) || text[closeText+1] != '(' {
+		return nil, "", 0, false
+	}
+
+	depth = 0
+	closeURL := -1
+	for j := closeText + 1; j < len(text) && closeURL == -1; j++ {
+		switch text[j] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				closeURL = j
+			}
+		case '\n':
+			return nil, "", 0, false
+		}
+	}
+	if closeURL == -1 {
+		return nil, "", 0, false
+	}
+
+	// a title may follow the destination; it is not rendered
+	destination := strings.Fields(text[closeText+2 : closeURL])
+	if len(destination) == 0 {
+		return nil, "", 0, false
+	}
+	href := strings.TrimSuffix(strings.TrimPrefix(destination[0], "<"), ">")
+
+	return o.parseInline(text[start+1:closeText], false), href, closeURL + 1, true
+}
+
+// parseEmphasis reads strong or emphasised text opened by the delimiter at text[start]
+func (o Options) parseEmphasis(text string, start int, links bool) (node, int, bool) {
+	c := text[start]
+	run := runLength(text, start, c)
+	// underscores inside words are part of the word
+	if c == '_' && start > 0 && isWord(text[start-1]) {
+		return node{}, 0, false
+	}
+
+	for _, size := range []int{2, 1} {
+		if run < size {
+			continue
+		}
+		open := start + size
+		if open >= len(text) || isSpace(text[open]) {
+			continue
+		}
+		delimiter := strings.Repeat(string(c), size)
+		for j := open + 1; j+size <= len(text); j++ {
+			if text[j:j+size] != delimiter || isSpace(text[j-1]) {
+				continue
+			}
+			// a single delimiter must not be half of a double one
+			if size == 1 && j+1 < len(text) && text[j+1] == c {
+				j++
+				continue
+			}
+			if c == '_' && j+size < len(text) && isWord(text[j+size]) {
+				continue
+			}
+			kind := emphasisNode
+			if size == 2 {
+				kind = strongNode
+			}
+			return node{kind: kind, children: o.parseInline(text[open:j], links)}, j + size, true
+		}
+	}
+	return node{}, 0, false
+}
+
+// parseBareURL reads a URL written without any Markdown around it
+func parseBareURL(text string, start int) (string, int, bool) {
+	var prefix string
+	for _, p := range urlPrefixes {
+		if len(text)-start > len(p) && strings.EqualFold(text[start:start+len(p)], p) {
+			prefix = p
+			break
+		}
+	}
+	if prefix == "" {
+		return "", 0, false
+	}
+
+	end := start
+	for end < len(text) && !isSpace(text[end]) && text[end] != '<' && text[end] != '>' {
+		end++
+	}
+	// trailing punctuation belongs to the sentence, as does a closing bracket without an opening one
+	for end > start+len(prefix) {
+		last := text[end-1]
+		if strings.IndexByte(`.,:;!?'"*_`, last) >= 0 ||
+			last == ')' && strings.Count(text[start:end], "(") < strings.Count(text[start:end], ")") {
+			end--
+			continue
+		}
+		break
+	}
+	if end == start+len(prefix) {
+		return "", 0, false
+	}
+
+	href := text[start:end]
+	if strings.EqualFold(prefix, "www.") {
+		href = "http://" + href
+	}
+	if !SafeURL(href) {
+		return "", 0, false
+	}
+	return href, end, true
+}
+
+// parseUsername reads the username of a mention starting at text[start]
+func parseUsername(text string, start int) (string, int) {
+	end := start
+	for end < len(text) && (isWord(text[end]) && text[end] < 0x80 || strings.IndexByte("_.-", text[end]) >= 0) {
+		end++
+	}
+	// a mention at the end of a sentence leaves the full stop out
+	for end > start && strings.IndexByte(".-", text[end-1]) >= 0 {
+		end--
+	}
+	return text[start:end], end
+}
+
+func runLength(text string, start int, c byte) int {
+	n := 0
+	for start+n < len(text) && text[start+n] == c {
+		n++
+	}
+	return n
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWord(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
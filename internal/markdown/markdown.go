@@ -0,0 +1,286 @@
+// Package markdown renders the small subset of Markdown used in todo descriptions and comments
+//
+// Supported are paragraphs, line breaks, bullet and numbered lists (nested by indentation),
+// block quotes, fenced code blocks, headings, inline code, emphasis, links and bare URLs which
+// are linked automatically. Raw HTML in the source is never passed through; it is shown as
+// text. Everything rendered goes through Sanitize as well so that only a small allowlist of
+// tags and attributes can reach the page.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	headingPattern  = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	fencePattern    = regexp.MustCompile("^\\s{0,3}(```+|~~~+)")
+	quotePattern    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	checkboxPattern = regexp.MustCompile(`^\[([ xX])\]\s+`)
+)
+
+// Options change how Markdown is rendered
+type Options struct {
+	// Mention returns the URL that an @username mention links to, or false when it is not a user
+	Mention func(username string) (href string, ok bool)
+}
+
+// Render returns the Markdown source as sanitized HTML
+func Render(src string) string {
+	return Options{}.Render(src)
+}
+
+// RenderInline returns the Markdown source as sanitized HTML without block elements
+//
+// It suits places where the text sits within a line, such as a todo in a list. Line breaks are
+// kept but lists, quotes, code blocks and headings are shown as the text they contain.
+func RenderInline(src string) string {
+	return Options{}.RenderInline(src)
+}
+
+// Render returns the Markdown source as sanitized HTML
+func (o Options) Render(src string) string {
+	var b strings.Builder
+	o.renderBlocks(&b, splitLines(src))
+	return Sanitize(b.String())
+}
+
+// RenderInline returns the Markdown source as sanitized HTML without block elements
+func (o Options) RenderInline(src string) string {
+	var lines []string
+	for _, line := range splitLines(plainBlocks(src)) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return Sanitize(o.renderInline(strings.Join(lines, "\n")))
+}
+
+// PlainText returns the text of the Markdown source without any formatting
+//
+// Link targets are dropped in favour of their text. It is used for searching so that the
+// Markdown syntax is neither matched nor gets in the way of matching.
+func PlainText(src string) string {
+	var lines []string
+	for _, line := range splitLines(plainBlocks(src)) {
+		lines = append(lines, plainInline(line))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// plainBlocks removes the block syntax, such as list markers and fences, leaving the inline Markdown
+func plainBlocks(src string) string {
+	var lines []string
+	var fence string
+	for _, line := range splitLines(src) {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+				continue
+			}
+			// code keeps its content as it is and is quoted so that it stays code
+			lines = append(lines, codeSpan(line))
+			continue
+		}
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			fence = m[1]
+			continue
+		}
+		for {
+			if m := quotePattern.FindStringSubmatch(line); m != nil {
+				line = m[1]
+				continue
+			}
+			if m := listItemPattern.FindStringSubmatch(line); m != nil {
+				line = checkboxPattern.ReplaceAllString(m[3], "")
+				continue
+			}
+			break
+		}
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			line = m[1]
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func splitLines(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	return strings.Split(src, "\n")
+}
+
+// codeSpan wraps text in enough backticks that none inside it end the span early
+func codeSpan(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + " " + text + " " + fence
+}
+
+func (o Options) renderBlocks(b *strings.Builder, lines []string) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) != 0 {
+			b.WriteString("<p>" + o.renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			i++
+		case fencePattern.MatchString(line):
+			flush()
+			i = o.renderCode(b, lines, i)
+		case quotePattern.MatchString(line):
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := quotePattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quoted = append(quoted, m[1])
+			}
+			b.WriteString("<blockquote>\n")
+			o.renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+		case headingPattern.MatchString(line):
+			flush()
+			b.WriteString("<p><strong>" + o.renderInline(headingPattern.FindStringSubmatch(line)[1]) + "</strong></p>\n")
+			i++
+		case listItemPattern.MatchString(line):
+			flush()
+			i = o.renderList(b, lines, i)
+		default:
+			paragraph = append(paragraph, strings.TrimSpace(line))
+			i++
+		}
+	}
+	flush()
+}
+
+// renderCode writes the fenced code block starting at lines[start] and returns the line after it
+func (o Options) renderCode(b *strings.Builder, lines []string, start int) int {
+	fence := fencePattern.FindStringSubmatch(lines[start])[1]
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	b.WriteString("<pre><code>" + escape(strings.Join(code, "\n")) + "</code></pre>\n")
+	return i
+}
+
+// renderList writes the list starting at lines[start], with the lists nested inside it, and
+// returns the line after it
+func (o Options) renderList(b *strings.Builder, lines []string, start int) int {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	indent := indentWidth(first[1])
+	ordered := isOrdered(first[2])
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag + ">\n")
+
+	i := start
+	for i < len(lines) {
+		m := listItemPattern.FindStringSubmatch(lines[i])
+		if m == nil || indentWidth(m[1]) < indent || isOrdered(m[2]) != ordered {
+			break
+		}
+		if indentWidth(m[1]) > indent {
+			// a deeper item without a parent item at this level
+			i = o.renderList(b, lines, i)
+			continue
+		}
+
+		b.WriteString("<li>" + o.renderListItem(m[3]))
+		i++
+		// text continuing the item and lists nested under it
+		for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+			if n := listItemPattern.FindStringSubmatch(lines[i]); n != nil {
+				if indentWidth(n[1]) <= indent {
+					break
+				}
+				b.WriteString("\n")
+				i = o.renderList(b, lines, i)
+				continue
+			}
+			b.WriteString("<br>" + o.renderInline(strings.TrimSpace(lines[i])))
+			i++
+		}
+		b.WriteString("</li>\n")
+
+		// a blank line ends the list unless another item follows it
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" && listItemPattern.MatchString(lines[i+1]) {
+			i++
+		}
+	}
+
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func (o Options) renderListItem(text string) string {
+	if m := checkboxPattern.FindStringSubmatch(text); m != nil {
+		mark := "☐ "
+		if m[1] != " " {
+			mark = "☑ "
+		}
+		return mark + o.renderInline(text[len(m[0]):])
+	}
+	return o.renderInline(text)
+}
+
+func isOrdered(marker string) bool {
+	return marker[0] >= '0' && marker[0] <= '9'
+}
+
+func indentWidth(indent string) int {
+	var width int
+	for _, r := range indent {
+		switch r {
+		case '\t':
+			width += 4 - width%4
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// Mentions returns the distinct usernames mentioned as @username, in the order they first appear
+//
+// Mentions within code and link text do not count.
+func Mentions(src string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	o := Options{Mention: func(username string) (string, bool) {
+		if key := strings.ToLower(username); !seen[key] {
+			seen[key] = true
+			usernames = append(usernames, username)
+		}
+		return "", false
+	}}
+	for _, line := range splitLines(plainBlocks(src)) {
+		o.parseInline(line, true)
+	}
+	return usernames
+}
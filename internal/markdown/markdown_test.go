@@ -0,0 +1,122 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"PlainText": {
+			src:  "Feed the cat",
+			want: "<p>Feed the cat</p>\n",
+		},
+		"Emphasis": {
+			src:  "**Really** _do_ *it* but not snake_case_name or 2 * 3 * 4",
+			want: "<p><strong>Really</strong> <em>do</em> <em>it</em> but not snake_case_name or 2 * 3 * 4</p>\n",
+		},
+		"Code": {
+			src:  "Run `go test ./...` and `` a ` b ``",
+			want: "<p>Run <code>go test ./...</code> and <code>a ` b</code></p>\n",
+		},
+		"Links": {
+			src: "See [the docs](https://example.com/docs \"Docs\"), <https://example.com/a?b=1&c=2> and www.example.com/x.",
+			want: `<p>See <a href="https://example.com/docs" rel="nofollow noopener noreferrer">the docs</a>, ` +
+				`<a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer">https://example.com/a?b=1&amp;c=2</a> and ` +
+				`<a href="http://www.example.com/x" rel="nofollow noopener noreferrer">www.example.com/x</a>.</p>` + "\n",
+		},
+		"BareURLInParentheses": {
+			src:  "(see https://en.wikipedia.org/wiki/Go_(programming_language))",
+			want: `<p>(see <a href="https://en.wikipedia.org/wiki/Go_(programming_language)" rel="nofollow noopener noreferrer">https://en.wikipedia.org/wiki/Go_(programming_language)</a>)</p>` + "\n",
+		},
+		"UnsafeLink": {
+			src:  "[click](javascript:alert(1)) [again](JaVaScRiPt:alert(1))",
+			want: "<p>click again</p>\n",
+		},
+		"RawHTML": {
+			src:  `<script>alert(1)</script><img src=x onerror="alert(1)"> & <b>bold</b>`,
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;img src=x onerror=&#34;alert(1)&#34;&gt; &amp; &lt;b&gt;bold&lt;/b&gt;</p>\n",
+		},
+		"Paragraphs": {
+			src:  "first line\nsecond line\n\nnext paragraph",
+			want: "<p>first line<br>\nsecond line</p>\n<p>next paragraph</p>\n",
+		},
+		"Lists": {
+			src: "Steps:\n- one\n- [x] two\n  1. nested\n  2. again\n- three\n\n1) first",
+			want: "<p>Steps:</p>\n<ul>\n<li>one</li>\n<li>☑ two\n<ol>\n<li>nested</li>\n<li>again</li>\n</ol>\n</li>\n" +
+				"<li>three</li>\n</ul>\n<ol>\n<li>first</li>\n</ol>\n",
+		},
+		"Blocks": {
+			src:  "# Title\n> quoted *text*\n```\n<b>code</b>\n```",
+			want: "<p><strong>Title</strong></p>\n<blockquote>\n<p>quoted <em>text</em></p>\n</blockquote>\n<pre><code>&lt;b&gt;code&lt;/b&gt;</code></pre>\n",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Render(tt.src); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	src := "- Buy **milk**\n- see <https://example.com>\n```\nx < y\n```"
+	want := "Buy <strong>milk</strong><br>\nsee " +
+		`<a href="https://example.com" rel="nofollow noopener noreferrer">https://example.com</a>` +
+		"<br>\n<code>x &lt; y</code>"
+	if got := RenderInline(src); got != want {
+		t.Errorf("RenderInline() = %q, want %q", got, want)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"PlainText": {
+			src:  "Feed the cat",
+			want: "Feed the cat",
+		},
+		"Formatting": {
+			src:  "# Trip\n- [ ] Book **cheap** [flights](https://example.com) with `code`\n> quoted _text_",
+			want: "Trip\nBook cheap flights with code\nquoted text",
+		},
+		"Escapes": {
+			src:  `not \*emphasis\* or snake_case`,
+			want: "not *emphasis* or snake_case",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := PlainText(tt.src); got != tt.want {
+				t.Errorf("PlainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_Render(t *testing.T) {
+	o := Options{Mention: func(username string) (string, bool) {
+		return "/users/" + username, username == "alice" || username == "bob.smith"
+	}}
+	src := "@alice and @bob.smith. but not @carol, me@alice.com or `@alice`"
+	want := `<p><a href="/users/alice" rel="nofollow noopener noreferrer">@alice</a> and ` +
+		`<a href="/users/bob.smith" rel="nofollow noopener noreferrer">@bob.smith</a>. ` +
+		"but not @carol, me@alice.com or <code>@alice</code></p>\n"
+	if got := o.Render(src); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	src := "Hey @alice, ask @Bob_2 and @alice again.\n```\n@carol\n```\n[@dave](https://example.com) `@erin` mail@frank.com"
+	want := []string{"alice", "Bob_2"}
+	if got := Mentions(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("Mentions() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// allowedTags are the only elements Sanitize keeps, with the attributes each may carry
+var allowedTags = map[string][]string{
+	"a":          {"href", "title"},
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"em":         nil,
+	"li":         nil,
+	"ol":         nil,
+	"p":          nil,
+	"pre":        nil,
+	"strong":     nil,
+	"ul":         nil,
+}
+
+// voidTags have no content and are never closed
+var voidTags = map[string]bool{
+	"br": true,
+}
+
+// droppedTags are removed together with everything inside them
+var droppedTags = map[string]bool{
+	"iframe":   true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"style":    true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+	"xmp":      true,
+}
+
+// safeSchemes are the URL schemes links may use; URLs without a scheme are relative and allowed
+var safeSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// linkRel is added to every link so that the pages linked to gain nothing from the app
+const linkRel = "nofollow noopener noreferrer"
+
+var (
+	tagPattern       = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^\s/>"'=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*)\s*/?>`)
+	attributePattern = regexp.MustCompile(`([^\s/>"'=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+	entityPattern    = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]{1,31}|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+)
+
+// Sanitize returns the HTML with everything but an allowlist of tags and attributes removed
+//
+// Text that is not part of an allowed tag is escaped, links only keep URLs with a safe scheme,
+// and the tags are balanced so that the result cannot affect the page around it.
+func Sanitize(s string) string {
+	var b strings.Builder
+	var open []string
+
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '<':
+			if strings.HasPrefix(s[i:], "<!--") {
+				end := strings.Index(s[i+4:], "-->")
+				if end == -1 {
+					return closeTags(&b, open)
+				}
+				i += 4 + end + 3
+				continue
+			}
+			m := tagPattern.FindStringSubmatch(s[i:])
+			if m == nil {
+				b.WriteString("&lt;")
+				i++
+				continue
+			}
+			i += len(m[0])
+			closing, name, attributes := m[1] == "/", strings.ToLower(m[2]), m[3]
+
+			switch {
+			case droppedTags[name] && !closing:
+				i = skipContent(s, i, name)
+			case !isAllowed(name):
+			case closing:
+				open = closeTag(&b, open, name)
+			default:
+				b.WriteString(openTag(name, attributes))
+				if !voidTags[name] {
+					open = append(open, name)
+				}
+			}
+		case '>':
+			b.WriteString("&gt;")
+			i++
+		case '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+			b.WriteString("&amp;")
+			i++
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return closeTags(&b, open)
+}
+
+// SafeURL returns true when the URL is relative or uses one of the safe schemes
+func SafeURL(u string) bool {
+	u = strings.TrimSpace(u)
+	for _, r := range u {
+		// control characters are ignored by browsers when reading the scheme
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	colon := strings.IndexByte(u, ':')
+	if colon == -1 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	return safeSchemes[strings.ToLower(u[:colon])]
+}
+
+func isAllowed(name string) bool {
+	_, ok := allowedTags[name]
+	return ok
+}
+
+func openTag(name, attributes string) string {
+	var b strings.Builder
+	b.WriteString("<" + name)
+	for _, m := range attributePattern.FindAllStringSubmatch(attributes, -1) {
+		attribute := strings.ToLower(m[1])
+		if !allowedAttribute(name, attribute) {
+			continue
+		}
+		// entities are decoded first so that they cannot hide the scheme of a URL
+		value := html.UnescapeString(m[2] + m[3] + m[4])
+		if attribute == "href" && !SafeURL(value) {
+			continue
+		}
+		b.WriteString(" " + attribute + `="` + html.EscapeString(value) + `"`)
+	}
+	if name == "a" {
+		b.WriteString(` rel="` + linkRel + `"`)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func allowedAttribute(tag, attribute string) bool {
+	for _, allowed := range allowedTags[tag] {
+		if allowed == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// closeTag closes the most recently opened tag with the name, along with any still open inside it
+func closeTag(b *strings.Builder, open []string, name string) []string {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] != name {
+			continue
+		}
+		for j := len(open) - 1; j >= i; j-- {
+			b.WriteString("</" + open[j] + ">")
+		}
+		return open[:i]
+	}
+	// there is nothing to close
+	return open
+}
+
+func closeTags(b *strings.Builder, open []string) string {
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// skipContent returns the position after the closing tag of a dropped element, or the end of s
+func skipContent(s string, i int, name string) int {
+	closing := "</" + name
+	for ; i+len(closing) <= len(s); i++ {
+		if !strings.EqualFold(s[i:i+len(closing)], closing) {
+			continue
+		}
+		if gt := strings.IndexByte(s[i:], '>'); gt != -1 {
+			return i + gt + 1
+		}
+		break
+	}
+	return len(s)
+}
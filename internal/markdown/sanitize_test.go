@@ -0,0 +1,72 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := map[string]struct {
+		html string
+		want string
+	}{
+		"AllowedTags": {
+			html: "<p>Some <strong>bold</strong> and <em>emphasis</em><br/></p>",
+			want: "<p>Some <strong>bold</strong> and <em>emphasis</em><br></p>",
+		},
+		"DisallowedTags": {
+			html: `<div class="x"><img src=x onerror=alert(1)>text</div>`,
+			want: "text",
+		},
+		"DroppedContent": {
+			html: "a<script>alert('<p>')</script>b<STYLE>p{}</style >c<script>unclosed",
+			want: "abc",
+		},
+		"Attributes": {
+			html: `<p onclick="alert(1)" style="x"><a href='https://example.com' title="t" onmouseover=x>link</a></p>`,
+			want: `<p><a href="https://example.com" title="t" rel="nofollow noopener noreferrer">link</a></p>`,
+		},
+		"UnsafeURLs": {
+			html: `<a href="javascript:alert(1)">a</a><a href="&#106;avascript:alert(1)">b</a>` +
+				`<a href="java&#x09;script:alert(1)">c</a><a href=" data:text/html,x">d</a><a href="/todos?x=1">e</a>`,
+			want: `<a rel="nofollow noopener noreferrer">a</a><a rel="nofollow noopener noreferrer">b</a>` +
+				`<a rel="nofollow noopener noreferrer">c</a><a rel="nofollow noopener noreferrer">d</a>` +
+				`<a href="/todos?x=1" rel="nofollow noopener noreferrer">e</a>`,
+		},
+		"Balancing": {
+			html: "<ul><li><em>open</li></ul></p></strong><p>left open",
+			want: "<ul><li><em>open</em></li></ul><p>left open</p>",
+		},
+		"Text": {
+			html: "1 < 2 > 0 & a &amp; b &#60; <!-- comment --> <3 <!-- unterminated",
+			want: "1 &lt; 2 &gt; 0 &amp; a &amp; b &#60;  &lt;3 ",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Sanitize(tt.html); got != tt.want {
+				t.Errorf("Sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com":           true,
+		"HTTP://example.com":            true,
+		"mailto:someone@example.com":    true,
+		"/todos/1":                      true,
+		"todos?next=a:b":                true,
+		"#top":                          true,
+		"javascript:alert(1)":           false,
+		" JavaScript:alert(1)":          false,
+		"vbscript:msgbox":               false,
+		"data:text/html;base64,PHNjcmk": false,
+		"java\tscript:alert(1)":         false,
+	}
+	for u, want := range tests {
+		if got := SafeURL(u); got != want {
+			t.Errorf("SafeURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatched is the route of requests that matched no route, so that stray paths do not each get a series
+const unmatched = "unmatched"
+
+// Middleware counts and times the requests by the chi route pattern they matched, such as /todos/{todoId}
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		// the pattern is only known once the request has been routed
+		route := unmatched
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && len(rctx.RoutePatterns) > 0 {
+			// chi trims the trailing slash off the patterns, leaving nothing of the root
+			if route = rctx.RoutePattern(); route == "" {
+				route = "/"
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
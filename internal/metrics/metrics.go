@@ -0,0 +1,78 @@
+// Package metrics exposes what the app is doing to Prometheus
+//
+// The metrics are served at /metrics in the Prometheus text format: HTTP requests by chi route pattern, the
+// todos by state, the results of sending notifications and how long the todo repository takes, along with
+// the usual Go runtime and process metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "todos"
+
+// Metrics holds the metrics of the app in a registry of its own, so that tests each get a fresh set
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	notifications   *prometheus.CounterVec
+	operations      *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, chi route pattern and status code.",
+		}, []string{"method", "route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "How long HTTP requests took to handle, by method and chi route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_sent_total",
+			Help:      "Notifications sent on to users beyond their inbox, by result.",
+		}, []string{"result"}),
+		operations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "How long repository operations took, by repository and operation.",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"repository", "operation"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.notifications,
+		m.operations,
+	)
+	// the results are there from the start, so that rates of failures work before the first one
+	for _, result := range []string{resultDelivered, resultFailed} {
+		m.notifications.WithLabelValues(result)
+	}
+	return m
+}
+
+// Handler serves the metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Mount serves the metrics at /metrics
+func Mount(r chi.Router, m *Metrics) {
+	r.Handle("/metrics", m.Handler())
+}
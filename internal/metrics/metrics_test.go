@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+// failingNotifications fails to send every other notification
+type failingNotifications struct {
+	todos.NotificationService
+	sent int
+}
+
+func (s *failingNotifications) SendNotification(context.Context, uuid.UUID, string) error {
+	s.sent++
+	if s.sent%2 == 0 {
+		return errors.New("mailbox full")
+	}
+	return nil
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func wantLines(t *testing.T, metrics string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(metrics, "\n"+line+"\n") {
+			t.Errorf("the metrics are missing %q", line)
+		}
+	}
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := New()
+	router := chi.NewRouter()
+	router.Use(m.Middleware)
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	router.Get("/todos/{todoId}", func(w http.ResponseWriter, r *http.Request) {})
+	router.Route("/api", func(r chi.Router) {
+		r.Post("/sync", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad", http.StatusBadRequest)
+		})
+	})
+	Mount(router, m)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/", nil),
+		httptest.NewRequest(http.MethodGet, "/todos/"+uuid.NewString(), nil),
+		httptest.NewRequest(http.MethodGet, "/todos/"+uuid.NewString(), nil),
+		httptest.NewRequest(http.MethodPost, "/api/sync", nil),
+		httptest.NewRequest(http.MethodGet, "/nowhere/"+uuid.NewString(), nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	wantLines(t, rec.Body.String(),
+		`todos_http_requests_total{code="200",method="GET",route="/"} 1`,
+		`todos_http_requests_total{code="200",method="GET",route="/todos/{todoId}"} 2`,
+		`todos_http_requests_total{code="400",method="POST",route="/api/sync"} 1`,
+		`todos_http_requests_total{code="404",method="GET",route="unmatched"} 1`,
+		`todos_http_request_duration_seconds_count{method="GET",route="/todos/{todoId}"} 2`,
+	)
+}
+
+func TestMetrics_CountTodos(t *testing.T) {
+	m := New()
+	completed := domain.NewTodo("Completed")
+	completed.Completed = true
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	overdue, later := domain.NewTodo("Overdue"), domain.NewTodo("Due later")
+	overdue.DueDate, later.DueDate = &past, &future
+	archived := domain.NewTodo("Archived")
+	archived.Completed, archived.Archived = true, true
+	list := domain.NewTodos()
+	list.Save(domain.NewTodo("Open"), completed, overdue, later, archived)
+	m.CountTodos(list)
+
+	wantLines(t, scrape(t, m),
+		`todos_todos{state="open"} 2`,
+		`todos_todos{state="overdue"} 1`,
+		`todos_todos{state="completed"} 1`,
+		`todos_todos{state="archived"} 1`,
+	)
+}
+
+func TestMetrics_NotificationService(t *testing.T) {
+	m := New()
+	wantLines(t, scrape(t, m),
+		`todos_notifications_sent_total{result="delivered"} 0`,
+		`todos_notifications_sent_total{result="failed"} 0`,
+	)
+
+	notifications := m.NotificationService(&failingNotifications{})
+	for i := 0; i < 3; i++ {
+		_ = notifications.SendNotification(context.Background(), uuid.New(), "hello")
+	}
+	wantLines(t, scrape(t, m),
+		`todos_notifications_sent_total{result="delivered"} 2`,
+		`todos_notifications_sent_total{result="failed"} 1`,
+	)
+}
+
+// historyRepository is a todo repository keeping a history
+type historyRepository struct {
+	*domain.Todos
+}
+
+func (historyRepository) TodosAt(time.Time) ([]*domain.Todo, error) {
+	return nil, nil
+}
+
+func TestMetrics_TodoRepository(t *testing.T) {
+	m := New()
+	repo := m.TodoRepository(domain.NewTodos())
+	todo := repo.Add("Water the plants")
+	repo.Get(todo.ID)
+	repo.Get(todo.ID)
+	if _, ok := repo.(domain.TodoHistory); ok {
+		t.Errorf("a repository without a history looks like it has one")
+	}
+
+	history, ok := m.TodoRepository(historyRepository{domain.NewTodos()}).(domain.TodoHistory)
+	if !ok {
+		t.Fatalf("the history of the repository is hidden")
+	}
+	_, _ = history.TodosAt(time.Now())
+
+	wantLines(t, scrape(t, m),
+		`todos_repository_operation_duration_seconds_count{operation="Add",repository="todos"} 1`,
+		`todos_repository_operation_duration_seconds_count{operation="Get",repository="todos"} 2`,
+		`todos_repository_operation_duration_seconds_count{operation="TodosAt",repository="todos"} 1`,
+	)
+}
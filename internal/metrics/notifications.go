@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/features/todos"
+)
+
+// The results of sending a notification
+const (
+	resultDelivered = "delivered"
+	resultFailed    = "failed"
+)
+
+type notificationService struct {
+	todos.NotificationService
+	metrics *Metrics
+}
+
+// NotificationService counts the results of the notifications sent through the service
+func (m *Metrics) NotificationService(next todos.NotificationService) todos.NotificationService {
+	return &notificationService{NotificationService: next, metrics: m}
+}
+
+func (s *notificationService) SendNotification(ctx context.Context, userID uuid.UUID, message string) error {
+	err := s.NotificationService.SendNotification(ctx, userID, message)
+	result := resultDelivered
+	if err != nil {
+		result = resultFailed
+	}
+	s.metrics.notifications.WithLabelValues(result).Inc()
+	return err
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// todoRepository times each call made to the todo repository it wraps
+type todoRepository struct {
+	next    domain.TodoRepository
+	metrics *Metrics
+}
+
+// todoHistoryRepository is a timed todo repository that keeps a history, so that the services still find it
+type todoHistoryRepository struct {
+	todoRepository
+	history domain.TodoHistory
+}
+
+var (
+	_ domain.TodoRepository = (*todoRepository)(nil)
+	_ domain.TodoHistory    = (*todoHistoryRepository)(nil)
+)
+
+// TodoRepository times the operations of the todo repository
+func (m *Metrics) TodoRepository(next domain.TodoRepository) domain.TodoRepository {
+	r := todoRepository{next: next, metrics: m}
+	if history, ok := next.(domain.TodoHistory); ok {
+		return &todoHistoryRepository{todoRepository: r, history: history}
+	}
+	return &r
+}
+
+// observe records how long the operation took since it started; it is deferred as defer r.observe("Get")()
+func (r *todoRepository) observe(operation string) func() {
+	start := time.Now()
+	return func() {
+		r.metrics.operations.WithLabelValues("todos", operation).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (r *todoRepository) Add(description string) *domain.Todo {
+	defer r.observe("Add")()
+	return r.next.Add(description)
+}
+
+func (r *todoRepository) Save(todos ...*domain.Todo) {
+	defer r.observe("Save")()
+	r.next.Save(todos...)
+}
+
+func (r *todoRepository) AddSubtask(parentID uuid.UUID, subtask *domain.Todo) (*domain.Todo, error) {
+	defer r.observe("AddSubtask")()
+	return r.next.AddSubtask(parentID, subtask)
+}
+
+func (r *todoRepository) Change(id uuid.UUID, changes ...domain.Change) (*domain.Todo, error) {
+	defer r.observe("Change")()
+	return r.next.Change(id, changes...)
+}
+
+func (r *todoRepository) Remove(id uuid.UUID) {
+	defer r.observe("Remove")()
+	r.next.Remove(id)
+}
+
+func (r *todoRepository) Update(id uuid.UUID, completed bool, description string) *domain.Todo {
+	defer r.observe("Update")()
+	return r.next.Update(id, completed, description)
+}
+
+func (r *todoRepository) Search(search string) []*domain.Todo {
+	defer r.observe("Search")()
+	return r.next.Search(search)
+}
+
+func (r *todoRepository) All() []*domain.Todo {
+	defer r.observe("All")()
+	return r.next.All()
+}
+
+func (r *todoRepository) Get(id uuid.UUID) *domain.Todo {
+	defer r.observe("Get")()
+	return r.next.Get(id)
+}
+
+func (r *todoRepository) Reorder(ids []uuid.UUID) []*domain.Todo {
+	defer r.observe("Reorder")()
+	return r.next.Reorder(ids)
+}
+
+func (r *todoRepository) GetByCategory(category string) []*domain.Todo {
+	defer r.observe("GetByCategory")()
+	return r.next.GetByCategory(category)
+}
+
+func (r *todoRepository) GetByTag(tag string) []*domain.Todo {
+	defer r.observe("GetByTag")()
+	return r.next.GetByTag(tag)
+}
+
+func (r *todoRepository) GetByPriority(priority domain.Priority) []*domain.Todo {
+	defer r.observe("GetByPriority")()
+	return r.next.GetByPriority(priority)
+}
+
+func (r *todoRepository) GetByDueDate(start, end time.Time) []*domain.Todo {
+	defer r.observe("GetByDueDate")()
+	return r.next.GetByDueDate(start, end)
+}
+
+func (r *todoRepository) GetByAssignee(userID uuid.UUID) []*domain.Todo {
+	defer r.observe("GetByAssignee")()
+	return r.next.GetByAssignee(userID)
+}
+
+func (r *todoRepository) GetRecurring() []*domain.Todo {
+	defer r.observe("GetRecurring")()
+	return r.next.GetRecurring()
+}
+
+func (r *todoRepository) GetArchived() []*domain.Todo {
+	defer r.observe("GetArchived")()
+	return r.next.GetArchived()
+}
+
+func (r *todoRepository) GetSubtasks(parentID uuid.UUID) []*domain.Todo {
+	defer r.observe("GetSubtasks")()
+	return r.next.GetSubtasks(parentID)
+}
+
+func (r *todoRepository) GetOverdue() []*domain.Todo {
+	defer r.observe("GetOverdue")()
+	return r.next.GetOverdue()
+}
+
+func (r *todoRepository) GetUpcoming(days int) []*domain.Todo {
+	defer r.observe("GetUpcoming")()
+	return r.next.GetUpcoming(days)
+}
+
+func (r *todoRepository) Find(query domain.Query) []*domain.Todo {
+	defer r.observe("Find")()
+	return r.next.Find(query)
+}
+
+func (r *todoHistoryRepository) TodosAt(at time.Time) ([]*domain.Todo, error) {
+	defer r.observe("TodosAt")()
+	return r.history.TodosAt(at)
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// The states todos are counted in; each todo is in one of them
+const (
+	stateOpen      = "open"
+	stateOverdue   = "overdue"
+	stateCompleted = "completed"
+	stateArchived  = "archived"
+)
+
+// todoCollector counts the todos by state each time the metrics are read
+type todoCollector struct {
+	todos domain.TodoRepository
+	desc  *prometheus.Desc
+	now   func() time.Time
+}
+
+// CountTodos counts the todos of the repository by state: archived, completed, overdue, which are open todos
+// past their due date, and open
+func (m *Metrics) CountTodos(todos domain.TodoRepository) {
+	m.registry.MustRegister(&todoCollector{
+		todos: todos,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "todos"),
+			"Todos by state: open, overdue, completed or archived.",
+			[]string{"state"}, nil,
+		),
+		now: time.Now,
+	})
+}
+
+func (c *todoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *todoCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := map[string]int{stateOpen: 0, stateOverdue: 0, stateCompleted: 0, stateArchived: 0}
+	now := c.now()
+	for _, todo := range c.todos.All() {
+		counts[todoState(todo, now)]++
+	}
+	for state, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), state)
+	}
+}
+
+func todoState(todo *domain.Todo, now time.Time) string {
+	switch {
+	case todo.Archived:
+		return stateArchived
+	case todo.Completed:
+		return stateCompleted
+	case todo.DueDate != nil && todo.DueDate.Before(now):
+		return stateOverdue
+	default:
+		return stateOpen
+	}
+}
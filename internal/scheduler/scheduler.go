@@ -0,0 +1,75 @@
+// Package scheduler runs the jobs the server does in the background, such as bringing back snoozed todos
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job is work done on a schedule; an error is logged and the job runs again at its next time
+type Job func(ctx context.Context) error
+
+// Scheduler runs each of its jobs at a fixed interval
+type Scheduler struct {
+	logger  *log.Logger
+	jobs    []job
+	running atomic.Bool
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+func New(logger *log.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Every adds a job run when the scheduler starts and then after each interval
+func (s *Scheduler) Every(interval time.Duration, name string, run Job) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Run runs the jobs until the context ends, returning once none of them are running
+//
+// A job is never run again while it is still running; a run that takes longer than the interval delays the next.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// Running reports whether Run has started and not yet returned
+func (s *Scheduler) Running() bool {
+	return s.running.Load()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.run(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Printf("%s: %v", j.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	var logs bytes.Buffer
+	s := New(log.New(&logs, "", 0))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs, failures atomic.Int32
+	s.Every(10*time.Millisecond, "counting", func(context.Context) error {
+		// a second failure means the first was logged
+		if runs.Add(1) >= 3 && failures.Load() >= 2 {
+			cancel()
+		}
+		return nil
+	})
+	s.Every(10*time.Millisecond, "failing", func(context.Context) error {
+		failures.Add(1)
+		if !s.Running() {
+			t.Error("Running() = false while the jobs run")
+		}
+		return errors.New("out of coffee")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return once the context ended")
+	}
+
+	if s.Running() {
+		t.Error("Running() = true after Run() returned")
+	}
+	if runs.Load() < 3 || failures.Load() < 2 {
+		t.Errorf("runs = %d and failures = %d, want each job run again", runs.Load(), failures.Load())
+	}
+	if !strings.Contains(logs.String(), "failing: out of coffee") {
+		t.Errorf("logs = %q, want the failure logged", logs.String())
+	}
+}
@@ -16,7 +16,7 @@ import (
 	"github.com/stackus/todos/internal/templates/shared"
 )
 
-func HomePage(todos []*domain.Todo) templ.Component {
+func HomePage(todos []*domain.Todo, next string, order domain.SortOrder, sidebar partials.Sidebar) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -37,6 +37,16 @@ func HomePage(todos []*domain.Todo) templ.Component {
 				defer templ.ReleaseBuffer(templBuffer)
 			}
 			// TemplElement
+			err = partials.SidebarNav(sidebar).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
 			err = partials.Search("").Render(ctx, templBuffer)
 			if err != nil {
 				return err
@@ -47,7 +57,17 @@ func HomePage(todos []*domain.Todo) templ.Component {
 				return err
 			}
 			// TemplElement
-			err = partials.RenderTodos(todos).Render(ctx, templBuffer)
+			err = partials.SortTodos("/", order).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.RenderTodos(todos, next).Render(ctx, templBuffer)
 			if err != nil {
 				return err
 			}
@@ -61,6 +81,49 @@ func HomePage(todos []*domain.Todo) templ.Component {
 			if err != nil {
 				return err
 			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// Element (standard)
+			_, err = templBuffer.WriteString("<a")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" href=\"/todos/snoozed\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" class=\"block mt-4 text-sm\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// Text
+			var_3 := `Snoozed todos`
+			_, err = templBuffer.WriteString(var_3)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a>")
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.TransferLinks("").Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
 			if !templIsBuffer {
 				_, err = io.Copy(w, templBuffer)
 			}
@@ -76,3 +139,93 @@ func HomePage(todos []*domain.Todo) templ.Component {
 		return err
 	})
 }
+
+func SmartListPage(title, path string, todos []*domain.Todo, next string, order domain.SortOrder, sidebar partials.Sidebar) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		var_4 := templ.GetChildren(ctx)
+		if var_4 == nil {
+			var_4 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		// TemplElement
+		var_5 := templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+			templBuffer, templIsBuffer := w.(*bytes.Buffer)
+			if !templIsBuffer {
+				templBuffer = templ.GetBuffer()
+				defer templ.ReleaseBuffer(templBuffer)
+			}
+			// TemplElement
+			err = partials.SidebarNav(sidebar).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// Element (standard)
+			_, err = templBuffer.WriteString("<h2")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" class=\"text-lg font-bold\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// StringExpression
+			var var_6 string = title
+			_, err = templBuffer.WriteString(templ.EscapeString(var_6))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</h2>")
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.SortTodos(path, order).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.RenderTodos(todos, next).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			if !templIsBuffer {
+				_, err = io.Copy(w, templBuffer)
+			}
+			return err
+		})
+		err = shared.Page(title).Render(templ.WithChildren(ctx, var_5), templBuffer)
+		if err != nil {
+			return err
+		}
+		if !templIsBuffer {
+			_, err = io.Copy(w, templBuffer)
+		}
+		return err
+	})
+}
@@ -11,12 +11,14 @@ import "bytes"
 
 // GoExpression
 import (
-	"github.com/stackus/todos/internal/domain"
+	"net/url"
+
+	"github.com/stackus/todos/internal/fulltext"
 	"github.com/stackus/todos/internal/templates/partials"
 	"github.com/stackus/todos/internal/templates/shared"
 )
 
-func TodosPage(todos []*domain.Todo, term string) templ.Component {
+func TodosPage(results []fulltext.Result, term string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
 		if !templIsBuffer {
@@ -47,10 +49,59 @@ func TodosPage(todos []*domain.Todo, term string) templ.Component {
 				return err
 			}
 			// TemplElement
-			err = partials.RenderTodos(todos).Render(ctx, templBuffer)
+			err = partials.RenderSearchResults(results).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
 			if err != nil {
 				return err
 			}
+			// If
+			if term != "" {
+				// Element (standard)
+				_, err = templBuffer.WriteString("<a")
+				if err != nil {
+					return err
+				}
+				// Element Attributes
+				_, err = templBuffer.WriteString(" href=")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
+				var var_3 templ.SafeURL = templ.SafeURL("/views?" + url.Values{"search": {term}}.Encode())
+				_, err = templBuffer.WriteString(templ.EscapeString(string(var_3)))
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(" class=\"block text-sm\"")
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString(">")
+				if err != nil {
+					return err
+				}
+				// Text
+				var_4 := `Save this search as a view`
+				_, err = templBuffer.WriteString(var_4)
+				if err != nil {
+					return err
+				}
+				_, err = templBuffer.WriteString("</a>")
+				if err != nil {
+					return err
+				}
+			}
 			// Whitespace (normalised)
 			_, err = templBuffer.WriteString(` `)
 			if err != nil {
@@ -61,6 +112,49 @@ func TodosPage(todos []*domain.Todo, term string) templ.Component {
 			if err != nil {
 				return err
 			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// Element (standard)
+			_, err = templBuffer.WriteString("<a")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" href=\"/todos/snoozed\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" class=\"block mt-4 text-sm\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// Text
+			var_5 := `Snoozed todos`
+			_, err = templBuffer.WriteString(var_5)
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a>")
+			if err != nil {
+				return err
+			}
+			// Whitespace (normalised)
+			_, err = templBuffer.WriteString(` `)
+			if err != nil {
+				return err
+			}
+			// TemplElement
+			err = partials.TransferLinks(term).Render(ctx, templBuffer)
+			if err != nil {
+				return err
+			}
 			if !templIsBuffer {
 				_, err = io.Copy(w, templBuffer)
 			}
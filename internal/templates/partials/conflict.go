@@ -0,0 +1,14 @@
+package partials
+
+// TodoChange is a change to a todo that could not be saved because the todo was changed first
+type TodoChange struct {
+	Completed   bool
+	Description string
+}
+
+func doneLabel(completed bool) string {
+	if completed {
+		return "done"
+	}
+	return "not done"
+}
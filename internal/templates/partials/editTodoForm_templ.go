@@ -11,6 +11,8 @@ import "bytes"
 
 // GoExpression
 import (
+	"strconv"
+
 	"github.com/stackus/todos/internal/domain"
 )
 
@@ -260,6 +262,40 @@ func EditTodoForm(todo *domain.Todo) templ.Component {
 			return err
 		}
 		// Element Attributes
+		_, err = templBuffer.WriteString(" type=\"hidden\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" name=\"version\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" value=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(strconv.Itoa(todo.Version)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<input")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
 		_, err = templBuffer.WriteString(" type=\"submit\"")
 		if err != nil {
 			return err
@@ -0,0 +1,60 @@
+package partials
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// FocusView is what the focus page shows to the user focusing
+type FocusView struct {
+	// Session is the latest session of the user, nil when they have never focused
+	Session *domain.FocusSession
+	// Todo is the todo of the session
+	Todo *domain.Todo
+	// Todos can be chosen to focus on; Selected is the ID of the one chosen already
+	Todos    []*domain.Todo
+	Selected string
+	// Settings fill the form starting a session
+	Settings domain.FocusSettings
+	// Day is summarised by Pomodoros, Focused and Summary
+	Day       time.Time
+	Pomodoros int
+	Focused   time.Duration
+	Summary   []FocusSummaryLine
+	Now       time.Time
+}
+
+// FocusSummaryLine is the focus given to one todo during the day
+type FocusSummaryLine struct {
+	Todo      *domain.Todo
+	Pomodoros int
+	Focused   time.Duration
+}
+
+// Active returns true while the user has a session running
+func (v FocusView) Active() bool {
+	return v.Session != nil && v.Session.Active()
+}
+
+func focusPhaseLabel(phase domain.FocusPhase) string {
+	switch phase {
+	case domain.FocusShortBreak:
+		return "Short break"
+	case domain.FocusLongBreak:
+		return "Long break"
+	default:
+		return "Pomodoro"
+	}
+}
+
+// focusClock writes the time left as minutes and seconds
+func focusClock(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return strconv.Itoa(seconds/60) + ":" + strconv.Itoa(seconds%60/10) + strconv.Itoa(seconds%10)
+}
+
+func minutes(d time.Duration) string {
+	return strconv.Itoa(int(d.Minutes()))
+}
@@ -0,0 +1,102 @@
+package partials
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// HabitLine is how well a recurring todo has been kept up
+type HabitLine struct {
+	Todo    *domain.Todo
+	Current int
+	Longest int
+	// Missed is how many periods passed without the todo being completed
+	Missed int
+}
+
+// habitHistory returns the periods the heatmap of a recurring todo shows
+//
+// Daily history starts on a Monday so each column of the heatmap is a week.
+func habitHistory(todo *domain.Todo, now time.Time) []domain.HabitDay {
+	switch strings.ToLower(todo.Recurring.Frequency) {
+	case "weekly":
+		return todo.HabitHistory(now, 52)
+	case "monthly":
+		return todo.HabitHistory(now, 24)
+	case "yearly":
+		return todo.HabitHistory(now, 10)
+	default:
+		return todo.HabitHistory(now, 26*7+(int(now.Weekday())+6)%7+1)
+	}
+}
+
+func habitDaily(todo *domain.Todo) bool {
+	return !strings.EqualFold(todo.Recurring.Frequency, "weekly") &&
+		!strings.EqualFold(todo.Recurring.Frequency, "monthly") &&
+		!strings.EqualFold(todo.Recurring.Frequency, "yearly")
+}
+
+func habitDayClass(day domain.HabitDay) string {
+	switch {
+	case day.Done > 1:
+		return "bg-green-800"
+	case day.Done == 1:
+		return "bg-green-600"
+	case day.Missed:
+		return "bg-red-300"
+	default:
+		return "bg-gray-200"
+	}
+}
+
+func habitDayTitle(todo *domain.Todo, day domain.HabitDay) string {
+	var label string
+	switch strings.ToLower(todo.Recurring.Frequency) {
+	case "weekly":
+		label = "Week of " + day.Start.Format("Jan 2, 2006")
+	case "monthly":
+		label = day.Start.Format("January 2006")
+	case "yearly":
+		label = day.Start.Format("2006")
+	default:
+		label = day.Start.Format("Mon, Jan 2, 2006")
+	}
+	switch {
+	case day.Done > 0:
+		return label + ": done"
+	case day.Missed:
+		return label + ": missed"
+	default:
+		return label
+	}
+}
+
+func habitPeriod(todo *domain.Todo, n int) string {
+	var unit string
+	switch strings.ToLower(todo.Recurring.Frequency) {
+	case "weekly":
+		unit = "week"
+	case "monthly":
+		unit = "month"
+	case "yearly":
+		unit = "year"
+	default:
+		unit = "day"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return strconv.Itoa(n) + " " + unit
+}
+
+func streakText(todo *domain.Todo, now time.Time) string {
+	current, longest := todo.Streak(now)
+	text := "Current streak " + habitPeriod(todo, current) + ", longest " + habitPeriod(todo, longest)
+	if missed := len(todo.MissedOccurrences(now)); missed > 0 {
+		text += ", " + habitPeriod(todo, missed) + " missed"
+	}
+	return text
+}
@@ -0,0 +1,147 @@
+package partials
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/identity"
+	"github.com/stackus/todos/internal/markdown"
+)
+
+// ImportColumn is a todo field that can be read from a column of imported CSV content
+type ImportColumn struct {
+	// Field is the name of the form field holding the chosen column
+	Field    string
+	Label    string
+	Required bool
+	// Header is the column currently chosen
+	Header string
+}
+
+func priorityLabel(priority domain.Priority) string {
+	switch priority {
+	case domain.PriorityHigh:
+		return "High"
+	case domain.PriorityLow:
+		return "Low"
+	default:
+		return "Medium"
+	}
+}
+
+func exportURL(format string, search string) templ.SafeURL {
+	u := "/export/" + format
+	if search != "" {
+		u += "?" + url.Values{"search": {search}}.Encode()
+	}
+	return templ.SafeURL(u)
+}
+
+func commentPath(todo *domain.Todo, comment domain.Comment) string {
+	return "/todos/" + todo.ID.String() + "/comments/" + comment.ID.String()
+}
+
+// renderMarkdown writes Markdown source as sanitized HTML
+func renderMarkdown(src string) templ.Component {
+	return rawHTML(markdown.Render(src))
+}
+
+// renderComment writes the Markdown of a comment as sanitized HTML, linking the users it mentions
+func renderComment(comment domain.Comment, viewer Viewer) templ.Component {
+	return rawHTML(viewer.markdown().Render(comment.Content))
+}
+
+// renderMarkdownInline writes Markdown source as sanitized HTML that fits within a line
+func renderMarkdownInline(src string) templ.Component {
+	return rawHTML(markdown.RenderInline(src))
+}
+
+func rawHTML(html string) templ.Component {
+	return templ.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, html)
+		return err
+	})
+}
+
+// notificationURL links to what the notification is about
+func notificationURL(notification *domain.Notification) templ.SafeURL {
+	u := "/todos/" + notification.TodoID.String()
+	if notification.CommentID != nil {
+		u += "#comments"
+	}
+	return templ.SafeURL(u)
+}
+
+// FormatSize writes a number of bytes the way people read file sizes
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return strconv.FormatInt(size, 10) + " B"
+	}
+	value, suffix := float64(size)/unit, "KiB"
+	for _, next := range []string{"MiB", "GiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64) + " " + suffix
+}
+
+func attachmentPath(todo *domain.Todo, attachment domain.Attachment) string {
+	return "/todos/" + todo.ID.String() + "/attachments/" + attachment.ID.String()
+}
+
+// TimeTotal is a line of a time report
+type TimeTotal struct {
+	Label string
+	// Todo is set on the lines of todos, which link to them
+	Todo     *domain.Todo
+	Duration time.Duration
+}
+
+// formatDuration writes a duration in hours and minutes, such as 1h 05m or 25m
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return strconv.Itoa(minutes) + "m"
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// runningTimer returns the timer the user viewing the page has running on the todo, or nil when there is none
+func runningTimer(ctx context.Context, todo *domain.Todo) *domain.TimeEntry {
+	return todo.RunningTimer(identity.UserID(ctx))
+}
+
+// timeSpent writes the time spent on the todo, out of its estimate when it has one
+func timeSpent(todo *domain.Todo) string {
+	spent := formatDuration(todo.TimeSpent(time.Now()))
+	if todo.Estimate == 0 {
+		return spent
+	}
+	return spent + " / " + formatDuration(todo.Estimate)
+}
+
+func timeEntryPath(todo *domain.Todo, entry domain.TimeEntry) string {
+	return "/todos/" + todo.ID.String() + "/time/" + entry.ID.String()
+}
+
+// formatSnooze writes when a snoozed todo comes back, leaving out the date when it is today
+func formatSnooze(until time.Time) string {
+	now := time.Now()
+	if y, m, d := until.Date(); y == now.Year() && m == now.Month() && d == now.Day() {
+		return "today at " + until.Format("15:04")
+	}
+	if until.Year() == now.Year() {
+		return until.Format("Mon, Jan 2 at 15:04")
+	}
+	return until.Format("Mon, Jan 2, 2006 at 15:04")
+}
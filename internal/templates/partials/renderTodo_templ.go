@@ -11,6 +11,8 @@ import "bytes"
 
 // GoExpression
 import (
+	"strconv"
+
 	"github.com/stackus/todos/internal/domain"
 )
 
@@ -346,6 +348,40 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<input")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" type=\"hidden\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" name=\"version\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" value=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(strconv.Itoa(todo.Version)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
 		// Element (standard)
 		_, err = templBuffer.WriteString("<noscript>")
 		if err != nil {
@@ -408,13 +444,16 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		_, err = templBuffer.WriteString(" data-toggle-todo")
+		if err != nil {
+			return err
+		}
 		_, err = templBuffer.WriteString(">")
 		if err != nil {
 			return err
 		}
-		// StringExpression
-		var var_5 string = todo.Description
-		_, err = templBuffer.WriteString(templ.EscapeString(var_5))
+		// TemplElement
+		err = renderMarkdownInline(todo.Description).Render(ctx, templBuffer)
 		if err != nil {
 			return err
 		}
@@ -426,6 +465,129 @@ func RenderTodo(todo *domain.Todo) templ.Component {
 		if err != nil {
 			return err
 		}
+		// If
+		if len(todo.Comments) > 0 {
+			// Element (standard)
+			_, err = templBuffer.WriteString("<a")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" href=")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			var var_5 templ.SafeURL = templ.SafeURL("/todos/" + todo.ID.String() + "#comments")
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_5)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" title=\"Comments\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" class=\"ml-2 text-sm\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// Text
+			var_6 := `💬 `
+			_, err = templBuffer.WriteString(var_6)
+			if err != nil {
+				return err
+			}
+			// StringExpression
+			var var_7 string = strconv.Itoa(len(todo.Comments))
+			_, err = templBuffer.WriteString(templ.EscapeString(var_7))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a>")
+			if err != nil {
+				return err
+			}
+		}
+		// If
+		if len(todo.Pomodoros) > 0 {
+			// Element (standard)
+			_, err = templBuffer.WriteString("<a")
+			if err != nil {
+				return err
+			}
+			// Element Attributes
+			_, err = templBuffer.WriteString(" href=")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			var var_8 templ.SafeURL = templ.SafeURL("/focus?todo=" + todo.ID.String())
+			_, err = templBuffer.WriteString(templ.EscapeString(string(var_8)))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" title=\"Pomodoros\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(" class=\"ml-2 text-sm\"")
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString(">")
+			if err != nil {
+				return err
+			}
+			// Text
+			var_9 := `🍅 `
+			_, err = templBuffer.WriteString(var_9)
+			if err != nil {
+				return err
+			}
+			// StringExpression
+			var var_10 string = strconv.Itoa(len(todo.Pomodoros))
+			_, err = templBuffer.WriteString(templ.EscapeString(var_10))
+			if err != nil {
+				return err
+			}
+			_, err = templBuffer.WriteString("</a>")
+			if err != nil {
+				return err
+			}
+		}
+		// TemplElement
+		err = TimerButton(todo).Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// TemplElement
+		err = SnoozeButton(todo).Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// Children
+		err = var_1.Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
 		// Element (void)
 		_, err = templBuffer.WriteString("<input")
 		if err != nil {
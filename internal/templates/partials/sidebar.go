@@ -0,0 +1,18 @@
+package partials
+
+// Sidebar holds the smart lists and the views the user pinned
+type Sidebar struct {
+	Lists []SidebarLink
+	Views []SidebarLink
+	// All is true when every todo is shown rather than a list or view
+	All bool
+}
+
+// SidebarLink is an entry of the sidebar
+type SidebarLink struct {
+	URL   string
+	Title string
+	Count int
+	// Selected is true for the list or view being shown
+	Selected bool
+}
@@ -0,0 +1,52 @@
+package partials
+
+import (
+	"strings"
+
+	"github.com/stackus/todos/internal/domain"
+)
+
+// sortLabel describes a sort order, such as "Priority, then due date (reversed)"
+func sortLabel(order domain.SortOrder) string {
+	if order == domain.SortManual {
+		return "Manual"
+	}
+
+	keys := strings.Split(string(order), ",")
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		var label string
+		switch domain.SortOrder(strings.TrimPrefix(key, "-")) {
+		case domain.SortDueDate:
+			label = "Due date"
+		case domain.SortPriority:
+			label = "Priority"
+		case domain.SortCreated:
+			label = "Newest"
+		case domain.SortUpdated:
+			label = "Recently updated"
+		case domain.SortDescription:
+			label = "Description"
+		default:
+			label = "Manual"
+		}
+		if strings.HasPrefix(key, "-") {
+			label += " (reversed)"
+		}
+		if i > 0 {
+			label = "then " + strings.ToLower(label)
+		}
+		labels[i] = label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// sortChoices are the sort orders offered to pick from, along with the one in use when it is not among them
+func sortChoices(order domain.SortOrder) []domain.SortOrder {
+	for _, choice := range domain.SortOrders {
+		if choice == order {
+			return domain.SortOrders
+		}
+	}
+	return append(append([]domain.SortOrder{}, domain.SortOrders...), order)
+}
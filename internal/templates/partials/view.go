@@ -0,0 +1,31 @@
+package partials
+
+import (
+	"github.com/stackus/todos/internal/domain"
+)
+
+// ViewForm holds the fields of the form saving a search as a view
+type ViewForm struct {
+	// Action is where the form is posted
+	Action    string
+	Name      string
+	Search    string
+	Tags      string
+	Category  string
+	Priority  string
+	DueFrom   string
+	DueTo     string
+	Assignee  string
+	Completed string
+	Archived  bool
+	Sort      string
+	Pinned    bool
+	// Me is the ID of the user filling in the form, offered as the assignee
+	Me string
+	// Problem explains why the form was not saved
+	Problem string
+}
+
+func viewPath(view *domain.View) string {
+	return "/views/" + view.ID.String()
+}
@@ -0,0 +1,43 @@
+package partials
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/stackus/todos/internal/domain"
+	"github.com/stackus/todos/internal/markdown"
+)
+
+// Viewer is the user reading a page, along with the users the page may name
+type Viewer struct {
+	UserID uuid.UUID
+	Users  []*domain.User
+}
+
+// Name names the user from the point of view of the viewer
+func (v Viewer) Name(userID uuid.UUID) string {
+	if userID == v.UserID {
+		return "You"
+	}
+	for _, user := range v.Users {
+		if user.ID == userID {
+			return user.DisplayName()
+		}
+	}
+	return "User " + userID.String()[:8]
+}
+
+// markdown links the @mentions of known users to their pages
+func (v Viewer) markdown() markdown.Options {
+	return markdown.Options{Mention: func(username string) (string, bool) {
+		username, ok := domain.NormalizeUsername(username)
+		if !ok {
+			return "", false
+		}
+		for _, user := range v.Users {
+			if user.Username == username {
+				return "/users/" + user.Username, true
+			}
+		}
+		return "", false
+	}}
+}
@@ -0,0 +1,5 @@
+package shared
+
+// htmxConfig keeps htmx within the Content-Security-Policy: it neither evaluates script in attributes nor
+// adds a style element of its own
+const htmxConfig = `{"allowEval":false,"includeIndicatorStyles":false}`
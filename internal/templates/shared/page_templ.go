@@ -9,6 +9,12 @@ import "context"
 import "io"
 import "bytes"
 
+// GoExpression
+import (
+	"github.com/stackus/todos/internal/assets"
+	"github.com/stackus/todos/internal/csp"
+)
+
 func Page(title string) templ.Component {
 	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
 		templBuffer, templIsBuffer := w.(*bytes.Buffer)
@@ -93,7 +99,19 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" href=\"/dist/favicon.svg\"")
+		_, err = templBuffer.WriteString(" href=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(assets.Path("favicon.svg")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
@@ -125,6 +143,42 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Element Attributes
+		_, err = templBuffer.WriteString(" name=\"theme-color\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" content=\"#f8e71c\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<link")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" rel=\"manifest\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" href=\"/dist/manifest.webmanifest\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<meta")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
 		_, err = templBuffer.WriteString(" name=\"robots\"")
 		if err != nil {
 			return err
@@ -173,21 +227,141 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// Element (void)
+		_, err = templBuffer.WriteString("<meta")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/htmx.org@1.9.2\"")
+		_, err = templBuffer.WriteString(" name=\"htmx-config\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" content=")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" integrity=\"sha384-L6OqL9pRWyyFU3+/bjdSri+iIphTN/bvYyM37tICVyOJkWZLpP2vGn6VUEXgzg6h\"")
+		_, err = templBuffer.WriteString("\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" crossorigin=\"anonymous\"")
+		_, err = templBuffer.WriteString(templ.EscapeString(htmxConfig))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// TemplElement
+		err = script("vendor/htmx.min.js").Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// TemplElement
+		err = script("vendor/_hyperscript.min.js").Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// TemplElement
+		err = script("vendor/Sortable.min.js").Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// TemplElement
+		err = script("app.js").Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		// Element (void)
+		_, err = templBuffer.WriteString("<link")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" rel=\"stylesheet\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" href=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(assets.Path("styles.css")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" integrity=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(assets.Integrity("styles.css")))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</head>")
+		if err != nil {
+			return err
+		}
+		// Element (standard)
+		_, err = templBuffer.WriteString("<body")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" class=\"h-full bg-yellow-50 font-mono\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (standard)
+		_, err = templBuffer.WriteString("<section")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" class=\"max-w-lg mx-auto my-2\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (standard)
+		_, err = templBuffer.WriteString("<h1")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" class=\"text-8xl font-black text-center m-0 pb-2\"")
 		if err != nil {
 			return err
 		}
@@ -196,22 +370,36 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_3 := ``
+		var_3 := `Todos`
 		_, err = templBuffer.WriteString(var_3)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString("</h1>")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// Element (standard)
+		_, err = templBuffer.WriteString("<nav")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/hyperscript.org@0.9.8\"")
+		_, err = templBuffer.WriteString(" class=\"flex justify-center gap-4 mb-2 text-sm\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Element (standard)
+		_, err = templBuffer.WriteString("<a")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" href=\"/\"")
 		if err != nil {
 			return err
 		}
@@ -220,22 +408,22 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_4 := ``
+		var_4 := `Todos`
 		_, err = templBuffer.WriteString(var_4)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString("</a>")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// Element (standard)
+		_, err = templBuffer.WriteString("<a")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"https://unpkg.com/sortablejs@1.15.0\"")
+		_, err = templBuffer.WriteString(" href=\"/inbox\"")
 		if err != nil {
 			return err
 		}
@@ -244,22 +432,22 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_5 := ``
+		var_5 := `Inbox`
 		_, err = templBuffer.WriteString(var_5)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString("</a>")
 		if err != nil {
 			return err
 		}
-		// RawElement
-		_, err = templBuffer.WriteString("<script")
+		// Element (standard)
+		_, err = templBuffer.WriteString("<a")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" src=\"/dist/app.js\"")
+		_, err = templBuffer.WriteString(" href=\"/time\"")
 		if err != nil {
 			return err
 		}
@@ -268,44 +456,46 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_6 := ``
+		var_6 := `Time`
 		_, err = templBuffer.WriteString(var_6)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</script>")
+		_, err = templBuffer.WriteString("</a>")
 		if err != nil {
 			return err
 		}
-		// Element (void)
-		_, err = templBuffer.WriteString("<link")
+		// Element (standard)
+		_, err = templBuffer.WriteString("<a")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" rel=\"stylesheet\"")
+		_, err = templBuffer.WriteString(" href=\"/focus\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(" href=\"/dist/styles.css\"")
+		_, err = templBuffer.WriteString(">")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString(">")
+		// Text
+		var_7 := `Focus`
+		_, err = templBuffer.WriteString(var_7)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</head>")
+		_, err = templBuffer.WriteString("</a>")
 		if err != nil {
 			return err
 		}
 		// Element (standard)
-		_, err = templBuffer.WriteString("<body")
+		_, err = templBuffer.WriteString("<a")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" class=\"h-full bg-yellow-50 font-mono\"")
+		_, err = templBuffer.WriteString(" href=\"/habits\"")
 		if err != nil {
 			return err
 		}
@@ -313,13 +503,23 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
+		// Text
+		var_8 := `Habits`
+		_, err = templBuffer.WriteString(var_8)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</a>")
+		if err != nil {
+			return err
+		}
 		// Element (standard)
-		_, err = templBuffer.WriteString("<section")
+		_, err = templBuffer.WriteString("<a")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" class=\"max-w-lg mx-auto my-2\"")
+		_, err = templBuffer.WriteString(" href=\"/users/me\"")
 		if err != nil {
 			return err
 		}
@@ -327,13 +527,48 @@ func Page(title string) templ.Component {
 		if err != nil {
 			return err
 		}
+		// Text
+		var_9 := `Profile`
+		_, err = templBuffer.WriteString(var_9)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</a>")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</nav>")
+		if err != nil {
+			return err
+		}
+		// Children
+		err = var_1.Render(ctx, templBuffer)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</section>")
+		if err != nil {
+			return err
+		}
 		// Element (standard)
-		_, err = templBuffer.WriteString("<h1")
+		_, err = templBuffer.WriteString("<div")
 		if err != nil {
 			return err
 		}
 		// Element Attributes
-		_, err = templBuffer.WriteString(" class=\"text-8xl font-black text-center m-0 pb-2\"")
+		_, err = templBuffer.WriteString(" id=\"offline-banner\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" class=\"fixed bottom-0 inset-x-0 bg-black text-white text-center text-sm py-2\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" hidden")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" role=\"status\"")
 		if err != nil {
 			return err
 		}
@@ -342,21 +577,30 @@ func Page(title string) templ.Component {
 			return err
 		}
 		// Text
-		var_7 := `Todos`
-		_, err = templBuffer.WriteString(var_7)
+		var_10 := `You are offline. `
+		_, err = templBuffer.WriteString(var_10)
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</h1>")
+		// Element (standard)
+		_, err = templBuffer.WriteString("<span")
 		if err != nil {
 			return err
 		}
-		// Children
-		err = var_1.Render(ctx, templBuffer)
+		// Element Attributes
+		_, err = templBuffer.WriteString(" id=\"offline-queued\"")
 		if err != nil {
 			return err
 		}
-		_, err = templBuffer.WriteString("</section>")
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</span>")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</div>")
 		if err != nil {
 			return err
 		}
@@ -374,3 +618,95 @@ func Page(title string) templ.Component {
 		return err
 	})
 }
+
+// GoExpression
+// script loads an embedded script by its fingerprinted path, checked against its hash and allowed by the
+// nonce of the request
+
+func script(name string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) (err error) {
+		templBuffer, templIsBuffer := w.(*bytes.Buffer)
+		if !templIsBuffer {
+			templBuffer = templ.GetBuffer()
+			defer templ.ReleaseBuffer(templBuffer)
+		}
+		ctx = templ.InitializeContext(ctx)
+		var_11 := templ.GetChildren(ctx)
+		if var_11 == nil {
+			var_11 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		// RawElement
+		_, err = templBuffer.WriteString("<script")
+		if err != nil {
+			return err
+		}
+		// Element Attributes
+		_, err = templBuffer.WriteString(" src=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(assets.Path(name)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" integrity=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(assets.Integrity(name)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(" nonce=")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(templ.EscapeString(csp.Nonce(ctx)))
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("\"")
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString(">")
+		if err != nil {
+			return err
+		}
+		// Text
+		var_12 := ``
+		_, err = templBuffer.WriteString(var_12)
+		if err != nil {
+			return err
+		}
+		_, err = templBuffer.WriteString("</script>")
+		if err != nil {
+			return err
+		}
+		if !templIsBuffer {
+			_, err = io.Copy(w, templBuffer)
+		}
+		return err
+	})
+}